@@ -0,0 +1,94 @@
+// Package gitexec is how foundagent runs git. Every git command foundagent
+// runs goes through the executor set with Set, after the command's timeout
+// and environment have been applied, so an executor other than the default
+// Subprocess, such as the scripted fake in package gittest, sees exactly
+// what git would have been asked to do.
+package gitexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// cancelWaitDelay is how long a cancelled git command gets to exit after
+// being interrupted, before it is killed
+const cancelWaitDelay = 5 * time.Second
+
+// Executor runs git processes
+type Executor interface {
+	// Run runs git with the invocation's arguments and waits for it to
+	// finish, stopping it when ctx is done. A git that runs and exits with a
+	// non-zero code fails with an error that has an ExitCode() int method,
+	// such as *ExitError.
+	Run(ctx context.Context, inv Invocation) error
+}
+
+// Invocation is one run of git
+type Invocation struct {
+	Args   []string // Arguments after "git"
+	Dir    string   // Working directory (empty = the current directory)
+	Env    []string // Variables set on top of the environment of fa
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExitError reports that git exited with a non-zero code. Subprocess
+// returns *exec.ExitError instead, which has the same ExitCode method.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the code git exited with
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// Subprocess is the default executor, which runs the git binary in PATH
+type Subprocess struct{}
+
+// Run runs git as a subprocess
+func (Subprocess) Run(ctx context.Context, inv Invocation) error {
+	cmd := exec.CommandContext(ctx, "git", inv.Args...)
+	cmd.Dir = inv.Dir
+	cmd.Stdout = inv.Stdout
+	cmd.Stderr = inv.Stderr
+	if len(inv.Env) > 0 {
+		cmd.Env = append(os.Environ(), inv.Env...)
+	}
+	// Interrupt rather than kill, so git can remove its lock files and
+	// partial clones; Windows cannot deliver an interrupt to a process
+	cmd.Cancel = func() error {
+		if runtime.GOOS == "windows" {
+			return cmd.Process.Kill()
+		}
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = cancelWaitDelay
+	return cmd.Run()
+}
+
+// executor runs git commands started from now on
+var executor Executor = Subprocess{}
+
+// Set sets the executor that git commands started from now on run through.
+// nil restores the default Subprocess.
+func Set(e Executor) {
+	if e == nil {
+		e = Subprocess{}
+	}
+	executor = e
+}
+
+// Current returns the executor git commands started now run through
+func Current() Executor {
+	return executor
+}
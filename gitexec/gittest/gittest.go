@@ -0,0 +1,271 @@
+// Package gittest provides a fake git executor for tests and harnesses. A
+// Fake answers git commands from a script of rules instead of running git,
+// and records every command it was asked to run, so failures such as
+// rejected credentials, rejected pushes and remotes that stop answering can
+// be simulated without a network or real repositories.
+//
+//	fake := gittest.New()
+//	fake.On("push").NonFastForward()
+//	fake.On("fetch").Times(1).TransientFailure()
+//	t.Cleanup(fake.Install())
+package gittest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/foundagent/foundagent/gitexec"
+)
+
+// Fake is a gitexec.Executor that answers commands from its rules. Commands no
+// rule matches are passed to Fallback, or fail with exit code 1 if it is
+// nil. A Fake is safe for concurrent use.
+type Fake struct {
+	// Fallback runs the commands no rule matches, such as gitexec.Subprocess{}
+	// to fake only the commands that reach a remote
+	Fallback gitexec.Executor
+
+	mu    sync.Mutex
+	rules []*Rule
+	calls []Call
+}
+
+// Call is a command a Fake was asked to run
+type Call struct {
+	Args []string
+	Dir  string
+	Env  []string
+}
+
+// String returns the command line of the call
+func (c Call) String() string {
+	return "git " + strings.Join(c.Args, " ")
+}
+
+// Rule says how a Fake answers the commands that match it. Its methods
+// return the rule, so they can be chained.
+type Rule struct {
+	args   []string
+	stdout string
+	stderr string
+	code   int
+	delay  time.Duration
+	times  int // Commands left to answer (0 = any number)
+	run    func(ctx context.Context, inv gitexec.Invocation) error
+}
+
+// New returns a fake with no rules
+func New() *Fake {
+	return &Fake{}
+}
+
+// Install makes f the executor git commands run through, and returns a
+// function that restores the executor it replaced
+func (f *Fake) Install() (restore func()) {
+	previous := gitexec.Current()
+	gitexec.Set(f)
+	return func() {
+		gitexec.Set(previous)
+	}
+}
+
+// On adds a rule for the commands whose arguments, from the git subcommand
+// on, start with args. On("push") matches every push, and
+// On("rev-parse", "--abbrev-ref") only those rev-parses. With no args the
+// rule matches every command. Rules are tried in the order they were added,
+// so a rule limited with Times can be followed by one for later commands.
+// A rule answers with success and no output until told otherwise.
+func (f *Fake) On(args ...string) *Rule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rule := &Rule{args: args}
+	f.rules = append(f.rules, rule)
+	return rule
+}
+
+// Calls returns the commands the fake was asked to run, in order
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Count returns how many of the commands the fake was asked to run match
+// args, as in On
+func (f *Fake) Count(args ...string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, call := range f.calls {
+		if matches(call.Args, args) {
+			count++
+		}
+	}
+	return count
+}
+
+// Run answers a git command from the first rule that matches it
+func (f *Fake) Run(ctx context.Context, inv gitexec.Invocation) error {
+	rule, fallback := f.record(inv)
+	if rule == nil {
+		if fallback != nil {
+			return fallback.Run(ctx, inv)
+		}
+		if inv.Stderr != nil {
+			fmt.Fprintf(inv.Stderr, "gittest: no rule for git %s\n", strings.Join(inv.Args, " "))
+		}
+		return &gitexec.ExitError{Code: 1}
+	}
+	return rule.answer(ctx, inv)
+}
+
+// record records a call and returns the rule that answers it
+func (f *Fake) record(inv gitexec.Invocation) (*Rule, gitexec.Executor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{
+		Args: slices.Clone(inv.Args),
+		Dir:  inv.Dir,
+		Env:  slices.Clone(inv.Env),
+	})
+
+	for _, rule := range f.rules {
+		if rule.times < 0 || !matches(inv.Args, rule.args) {
+			continue
+		}
+		if rule.times > 0 {
+			rule.times--
+			if rule.times == 0 {
+				rule.times = -1 // Used up
+			}
+		}
+		return rule, f.Fallback
+	}
+	return nil, f.Fallback
+}
+
+// Stdout makes the rule write s to standard output
+func (r *Rule) Stdout(s string) *Rule {
+	r.stdout = s
+	return r
+}
+
+// Stderr makes the rule write s to standard error
+func (r *Rule) Stderr(s string) *Rule {
+	r.stderr = s
+	return r
+}
+
+// Exit makes the rule's commands exit with code
+func (r *Rule) Exit(code int) *Rule {
+	r.code = code
+	return r
+}
+
+// Delay makes the rule's commands take d before answering, like a slow
+// remote. A command whose timeout passes or that is cancelled first stops
+// waiting and fails.
+func (r *Rule) Delay(d time.Duration) *Rule {
+	r.delay = d
+	return r
+}
+
+// Times limits the rule to the next n commands it matches. Later commands
+// fall through to the rules after it.
+func (r *Rule) Times(n int) *Rule {
+	r.times = n
+	return r
+}
+
+// Do makes the rule answer by calling fn, after any delay, instead of
+// writing its output and exit code. fn can write to the invocation's Stdout
+// and Stderr, which may be nil.
+func (r *Rule) Do(fn func(ctx context.Context, inv gitexec.Invocation) error) *Rule {
+	r.run = fn
+	return r
+}
+
+// AuthFailure makes the rule fail the way git does when ssh rejects the
+// user's key
+func (r *Rule) AuthFailure() *Rule {
+	return r.Stderr("git@example.com: Permission denied (publickey).\n" +
+		"fatal: Could not read from remote repository.\n\n" +
+		"Please make sure you have the correct access rights\n" +
+		"and the repository exists.\n").Exit(128)
+}
+
+// NonFastForward makes the rule fail the way a push does when the remote
+// branch has commits the local one does not
+func (r *Rule) NonFastForward() *Rule {
+	return r.Stderr("To example.com:org/repo.git\n" +
+		" ! [rejected]        HEAD -> main (non-fast-forward)\n" +
+		"error: failed to push some refs to 'example.com:org/repo.git'\n" +
+		"hint: Updates were rejected because the tip of your current branch is behind\n" +
+		"hint: its remote counterpart.\n").Exit(1)
+}
+
+// TransientFailure makes the rule fail the way git does when the remote
+// answers with an HTTP 503, which is retried
+func (r *Rule) TransientFailure() *Rule {
+	return r.Stderr("fatal: unable to access 'https://example.com/org/repo.git/': " +
+		"The requested URL returned error: 503\n").Exit(128)
+}
+
+// answer answers a command
+func (r *Rule) answer(ctx context.Context, inv gitexec.Invocation) error {
+	if r.delay > 0 {
+		timer := time.NewTimer(r.delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if r.run != nil {
+		return r.run(ctx, inv)
+	}
+	write(inv.Stdout, r.stdout)
+	write(inv.Stderr, r.stderr)
+	if r.code != 0 {
+		return &gitexec.ExitError{Code: r.code}
+	}
+	return nil
+}
+
+func write(w io.Writer, s string) {
+	if w != nil && s != "" {
+		_, _ = io.WriteString(w, s)
+	}
+}
+
+// matches reports whether a command's arguments, from the subcommand on,
+// start with prefix
+func matches(args, prefix []string) bool {
+	args = args[subcommandIndex(args):]
+	return len(args) >= len(prefix) && slices.Equal(args[:len(prefix)], prefix)
+}
+
+// subcommandIndex returns the index of the git subcommand in args, skipping
+// global options such as -C <dir> and --git-dir=<dir>
+func subcommandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-C" || arg == "-c":
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return i
+		}
+	}
+	return len(args)
+}
@@ -0,0 +1,152 @@
+package gittest
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/foundagent/foundagent/gitexec"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, code, faErr.Code, faErr.Message)
+}
+
+func TestFake_AuthFailure(t *testing.T) {
+	fake := New()
+	fake.On("clone").AuthFailure()
+	t.Cleanup(fake.Install())
+
+	err := git.Clone(git.CloneOptions{URL: "git@example.com:org/api.git", TargetPath: "/ws/repos/api/.bare", Bare: true})
+	requireCode(t, err, errors.ErrCodeGitOperationFailed)
+	assert.Contains(t, err.Error(), "Failed to clone repository")
+
+	// Authentication failures are not retried
+	assert.Equal(t, 1, fake.Count("clone"))
+	assert.Equal(t, []string{"clone", "--bare", "--quiet", "git@example.com:org/api.git", "/ws/repos/api/.bare"}, fake.Calls()[0].Args)
+}
+
+func TestFake_PushRejected(t *testing.T) {
+	fake := New()
+	fake.On("push").NonFastForward()
+	t.Cleanup(fake.Install())
+
+	err := git.PushBranch("/ws/repos/api/worktrees/main", git.PushOptions{})
+	requireCode(t, err, errors.ErrCodePushFailed)
+	assert.Contains(t, err.Error(), "remote has new commits")
+
+	fake = New()
+	fake.On("push").AuthFailure()
+	t.Cleanup(fake.Install())
+
+	err = git.PushBranch("/ws/repos/api/worktrees/main", git.PushOptions{Remote: "origin"})
+	requireCode(t, err, errors.ErrCodeAuthenticationFailed)
+	assert.Equal(t, "git -C /ws/repos/api/worktrees/main push --set-upstream origin HEAD", fake.Calls()[0].String())
+}
+
+func TestFake_SlowRemote(t *testing.T) {
+	fake := New()
+	fake.On("fetch").Delay(time.Minute)
+	t.Cleanup(fake.Install())
+
+	git.SetTimeouts(git.Timeouts{Fetch: 50 * time.Millisecond})
+	defer git.SetTimeouts(git.Timeouts{})
+
+	start := time.Now()
+	err := git.FetchWithOptions("/ws/repos/api/.bare", git.FetchOptions{})
+	assert.Less(t, time.Since(start), 10*time.Second)
+	requireCode(t, err, errors.ErrCodeGitTimeout)
+}
+
+func TestFake_TransientFailureRetried(t *testing.T) {
+	fake := New()
+	fake.On("fetch").Times(1).TransientFailure()
+	fake.On("fetch")
+	t.Cleanup(fake.Install())
+
+	var retries []git.Retry
+	err := git.FetchWithOptions("/ws/repos/api/.bare", git.FetchOptions{
+		OnRetry: func(r git.Retry) { retries = append(retries, r) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fake.Count("fetch"))
+	require.Len(t, retries, 1)
+	assert.Contains(t, retries[0].Reason, "returned error: 503")
+}
+
+func TestFake_Output(t *testing.T) {
+	fake := New()
+	fake.On("rev-parse", "--abbrev-ref", "HEAD").Stdout("feature-x\n")
+	fake.On("symbolic-ref").Exit(1)
+	t.Cleanup(fake.Install())
+
+	branch, err := git.GetCurrentBranch("/ws/repos/api/worktrees/feature-x")
+	require.NoError(t, err)
+	assert.Equal(t, "feature-x", branch)
+
+	detached, err := git.IsDetachedHead("/ws/repos/api/worktrees/feature-x")
+	require.NoError(t, err)
+	assert.True(t, detached)
+
+	// Commands no rule matches fail
+	_, err = git.Version()
+	assert.Error(t, err)
+}
+
+func TestFake_RecordsEnvironment(t *testing.T) {
+	fake := New()
+	fake.On()
+	t.Cleanup(fake.Install())
+
+	git.SetNonInteractive(true)
+	defer git.SetNonInteractive(false)
+
+	require.NoError(t, git.Fetch("/ws/repos/api/.bare"))
+	call := fake.Calls()[0]
+	assert.Equal(t, "/ws/repos/api/.bare", call.Dir)
+	assert.Contains(t, call.Env, "GIT_TERMINAL_PROMPT=0")
+}
+
+func TestFake_DoAndFallback(t *testing.T) {
+	fake := New()
+	fake.Fallback = gitexec.Subprocess{}
+	fake.On("remote").Stdout("origin\nupstream\n")
+	fake.On("for-each-ref").Do(func(ctx context.Context, inv gitexec.Invocation) error {
+		_, _ = io.WriteString(inv.Stdout, "refs/remotes/origin/HEAD\nrefs/remotes/origin/main\nrefs/remotes/upstream/main\nrefs/remotes/upstream/dev\n")
+		return nil
+	})
+	t.Cleanup(fake.Install())
+
+	// Faked, so the repository need not exist
+	branches, err := git.ListRemoteBranches("/ws/repos/api/.bare")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main", "dev"}, branches)
+
+	// Run by git itself
+	version, err := git.Version()
+	require.NoError(t, err)
+	assert.Contains(t, version, "git version")
+}
+
+func TestFake_InstallRestores(t *testing.T) {
+	outer := New()
+	restoreOuter := outer.Install()
+	defer restoreOuter()
+
+	inner := New()
+	restore := inner.Install()
+	assert.Same(t, inner, gitexec.Current())
+
+	// The executor it replaced comes back, not the default
+	restore()
+	assert.Same(t, outer, gitexec.Current())
+}
@@ -0,0 +1,115 @@
+// Package atomicfile writes files so that a crash or a full disk leaves either
+// the old contents or the new ones, never a truncated mix of both.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/foundagent/foundagent/internal/dryrun"
+)
+
+// BackupSuffix is appended to a file's path to name its backup
+const BackupSuffix = ".bak"
+
+// BackupPath returns the path of the backup kept by WriteWithBackup
+func BackupPath(path string) string {
+	return path + BackupSuffix
+}
+
+// Write replaces the file at path with data. The data is written and synced
+// to a temporary file in the same directory, which is then renamed over path.
+// In a dry run the write is only recorded.
+func Write(path string, data []byte, perm os.FileMode) error {
+	if dryrun.Enabled() {
+		dryrun.WriteFile(path, data)
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file unless it was renamed into place
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	renamed = true
+
+	syncDir(dir)
+	return nil
+}
+
+// WriteWithBackup is Write that first keeps the current contents of path, if
+// any, at BackupPath(path). Since every write replaces the file whole, the
+// backup is always a complete earlier version. A backup that cannot be made
+// does not stop the write.
+func WriteWithBackup(path string, data []byte, perm os.FileMode) error {
+	if dryrun.Enabled() {
+		return Write(path, data, perm)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		_ = backup(path, info.Mode().Perm())
+	}
+	return Write(path, data, perm)
+}
+
+// Restore replaces the file at path with its backup
+func Restore(path string) error {
+	data, err := os.ReadFile(BackupPath(path))
+	if err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(BackupPath(path)); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return Write(path, data, perm)
+}
+
+// backup copies path to its backup path, replacing the old backup atomically
+func backup(path string, perm os.FileMode) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return Write(BackupPath(path), data, perm)
+}
+
+// syncDir flushes a directory entry change, such as a rename, to disk. Not
+// every platform can sync directories, so failures are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
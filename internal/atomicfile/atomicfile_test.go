@@ -0,0 +1,96 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0644))
+
+	require.NoError(t, Write(path, []byte("new"), 0600))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if os.PathSeparator == '/' {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWrite_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "state.json")
+
+	assert.Error(t, Write(path, []byte("data"), 0644))
+	assert.NoFileExists(t, path)
+}
+
+func TestWriteWithBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// The first write has nothing to back up
+	require.NoError(t, WriteWithBackup(path, []byte("v1"), 0644))
+	assert.NoFileExists(t, BackupPath(path))
+
+	require.NoError(t, WriteWithBackup(path, []byte("v2"), 0644))
+	require.NoError(t, WriteWithBackup(path, []byte("v3"), 0644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", string(data))
+
+	backup, err := os.ReadFile(BackupPath(path))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(backup))
+}
+
+func TestRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, WriteWithBackup(path, []byte("good"), 0644))
+	require.NoError(t, WriteWithBackup(path, []byte("next"), 0644))
+	require.NoError(t, os.WriteFile(path, []byte("trunc"), 0644))
+
+	require.NoError(t, Restore(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "good", string(data))
+}
+
+func TestRestore_NoBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	assert.Error(t, Restore(path))
+}
+
+func TestWrite_DryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+
+	dryrun.Start()
+	defer dryrun.Stop()
+
+	require.NoError(t, WriteWithBackup(path, []byte("new\n"), 0644))
+
+	// Nothing is written, but the write is planned
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", string(data))
+	assert.NoFileExists(t, BackupPath(path))
+	assert.Equal(t, []dryrun.Step{{Action: dryrun.ActionEdit, Path: path, Diff: "-old\n+new\n"}}, dryrun.Steps())
+}
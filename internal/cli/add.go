@@ -1,22 +1,28 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/errors"
 	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
 	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/progress"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	addForce bool
-	addJSON  bool
+	addForce  bool
+	addJSON   bool
+	addDepth  int
+	addFilter string
 )
 
 var addCmd = &cobra.Command{
@@ -32,7 +38,11 @@ Multiple repositories can be added in parallel by providing multiple URLs.
 An optional custom name can be provided after the URL.
 
 If no URLs are provided, repositories from .foundagent.yaml will be cloned
-to match the configuration (reconciliation mode).`,
+to match the configuration (reconciliation mode).
+
+Large repositories can be cloned shallow (--depth) or partial (--filter).
+These settings are saved as clone_depth and filter in the config, and
+'fa sync' keeps fetching the same way.`,
 	Example: `  # Add a single repository
   fa add git@github.com:org/my-repo.git
 
@@ -45,18 +55,38 @@ to match the configuration (reconciliation mode).`,
   # Sync workspace to match config
   fa add
 
+  # Clone only the backend group from config
+  fa add --group backend
+
+  # Shallow clone with only the latest commit
+  fa add git@github.com:org/monorepo.git --depth 1
+
+  # Partial clone that downloads file contents on demand
+  fa add git@github.com:org/monorepo.git --filter blob:none
+
   # Add with JSON output
   fa add git@github.com:org/my-repo.git --json
 
+  # Show what would be cloned and changed, without doing it
+  fa add git@github.com:org/my-repo.git --dry-run
+
   # Force re-clone existing repository
   fa add git@github.com:org/my-repo.git --force`,
-	Args: cobra.MinimumNArgs(0),
-	RunE: runAdd,
+	Args:        cobra.MinimumNArgs(0),
+	Annotations: locksWorkspace,
+	RunE:        runAdd,
 }
 
 func init() {
 	addCmd.Flags().BoolVar(&addForce, "force", false, "Force re-clone if repository already exists")
 	addCmd.Flags().BoolVar(&addJSON, "json", false, "Output result as JSON")
+	addRepoSelectionFlags(addCmd)
+	addCmd.Flags().IntVar(&addDepth, "depth", 0, "Shallow clone with this many recent commits")
+	addCmd.Flags().StringVar(&addFilter, "filter", "", "Partial clone filter, e.g. blob:none")
+	addDryRunFlag(addCmd)
+	_ = addCmd.RegisterFlagCompletionFunc("filter", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"blob:none", "tree:0"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	rootCmd.AddCommand(addCmd)
 }
 
@@ -68,6 +98,8 @@ type addResult struct {
 	Status       string `json:"status"`
 	Error        string `json:"error,omitempty"`
 	Skipped      bool   `json:"skipped,omitempty"`
+
+	Retries []git.Retry `json:"retries,omitempty"` // Clone retries after transient network failures
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -82,16 +114,28 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := validateCloneFlags(); err != nil {
+		if addJSON {
+			_ = output.PrintError(err)
+		} else {
+			output.PrintErrorMessage("Error: %v", err)
+		}
+		return err
+	}
+
 	// If no URLs provided, sync from config (reconciliation mode)
 	if len(args) == 0 {
-		return runReconcile(ws)
+		return runReconcile(commandContext(cmd), ws)
 	}
 
 	// Parse arguments - support both "url name" and "url url url" patterns
 	repos := parseAddArgs(args)
 
 	// Add repositories
-	results := addRepositories(ws, repos)
+	results := addRepositories(commandContext(cmd), ws, repos)
+	if dryRun {
+		return printPlan(ws, addJSON, addProblems(results))
+	}
 
 	// Output results
 	if addJSON {
@@ -135,9 +179,9 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runReconcile(ws *workspace.Workspace) error {
+func runReconcile(ctx context.Context, ws *workspace.Workspace) error {
 	// Reconcile config with state
-	result, err := workspace.Reconcile(ws)
+	result, err := workspace.ReconcileSelected(ws, repoSelector())
 	if err != nil {
 		if addJSON {
 			_ = output.PrintError(err)
@@ -149,6 +193,9 @@ func runReconcile(ws *workspace.Workspace) error {
 
 	// If nothing to clone, report and exit
 	if len(result.ReposToClone) == 0 {
+		if dryRun {
+			return printPlan(ws, addJSON, nil)
+		}
 		if addJSON {
 			return output.PrintSuccess(map[string]interface{}{
 				"message":          "All repositories are up-to-date",
@@ -165,18 +212,46 @@ func runReconcile(ws *workspace.Workspace) error {
 	}
 
 	// Clone missing repositories
-	if !addJSON {
+	if !addJSON && !dryRun {
 		output.PrintMessage("Cloning %d repository(ies) from config...", len(result.ReposToClone))
 	}
 
 	repos := make([]repoToAdd, len(result.ReposToClone))
 	for i, r := range result.ReposToClone {
-		repos[i] = repoToAdd{URL: r.URL, Name: r.Name}
+		repos[i] = repoFromConfig(r)
 	}
 
-	results := addRepositories(ws, repos)
+	results := addRepositories(ctx, ws, repos)
+
+	// A freshly cloned repo may hold an included config listing more repos
+	attempted := make(map[string]bool)
+	for _, r := range repos {
+		attempted[r.Name] = true
+	}
+	for {
+		next, err := workspace.ReconcileSelected(ws, repoSelector())
+		if err != nil {
+			break
+		}
+		result.ReposStale = next.ReposStale
+
+		var more []repoToAdd
+		for _, r := range next.ReposToClone {
+			if !attempted[r.Name] {
+				attempted[r.Name] = true
+				more = append(more, repoFromConfig(r))
+			}
+		}
+		if len(more) == 0 {
+			break
+		}
+		results = append(results, addRepositories(ctx, ws, more)...)
+	}
 
 	// Output results
+	if dryRun {
+		return printPlan(ws, addJSON, addProblems(results))
+	}
 	if addJSON {
 		return output.PrintJSON(map[string]interface{}{
 			"repos_cloned": results,
@@ -208,9 +283,70 @@ func runReconcile(ws *workspace.Workspace) error {
 	return nil
 }
 
+// addProblems returns the repos a dry run of add would skip or fail to add
+func addProblems(results []addResult) []planProblem {
+	var problems []planProblem
+	for _, r := range results {
+		name := r.Name
+		if name == "" {
+			name = r.URL
+		}
+		switch {
+		case r.Status == "error":
+			problems = append(problems, planProblem{Repo: name, Status: "error", Message: r.Error})
+		case r.Skipped:
+			problems = append(problems, planProblem{Repo: name, Status: "skipped", Message: "already exists"})
+		}
+	}
+	return problems
+}
+
 type repoToAdd struct {
-	URL  string
-	Name string
+	URL          string
+	Name         string
+	Sparse       []string
+	Depth        int
+	Filter       string
+	SingleBranch bool
+}
+
+// validateCloneFlags checks --depth and --filter before anything is cloned
+func validateCloneFlags() error {
+	if addDepth < 0 {
+		return errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("Invalid depth: %d", addDepth),
+			"Use a positive number of commits",
+		)
+	}
+	if addFilter != "" && !config.ValidFilter(addFilter) {
+		return errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("Unsupported clone filter: %s", addFilter),
+			"Use blob:none, blob:limit=<size> or tree:<depth>",
+		)
+	}
+	return nil
+}
+
+// repoFromConfig returns a config entry to clone, with --depth and --filter
+// taking precedence over its clone settings
+func repoFromConfig(r config.RepoConfig) repoToAdd {
+	repo := repoToAdd{
+		URL:          r.URL,
+		Name:         r.Name,
+		Sparse:       r.Sparse,
+		Depth:        r.CloneDepth,
+		Filter:       r.Filter,
+		SingleBranch: r.SingleBranch,
+	}
+	if addDepth > 0 {
+		repo.Depth = addDepth
+	}
+	if addFilter != "" {
+		repo.Filter = addFilter
+	}
+	return repo
 }
 
 func parseAddArgs(args []string) []repoToAdd {
@@ -236,35 +372,54 @@ func parseAddArgs(args []string) []repoToAdd {
 			i++
 		}
 
-		repos = append(repos, repoToAdd{URL: url, Name: name})
+		repos = append(repos, repoToAdd{URL: url, Name: name, Depth: addDepth, Filter: addFilter})
 	}
 
 	return repos
 }
 
-func addRepositories(ws *workspace.Workspace, repos []repoToAdd) []addResult {
-	if len(repos) == 1 {
-		// Single repository - no parallelization
-		return []addResult{addRepository(ws, repos[0])}
-	}
+// addRepositories adds repos in parallel, at most ws.Jobs() at a time. Repos
+// not started before ctx is cancelled are reported as errors.
+// registerMu serializes the state, config and VS Code workspace updates of
+// repos added in parallel
+var registerMu sync.Mutex
 
-	// Multiple repositories - add in parallel
+func addRepositories(ctx context.Context, ws *workspace.Workspace, repos []repoToAdd) []addResult {
 	results := make([]addResult, len(repos))
-	var wg sync.WaitGroup
-
-	for i, repo := range repos {
-		wg.Add(1)
-		go func(index int, r repoToAdd) {
-			defer wg.Done()
-			results[index] = addRepository(ws, r)
-		}(i, repo)
+	bar := newProgress(addJSON)
+	started := workspace.ForEachParallel(ctx, ws.Jobs(), len(repos), func(ctx context.Context, i int) {
+		results[i] = addRepository(ws, repos[i], bar)
+	})
+	bar.Stop()
+
+	for i, ok := range started {
+		if !ok {
+			name := repos[i].Name
+			if name == "" {
+				name, _ = git.InferName(repos[i].URL)
+			}
+			results[i] = addResult{
+				Name:   name,
+				URL:    repos[i].URL,
+				Status: "error",
+				Error:  context.Cause(ctx).Error(),
+			}
+		}
 	}
-
-	wg.Wait()
 	return results
 }
 
-func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
+func addRepository(ws *workspace.Workspace, repo repoToAdd, bar *progress.Renderer) (result addResult) {
+	// The task starts once the repo's name is known
+	var task *progress.Task
+	defer func() {
+		if result.Status == "error" {
+			task.Fail()
+		} else {
+			task.Done()
+		}
+	}()
+
 	// Validate URL
 	if err := git.ValidateURL(repo.URL); err != nil {
 		return addResult{
@@ -313,9 +468,9 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 	// Create bare repository path
 	bareRepoPath := ws.BareRepoPath(name)
 
-	// Remove existing if force
-	if hasRepo && addForce {
-		if err := os.RemoveAll(bareRepoPath); err != nil {
+	// Remove existing if force, into the journal if it is being kept
+	if hasRepo && addForce && !journal.TrashRepo(name, bareRepoPath) {
+		if err := dryrun.RemoveAll(bareRepoPath); err != nil {
 			return addResult{
 				Name:   name,
 				URL:    repo.URL,
@@ -326,24 +481,42 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 	}
 
 	// Clone bare repository
-	if !addJSON {
-		output.PrintMessage("Cloning %s...", name)
-	}
-
-	if err := git.CloneBare(repo.URL, bareRepoPath, !addJSON); err != nil {
+	task = bar.Start(name, "cloning")
+	var retries []git.Retry
+	if err := git.Clone(git.CloneOptions{
+		URL:          repo.URL,
+		TargetPath:   bareRepoPath,
+		Bare:         true,
+		Progress:     !addJSON,
+		Depth:        repo.Depth,
+		Filter:       repo.Filter,
+		SingleBranch: repo.SingleBranch,
+		Output:       task.Writer(),
+		OnRetry: func(r git.Retry) {
+			retries = append(retries, r)
+			task.Log("%s", r)
+		},
+	}); err != nil {
 		return addResult{
-			Name:   name,
-			URL:    repo.URL,
-			Status: "error",
-			Error:  err.Error(),
+			Name:    name,
+			URL:     repo.URL,
+			Status:  "error",
+			Error:   err.Error(),
+			Retries: retries,
 		}
 	}
 
-	// Get default branch
-	defaultBranch, err := git.GetDefaultBranch(bareRepoPath)
+	// Get default branch. A dry run has no clone to ask, so it asks the
+	// remote.
+	var defaultBranch string
+	if dryRun {
+		defaultBranch, err = git.RemoteDefaultBranch(repo.URL)
+	} else {
+		defaultBranch, err = git.GetDefaultBranch(bareRepoPath)
+	}
 	if err != nil {
 		// Clean up on failure
-		os.RemoveAll(bareRepoPath)
+		dryrun.RemoveAll(bareRepoPath)
 		return addResult{
 			Name:   name,
 			URL:    repo.URL,
@@ -353,10 +526,11 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 	}
 
 	// Create worktree for default branch
+	task.SetPhase("creating worktree")
 	worktreePath := ws.WorktreePath(name, defaultBranch)
-	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+	if err := dryrun.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
 		// Clean up on failure
-		os.RemoveAll(bareRepoPath)
+		dryrun.RemoveAll(bareRepoPath)
 		return addResult{
 			Name:   name,
 			URL:    repo.URL,
@@ -369,9 +543,10 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 		BareRepoPath: bareRepoPath,
 		WorktreePath: worktreePath,
 		Branch:       defaultBranch,
+		Sparse:       repo.Sparse,
 	}); err != nil {
 		// Clean up on failure
-		os.RemoveAll(bareRepoPath)
+		dryrun.RemoveAll(bareRepoPath)
 		return addResult{
 			Name:   name,
 			URL:    repo.URL,
@@ -380,7 +555,9 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 		}
 	}
 
-	// Register repository in workspace
+	// Register repository in workspace. Repos added in parallel register one
+	// at a time, so none of their state and config updates are lost.
+	registerMu.Lock()
 	repository := &workspace.Repository{
 		Name:          name,
 		URL:           repo.URL,
@@ -391,9 +568,10 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 	}
 
 	if err := ws.AddRepository(repository); err != nil {
+		registerMu.Unlock()
 		// Clean up on failure
-		os.RemoveAll(bareRepoPath)
-		os.RemoveAll(worktreePath)
+		dryrun.RemoveAll(bareRepoPath)
+		dryrun.RemoveAll(worktreePath)
 		return addResult{
 			Name:   name,
 			URL:    repo.URL,
@@ -402,29 +580,43 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 		}
 	}
 
-	// Update config file with new repository
-	cfg, err := config.Load(ws.Path)
+	// Update config file with new repository. Only the local file is edited,
+	// and repos listed by an included file are left there.
+	cfg, err := config.LoadLocal(ws.Path)
 	if err != nil {
 		// Config load failed, but repo is already added - just warn
-		if !addJSON {
-			output.PrintErrorMessage("Warning: Failed to update config: %v", err)
-		}
-	} else {
+		task.Log("Warning: Failed to update config: %v", err)
+	} else if included, _ := config.IsIncluded(ws.Path, name); !included || config.HasRepo(cfg, name) {
 		config.AddRepo(cfg, repo.URL, name, defaultBranch)
+		recordCloneOptions(cfg, name, repo)
 		if err := config.Save(ws.Path, cfg); err != nil {
 			// Config save failed, but repo is already added - just warn
-			if !addJSON {
-				output.PrintErrorMessage("Warning: Failed to save config: %v", err)
-			}
+			task.Log("Warning: Failed to save config: %v", err)
 		}
 	}
 
+	registerMu.Unlock()
+
+	// 'fa undo' removes the worktree, then the clone
+	journal.RepoCloned(name, bareRepoPath)
+	journal.WorktreeAdded(name, bareRepoPath, worktreePath, defaultBranch)
+
+	// Add extra remotes, then fetch submodules and LFS files, once the config
+	// entry is in place
+	if err := ws.SetupRemotes(name); err != nil {
+		task.Log("Warning: %v", err)
+	}
+	if err := ws.SetupWorktree(name, worktreePath); err != nil {
+		task.Log("Warning: %v", err)
+	}
+
 	// Update VS Code workspace
-	if err := ws.AddWorktreeFolder(worktreePath); err != nil {
+	registerMu.Lock()
+	err = ws.AddWorktreeFolder(worktreePath)
+	registerMu.Unlock()
+	if err != nil {
 		// Don't fail the whole operation if VS Code update fails
-		if !addJSON {
-			output.PrintErrorMessage("Warning: Failed to update VS Code workspace: %v", err)
-		}
+		task.Log("Warning: Failed to update VS Code workspace: %v", err)
 	}
 
 	return addResult{
@@ -433,5 +625,21 @@ func addRepository(ws *workspace.Workspace, repo repoToAdd) addResult {
 		BareRepoPath: bareRepoPath,
 		WorktreePath: worktreePath,
 		Status:       "success",
+		Retries:      retries,
+	}
+}
+
+// recordCloneOptions saves the depth and filter a repo was cloned with to its
+// config entry, so later fetches and clones on other machines match
+func recordCloneOptions(cfg *config.Config, name string, repo repoToAdd) {
+	entry := config.GetRepo(cfg, name)
+	if entry == nil {
+		return
+	}
+	if repo.Depth > 0 {
+		entry.CloneDepth = repo.Depth
+	}
+	if repo.Filter != "" {
+		entry.Filter = repo.Filter
 	}
 }
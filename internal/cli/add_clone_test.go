@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setCloneFlags(t *testing.T, depth int, filter string) {
+	t.Helper()
+	addDepth, addFilter = depth, filter
+	t.Cleanup(func() { addDepth, addFilter = 0, "" })
+}
+
+func TestParseAddArgs_CloneFlags(t *testing.T) {
+	setCloneFlags(t, 1, "blob:none")
+
+	repos := parseAddArgs([]string{"git@github.com:org/a.git", "git@github.com:org/b.git", "bee"})
+
+	require.Len(t, repos, 2)
+	for _, repo := range repos {
+		assert.Equal(t, 1, repo.Depth)
+		assert.Equal(t, "blob:none", repo.Filter)
+	}
+}
+
+func TestRepoFromConfig(t *testing.T) {
+	entry := config.RepoConfig{
+		URL:          "git@github.com:org/mono.git",
+		Name:         "mono",
+		Sparse:       []string{"api"},
+		CloneDepth:   10,
+		Filter:       "tree:0",
+		SingleBranch: true,
+	}
+
+	repo := repoFromConfig(entry)
+	assert.Equal(t, repoToAdd{URL: entry.URL, Name: "mono", Sparse: []string{"api"}, Depth: 10, Filter: "tree:0", SingleBranch: true}, repo)
+
+	// Flags take precedence over the config
+	setCloneFlags(t, 1, "blob:none")
+	repo = repoFromConfig(entry)
+	assert.Equal(t, 1, repo.Depth)
+	assert.Equal(t, "blob:none", repo.Filter)
+	assert.True(t, repo.SingleBranch)
+}
+
+func TestValidateCloneFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		depth   int
+		filter  string
+		wantErr bool
+	}{
+		{"none", 0, "", false},
+		{"depth and filter", 1, "blob:limit=1m", false},
+		{"negative depth", -1, "", true},
+		{"unsupported filter", 0, "sparse:oid=abc", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setCloneFlags(t, tt.depth, tt.filter)
+
+			err := validateCloneFlags()
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var faErr *errors.Error
+			require.ErrorAs(t, err, &faErr)
+			assert.Equal(t, errors.ErrCodeInvalidInput, faErr.Code)
+		})
+	}
+}
+
+func TestRecordCloneOptions(t *testing.T) {
+	cfg := config.DefaultConfig("test")
+	config.AddRepo(cfg, "git@github.com:org/mono.git", "mono", "main")
+	config.AddRepo(cfg, "git@github.com:org/web.git", "web", "main")
+
+	recordCloneOptions(cfg, "mono", repoToAdd{Depth: 1, Filter: "blob:none"})
+	recordCloneOptions(cfg, "web", repoToAdd{})
+
+	assert.Equal(t, 1, cfg.Repos[0].CloneDepth)
+	assert.Equal(t, "blob:none", cfg.Repos[0].Filter)
+	assert.Zero(t, cfg.Repos[1].CloneDepth)
+	assert.Empty(t, cfg.Repos[1].Filter)
+}
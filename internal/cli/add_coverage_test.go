@@ -23,7 +23,7 @@ func TestAddRepository_InferNameError(t *testing.T) {
 		Name: "", // Force name inference
 	}
 
-	result := addRepository(ws, repo)
+	result := addRepository(ws, repo, nil)
 
 	assert.Equal(t, "error", result.Status)
 	assert.NotEmpty(t, result.Error)
@@ -47,7 +47,7 @@ func TestAddRepository_CloneBareError(t *testing.T) {
 	addJSON = true
 	defer func() { addJSON = false }()
 
-	result := addRepository(ws, repo)
+	result := addRepository(ws, repo, nil)
 
 	assert.Equal(t, "error", result.Status)
 	assert.NotEmpty(t, result.Error)
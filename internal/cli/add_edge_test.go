@@ -20,7 +20,7 @@ func TestAddRepository_NameInference(t *testing.T) {
 
 	// Test with URL but no name - should fail validation on invalid URL
 	repo := repoToAdd{URL: "not-a-url", Name: ""}
-	result := addRepository(ws, repo)
+	result := addRepository(ws, repo, nil)
 
 	assert.Equal(t, "error", result.Status)
 }
@@ -58,7 +58,7 @@ func TestAddRepository_ForceWithExistingDirectory(t *testing.T) {
 	defer func() { addForce = false }()
 
 	repoToAdd := repoToAdd{URL: "invalid-url", Name: "test-repo"}
-	result := addRepository(ws, repoToAdd)
+	result := addRepository(ws, repoToAdd, nil)
 
 	// Should fail on URL validation before it tries to remove
 	assert.Equal(t, "error", result.Status)
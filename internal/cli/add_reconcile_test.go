@@ -31,7 +31,7 @@ func TestRunReconcile_AllUpToDate(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 
 	// Run reconcile on empty workspace
-	err = runReconcile(ws)
+	err = runReconcile(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -63,7 +63,7 @@ func TestRunReconcile_AllUpToDateJSONMode(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 
 	addJSON = true
-	err = runReconcile(ws)
+	err = runReconcile(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -104,7 +104,7 @@ func TestRunReconcile_ReposToClone(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 
 	// Run reconcile - will try to clone
-	err = runReconcile(ws)
+	err = runReconcile(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -145,7 +145,7 @@ func TestRunReconcile_ReposToCloneJSONMode(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 
 	addJSON = true
-	err = runReconcile(ws)
+	err = runReconcile(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -172,7 +172,7 @@ func TestAddRepository_ValidURL(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "not-a-valid-url",
 		Name: "test",
-	})
+	}, nil)
 
 	assert.Equal(t, "error", result.Status)
 }
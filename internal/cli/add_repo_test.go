@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,7 +51,7 @@ func TestAddRepository_ValidateURLError(t *testing.T) {
 
 	result := addRepository(ws, repoToAdd{
 		URL: "not-a-valid-url",
-	})
+	}, nil)
 
 	assert.Equal(t, "error", result.Status)
 	assert.NotEmpty(t, result.Error)
@@ -65,7 +66,7 @@ func TestAddRepository_InferNameFromURLError(t *testing.T) {
 
 	result := addRepository(ws, repoToAdd{
 		URL: "https://example.com",
-	})
+	}, nil)
 
 	assert.Equal(t, "error", result.Status)
 }
@@ -84,7 +85,7 @@ func TestAddRepository_ValidURLWithClone(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  sourceURL,
 		Name: "test-repo",
-	})
+	}, nil)
 
 	// Should succeed with local repo
 	assert.Equal(t, "success", result.Status)
@@ -92,6 +93,33 @@ func TestAddRepository_ValidURLWithClone(t *testing.T) {
 	assert.NotEmpty(t, result.BareRepoPath)
 }
 
+// TestAddRepository_KeepsIncludedSettings tests that adding a repo does not
+// write settings the local file leaves to an included file
+func TestAddRepository_KeepsIncludedSettings(t *testing.T) {
+	sourceRepo := createLocalGitRepo(t, "source-repo")
+
+	tmpDir := t.TempDir()
+	ws, err := workspace.New("test-ws", tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	configPath := filepath.Join(ws.Path, ".foundagent.yaml")
+	require.NoError(t, os.WriteFile(filepath.Join(ws.Path, "team.yaml"), []byte("settings:\n  auto_create_worktree: true\n"), 0644))
+	require.NoError(t, os.WriteFile(configPath, []byte("workspace:\n  name: test-ws\ninclude:\n  - team.yaml\nrepos: []\n"), 0644))
+
+	result := addRepository(ws, repoToAdd{URL: "file://" + sourceRepo, Name: "test-repo"}, nil)
+	require.Equal(t, "success", result.Status, result.Error)
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "test-repo")
+	assert.NotContains(t, string(data), "auto_create_worktree")
+
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Settings.AutoCreateWorktree)
+}
+
 // TestAddRepository_ExistingRepoNoForce tests skipping existing repo
 func TestAddRepository_ExistingRepoNoForce(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -113,7 +141,7 @@ func TestAddRepository_ExistingRepoNoForce(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/test/repo.git",
 		Name: "test-repo",
-	})
+	}, nil)
 
 	assert.Equal(t, "success", result.Status)
 	assert.True(t, result.Skipped)
@@ -147,7 +175,7 @@ func TestAddRepository_ExistingRepoWithForce(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  sourceURL,
 		Name: "test-repo",
-	})
+	}, nil)
 
 	// Should succeed with force
 	assert.Equal(t, "success", result.Status)
@@ -167,7 +195,7 @@ func TestAddRepository_InferredName(t *testing.T) {
 
 	result := addRepository(ws, repoToAdd{
 		URL: sourceURL, // Name should be inferred as "my-awesome-repo"
-	})
+	}, nil)
 
 	// Should succeed and infer name from path
 	assert.Equal(t, "success", result.Status)
@@ -26,7 +26,7 @@ func TestAddRepository_HasRepositoryError(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/test/repo.git",
 		Name: "test-repo",
-	})
+	}, nil)
 
 	// Should error - either from yaml or from clone
 	assert.Equal(t, "error", result.Status)
@@ -66,7 +66,7 @@ func TestAddRepository_ForceRemoveError(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/test/repo.git",
 		Name: "test-repo",
-	})
+	}, nil)
 
 	// Should error - may be from removal or clone
 	assert.Equal(t, "error", result.Status)
@@ -89,7 +89,7 @@ func TestAddRepository_GetDefaultBranchError(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "file://" + repoPath, // Use file:// to avoid network
 		Name: "test-repo",
-	})
+	}, nil)
 
 	// This will fail during clone or default branch detection
 	assert.Equal(t, "error", result.Status)
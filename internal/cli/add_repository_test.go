@@ -26,7 +26,7 @@ func TestAddRepository_CompletelyInvalidURL(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "not a valid url at all",
 		Name: "test",
-	})
+	}, nil)
 
 	assert.Equal(t, "error", result.Status)
 	assert.Contains(t, result.Error, "Invalid")
@@ -49,7 +49,7 @@ func TestAddRepository_EmptyNameInference(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com",
 		Name: "",
-	})
+	}, nil)
 
 	assert.Equal(t, "error", result.Status)
 }
@@ -88,7 +88,7 @@ func TestAddRepository_SkipExisting(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/org/test-repo.git",
 		Name: "test-repo",
-	})
+	}, nil)
 
 	assert.Equal(t, "success", result.Status)
 	assert.True(t, result.Skipped)
@@ -113,7 +113,7 @@ func TestAddRepository_CloneFailure(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/nonexistent/repo-that-does-not-exist-12345.git",
 		Name: "test-repo",
-	})
+	}, nil)
 
 	assert.Equal(t, "error", result.Status)
 	assert.NotEmpty(t, result.Error)
@@ -160,7 +160,7 @@ func TestAddRepository_ForceRemoveExisting(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/nonexistent/new-repo.git",
 		Name: "test-repo",
-	})
+	}, nil)
 
 	// Should have removed the old directory
 	_, err = os.Stat(repoPath + "/testfile")
@@ -170,7 +170,7 @@ func TestRunReconcile_UpToDate(t *testing.T) {
 
 	// Reconcile with empty config (no repos to clone)
 	addJSON = false
-	err = runReconcile(ws)
+	err = runReconcile(t.Context(), ws)
 	assert.NoError(t, err)
 
 	// Reset flag
@@ -192,7 +192,7 @@ func TestRunReconcile_JSONMode(t *testing.T) {
 
 	// Reconcile in JSON mode
 	addJSON = true
-	err = runReconcile(ws)
+	err = runReconcile(t.Context(), ws)
 	assert.NoError(t, err)
 
 	// Reset flag
@@ -213,7 +213,7 @@ func TestAddRepositories_Single(t *testing.T) {
 		{URL: "not-a-url", Name: ""},
 	}
 
-	results := addRepositories(ws, repos)
+	results := addRepositories(t.Context(), ws, repos)
 	assert.Len(t, results, 1)
 	assert.Equal(t, "error", results[0].Status)
 	assert.NotEmpty(t, results[0].Error)
@@ -234,7 +234,7 @@ func TestAddRepositories_Multiple(t *testing.T) {
 		{URL: "invalid-url-2", Name: "repo2"},
 	}
 
-	results := addRepositories(ws, repos)
+	results := addRepositories(t.Context(), ws, repos)
 	assert.Len(t, results, 2)
 	// Both should fail validation
 	for _, r := range results {
@@ -254,7 +254,7 @@ func TestAddRepository_InvalidURL(t *testing.T) {
 
 	// Test invalid URL
 	repo := repoToAdd{URL: "not-a-url", Name: ""}
-	result := addRepository(ws, repo)
+	result := addRepository(ws, repo, nil)
 
 	assert.Equal(t, "error", result.Status)
 	assert.NotEmpty(t, result.Error)
@@ -272,7 +272,7 @@ func TestAddRepository_WithCustomName(t *testing.T) {
 
 	// Test with custom name using invalid URL (fails validation, not clone)
 	repo := repoToAdd{URL: "not-a-valid-url", Name: "custom-name"}
-	result := addRepository(ws, repo)
+	result := addRepository(ws, repo, nil)
 
 	assert.Equal(t, "error", result.Status) // URL validation fails
 	assert.NotEmpty(t, result.Error)
@@ -300,7 +300,7 @@ func TestAddRepository_AlreadyExists(t *testing.T) {
 	// Try to add again without force
 	addForce = false
 	repoToAddAgain := repoToAdd{URL: "https://github.com/org/repo.git", Name: "existing-repo"}
-	result := addRepository(ws, repoToAddAgain)
+	result := addRepository(ws, repoToAddAgain, nil)
 
 	assert.Equal(t, "success", result.Status)
 	assert.True(t, result.Skipped)
@@ -336,7 +336,7 @@ func TestAddRepository_ForceOverwrite(t *testing.T) {
 	// Try to add again with force using invalid URL
 	addForce = true
 	repoToAddAgain := repoToAdd{URL: "invalid-url", Name: "existing-repo"}
-	result := addRepository(ws, repoToAddAgain)
+	result := addRepository(ws, repoToAddAgain, nil)
 
 	// Should fail on URL validation
 	assert.Equal(t, "error", result.Status)
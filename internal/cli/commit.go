@@ -30,6 +30,9 @@ Examples:
   # Commit specific repos only
   fa commit --repo api --repo lib "API changes"
 
+  # Commit only repos in the backend group
+  fa commit --group backend "Backend changes"
+
   # Preview what would be committed
   fa commit --dry-run "Test commit"
 
@@ -47,7 +50,6 @@ var (
 	commitAll           bool
 	commitAmend         bool
 	commitDryRun        bool
-	commitRepos         []string
 	commitJSON          bool
 	commitVerbose       bool
 	commitAllowDetached bool
@@ -60,8 +62,8 @@ func init() {
 	commitCmd.Flags().BoolVarP(&commitAll, "all", "a", false, "Stage all tracked modifications")
 	commitCmd.Flags().BoolVar(&commitAmend, "amend", false, "Amend the previous commit")
 	commitCmd.Flags().BoolVar(&commitDryRun, "dry-run", false, "Preview without committing")
-	commitCmd.Flags().StringArrayVar(&commitRepos, "repo", nil, "Limit to specific repos (can be repeated)")
 	commitCmd.Flags().BoolVar(&commitJSON, "json", false, "Output as JSON")
+	addRepoSelectionFlags(commitCmd)
 	commitCmd.Flags().BoolVarP(&commitVerbose, "verbose", "v", false, "Show detailed progress")
 	commitCmd.Flags().BoolVar(&commitAllowDetached, "allow-detached", false, "Allow commits in detached HEAD")
 }
@@ -87,13 +89,13 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		All:           commitAll,
 		Amend:         commitAmend,
 		DryRun:        commitDryRun,
-		Repos:         commitRepos,
+		Selection:     repoSelector(),
 		Verbose:       commitVerbose,
 		AllowDetached: commitAllowDetached,
 	}
 
 	// Execute commit
-	results, err := ws.CommitAllRepos(opts)
+	results, err := ws.CommitAllRepos(commandContext(cmd), opts)
 	if err != nil {
 		return err
 	}
@@ -93,7 +93,7 @@ func TestCommitCommand_NoArgs(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = false
 	commitVerbose = false
 	commitAllowDetached = false
@@ -118,7 +118,7 @@ func TestCommitCommand_WithMessage(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = false
 	commitVerbose = false
 	commitAllowDetached = false
@@ -162,7 +162,7 @@ func TestCommitCommand_NothingToCommit(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = false
 	commitVerbose = false
 	commitAllowDetached = false
@@ -214,7 +214,7 @@ func TestCommitCommand_JSONOutput(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -265,7 +265,7 @@ func TestCommitCommand_MessageFlag(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -314,7 +314,7 @@ func TestCommitCommand_PositionalOverridesFlag(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -363,7 +363,7 @@ func TestCommitCommand_MultipleRepos(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -421,7 +421,7 @@ func TestCommitCommand_DryRun(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = true
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = false
 	commitVerbose = false
 	commitAllowDetached = false
@@ -483,7 +483,7 @@ func TestCommitCommand_RepoFilter(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = []string{"api"}
+	selectRepos = []string{"api"}
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -540,7 +540,7 @@ func TestCommitCommand_RepoFilterInvalid(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = []string{"nonexistent"}
+	selectRepos = []string{"nonexistent"}
 	commitJSON = false
 	commitVerbose = false
 	commitAllowDetached = false
@@ -565,7 +565,7 @@ func TestCommitCommand_AllFlag(t *testing.T) {
 	commitAll = true
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -617,7 +617,7 @@ func TestCommitCommand_AmendCLI(t *testing.T) {
 	commitAll = false
 	commitAmend = true
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
@@ -674,7 +674,7 @@ func TestCommitCommand_JSONErrorOutput(t *testing.T) {
 	commitAll = false
 	commitAmend = false
 	commitDryRun = false
-	commitRepos = nil
+	selectRepos = nil
 	commitJSON = true
 	commitVerbose = false
 	commitAllowDetached = false
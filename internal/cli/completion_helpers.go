@@ -3,6 +3,7 @@ package cli
 import (
 	"strings"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -101,3 +102,36 @@ func getBranchCompletions(cmd *cobra.Command, args []string, toComplete string)
 
 	return branches, cobra.ShellCompDirectiveNoFileComp
 }
+
+// getGroupCompletions returns configured group names for completion
+// Returns empty list gracefully if not in a workspace
+func getGroupCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return getLabelCompletions(config.Groups, toComplete)
+}
+
+// getTagCompletions returns configured tag names for completion
+// Returns empty list gracefully if not in a workspace
+func getTagCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return getLabelCompletions(config.Tags, toComplete)
+}
+
+func getLabelCompletions(labelsOf func(*config.Config) []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := config.Load(ws.Path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var labels []string
+	for _, label := range labelsOf(cfg) {
+		if toComplete == "" || strings.HasPrefix(label, toComplete) {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and edit workspace configuration",
+	Long: `Read and edit the workspace config file without opening an editor.
+
+Keys are dotted paths into the config. Repos are addressed by name, or by
+index in brackets:
+
+  workspace.name
+  settings.auto_create_worktree
+  repos.api.default_branch
+  repos[0].tags
+
+'get' and 'list' show effective values, with included files merged. 'set' and
+'unset' change only the workspace's own config file; setting a field of an
+included repo adds an override entry for it. Changes are validated before they
+are written, and comments and key order in YAML configs are preserved. TOML
+files are rewritten as a whole, so 'set' and 'unset' refuse to change a TOML
+config that has comments.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value",
+	Example: `  fa config get settings.auto_create_worktree
+  fa config get repos.api.url`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config value",
+	Example: `  fa config set settings.auto_create_worktree false
+  fa config set repos.api.default_branch develop
+  fa config set repos.api.tags go,backend`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: locksWorkspace,
+	RunE:        runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a config value",
+	Example: `  fa config unset repos.api.default_branch
+  fa config unset repos.api`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: locksWorkspace,
+	RunE:        runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all config values",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for the config file",
+	Long: `Print a JSON Schema describing .foundagent.yaml (and its TOML and JSON
+equivalents), generated from the config definitions in this release.
+
+Point your editor or CI at the schema to validate the config as you type. With
+the YAML language server, add this line to the top of .foundagent.yaml:
+
+  # yaml-language-server: $schema=./foundagent.schema.json`,
+	Example: `  fa config schema
+  fa config schema --output foundagent.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
+var configConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert the config file to another format",
+	Long: `Rewrite the workspace config file as YAML, TOML or JSON and remove the old
+file.
+
+The config must be valid before it is converted. Only the workspace's own file
+is converted; included files keep their format. Comments in a YAML config are
+not carried over to TOML or JSON.`,
+	Example: `  fa config convert --to toml
+  fa config convert --to json
+  fa config convert --to yaml`,
+	Args:        cobra.NoArgs,
+	Annotations: locksWorkspace,
+	RunE:        runConfigConvert,
+}
+
+var (
+	configJSON         bool
+	configSchemaOutput string
+	configConvertTo    string
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd, configSchemaCmd, configConvertCmd)
+
+	configCmd.PersistentFlags().BoolVar(&configJSON, "json", false, "Output result as JSON")
+	configSchemaCmd.Flags().StringVarP(&configSchemaOutput, "output", "o", "", "Write the schema to a file instead of stdout")
+	configConvertCmd.Flags().StringVar(&configConvertTo, "to", "", "Target format: yaml, toml or json")
+	addDryRunFlag(configSetCmd)
+	addDryRunFlag(configUnsetCmd)
+	addDryRunFlag(configConvertCmd)
+	_ = configConvertCmd.MarkFlagRequired("to")
+	_ = configConvertCmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "toml", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	value, err := config.GetValue(ws.Path, args[0])
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if configJSON {
+		return output.PrintJSON(config.KeyValue{Key: args[0], Value: value})
+	}
+
+	output.PrintMessage("%s", config.FormatValue(value))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if err := config.SetValue(ws.Path, args[0], args[1]); err != nil {
+		return printConfigError(err)
+	}
+
+	value, err := config.GetValue(ws.Path, args[0])
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if dryRun {
+		return printPlan(ws, configJSON, nil)
+	}
+	if configJSON {
+		return output.PrintJSON(config.KeyValue{Key: args[0], Value: value})
+	}
+
+	output.PrintMessage("✓ Set %s = %s", args[0], config.FormatValue(value))
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if err := config.UnsetValue(ws.Path, args[0]); err != nil {
+		return printConfigError(err)
+	}
+
+	if dryRun {
+		return printPlan(ws, configJSON, nil)
+	}
+	if configJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"key":   args[0],
+			"unset": true,
+		})
+	}
+
+	output.PrintMessage("✓ Unset %s", args[0])
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	values, err := config.ListValues(ws.Path)
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if configJSON {
+		return output.PrintJSON(values)
+	}
+
+	for _, kv := range values {
+		output.PrintMessage("%s = %s", kv.Key, config.FormatValue(kv.Value))
+	}
+	return nil
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	schema := config.GenerateJSONSchema()
+
+	if configSchemaOutput == "" {
+		return output.PrintJSON(schema)
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if err := atomicfile.Write(configSchemaOutput, append(data, '\n'), 0644); err != nil {
+		return printConfigError(errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to write schema file",
+			"Check that you have write permissions",
+			err,
+		))
+	}
+
+	if configJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"path": configSchemaOutput,
+		})
+	}
+
+	output.PrintMessage("✓ Wrote config schema to %s", configSchemaOutput)
+	return nil
+}
+
+func runConfigConvert(cmd *cobra.Command, args []string) error {
+	format, err := config.ParseFormat(configConvertTo)
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	result, err := config.Convert(ws.Path, format)
+	if err != nil {
+		return printConfigError(err)
+	}
+
+	if dryRun {
+		return printPlan(ws, configJSON, nil)
+	}
+	if configJSON {
+		return output.PrintJSON(result)
+	}
+
+	if !result.Converted {
+		output.PrintMessage("✓ Config is already %s (%s)", format, filepath.Base(result.To))
+		return nil
+	}
+
+	output.PrintMessage("✓ Converted %s to %s", filepath.Base(result.From), filepath.Base(result.To))
+	return nil
+}
+
+func printConfigError(err error) error {
+	if configJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureConfigOutput(t *testing.T, run func() error) (string, error) {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := run()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	return buf.String(), err
+}
+
+func setupConfigTestWorkspace(t *testing.T) {
+	t.Helper()
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	oldDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldDir) })
+	os.Chdir(ws.Path)
+}
+
+func TestConfigCommand_SetGetUnset(t *testing.T) {
+	configJSON = false
+	setupConfigTestWorkspace(t)
+
+	out, err := captureConfigOutput(t, func() error {
+		return runConfigSet(configSetCmd, []string{"settings.auto_create_worktree", "false"})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ Set settings.auto_create_worktree = false")
+
+	out, err = captureConfigOutput(t, func() error {
+		return runConfigGet(configGetCmd, []string{"settings.auto_create_worktree"})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "false\n", out)
+
+	out, err = captureConfigOutput(t, func() error {
+		return runConfigUnset(configUnsetCmd, []string{"settings.auto_create_worktree"})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ Unset settings.auto_create_worktree")
+
+	out, err = captureConfigOutput(t, func() error {
+		return runConfigList(configListCmd, nil)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "workspace.name = test-ws")
+	assert.Contains(t, out, "settings.auto_create_worktree = false")
+}
+
+func TestConfigCommand_GetJSON(t *testing.T) {
+	configJSON = true
+	defer func() { configJSON = false }()
+	setupConfigTestWorkspace(t)
+
+	out, err := captureConfigOutput(t, func() error {
+		return runConfigGet(configGetCmd, []string{"workspace.name"})
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, "workspace.name", result["key"])
+	assert.Equal(t, "test-ws", result["value"])
+}
+
+func TestConfigCommand_UnknownKey(t *testing.T) {
+	configJSON = false
+	setupConfigTestWorkspace(t)
+
+	_, err := captureConfigOutput(t, func() error {
+		return runConfigSet(configSetCmd, []string{"settings.unknown", "1"})
+	})
+	assert.Error(t, err)
+}
+
+func TestConfigCommand_Schema(t *testing.T) {
+	configJSON = false
+	configSchemaOutput = ""
+
+	out, err := captureConfigOutput(t, func() error {
+		return runConfigSchema(configSchemaCmd, nil)
+	})
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &schema))
+	assert.Equal(t, "object", schema["type"])
+	assert.Contains(t, schema["properties"], "repos")
+}
+
+func TestConfigCommand_SchemaToFile(t *testing.T) {
+	configJSON = false
+	configSchemaOutput = filepath.Join(t.TempDir(), "schema.json")
+	defer func() { configSchemaOutput = "" }()
+
+	out, err := captureConfigOutput(t, func() error {
+		return runConfigSchema(configSchemaCmd, nil)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ Wrote config schema to")
+
+	data, err := os.ReadFile(configSchemaOutput)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+}
+
+func TestConfigCommand_Convert(t *testing.T) {
+	configJSON = false
+	configConvertTo = "toml"
+	defer func() { configConvertTo = "" }()
+	setupConfigTestWorkspace(t)
+
+	out, err := captureConfigOutput(t, func() error {
+		return runConfigConvert(configConvertCmd, nil)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ Converted .foundagent.yaml to .foundagent.toml")
+	assert.FileExists(t, ".foundagent.toml")
+	assert.NoFileExists(t, ".foundagent.yaml")
+
+	// The workspace is still discoverable and readable in its new format
+	out, err = captureConfigOutput(t, func() error {
+		return runConfigGet(configGetCmd, []string{"workspace.name"})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test-ws\n", out)
+}
+
+func TestConfigCommand_ConvertInvalidFormat(t *testing.T) {
+	configJSON = false
+	configConvertTo = "xml"
+	defer func() { configConvertTo = "" }()
+
+	_, err := captureConfigOutput(t, func() error {
+		return runConfigConvert(configConvertCmd, nil)
+	})
+	assert.Error(t, err)
+}
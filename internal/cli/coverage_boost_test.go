@@ -94,7 +94,7 @@ func TestAddRepository_SkippedExisting(t *testing.T) {
 	result := addRepository(ws, repoToAdd{
 		URL:  "https://github.com/test/repo.git",
 		Name: "existing-repo",
-	})
+	}, nil)
 
 	// Should skip (status is success, skipped is true)
 	if result.Status == "success" {
@@ -131,7 +131,7 @@ func TestRunSyncPush_WithRepos(t *testing.T) {
 	syncVerbose = false
 
 	// Will fail during push but tests more code paths
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	// Error expected since repo isn't a real git repo
 	if err != nil {
@@ -165,7 +165,7 @@ func TestRunSyncPush_JSONModeWithRepos(t *testing.T) {
 	syncJSON = true
 
 	// Will fail but tests JSON output path
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	if err != nil {
 		t.Logf("Expected error: %v", err)
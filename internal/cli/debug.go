@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/trace"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var debugJSON bool
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Inspect what fa did",
+	Long: `Inspect what fa did in this workspace.
+
+Every fa command run in a workspace records the git commands and other
+subprocesses it ran in .foundagent/logs/, keeping the last 20 runs. Use
+--trace to also print the records to stderr as the command runs, or set
+FA_TRACE=<file> to append them to a file.`,
+}
+
+var debugLastRunCmd = &cobra.Command{
+	Use:   "last-run",
+	Short: "Show the subprocesses the previous fa command ran",
+	Long: `Show each git command and other subprocess the previous fa command in this
+workspace ran: its arguments, directory, duration and exit code, and the end
+of its stderr if it failed.`,
+	Example: `  # Show what the last command did
+  fa debug last-run
+
+  # The raw trace records
+  fa debug last-run --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noRunLogAnnotation: ""},
+	RunE:        runDebugLastRun,
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugLastRunCmd)
+
+	debugLastRunCmd.Flags().BoolVar(&debugJSON, "json", false, "Output the trace records as JSON")
+}
+
+func runDebugLastRun(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printDebugError(err)
+	}
+
+	logs, _ := trace.RunLogs(ws.LogsPath())
+	if len(logs) == 0 {
+		return printDebugError(errors.New(
+			errors.ErrCodeFileNotFound,
+			"No runs have been logged in this workspace",
+			"Run an fa command in the workspace first; its trace is kept in "+filepath.Join(workspace.FoundagentDir, workspace.LogsDir),
+		))
+	}
+
+	path := logs[len(logs)-1]
+	records, err := trace.Read(path)
+	if err != nil {
+		return printDebugError(errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to read the run log",
+			"Check that you have read permissions in the .foundagent directory",
+			err,
+		))
+	}
+
+	if debugJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"log":     path,
+			"records": records,
+		})
+	}
+
+	printTrace(records)
+	return nil
+}
+
+// printTrace prints a run's records, one subprocess per line
+func printTrace(records []trace.Record) {
+	var cwd string
+	commands, failed := 0, 0
+
+	for _, r := range records {
+		if r.Type == trace.TypeRun {
+			cwd = r.Cwd
+			output.PrintMessage("Run: %s", commandLine(r.Argv))
+			output.PrintMessage("  in %s at %s", r.Cwd, r.Time.Local().Format(time.DateTime))
+			output.PrintMessage("")
+			continue
+		}
+
+		commands++
+		symbol := "✓"
+		if r.ExitCode != 0 || r.Error != "" {
+			symbol = "✗"
+			failed++
+		}
+
+		line := fmt.Sprintf("%s %8s  exit %-3d  %s", symbol, r.Duration().Round(time.Millisecond), r.ExitCode, commandLine(r.Argv))
+		if r.Cwd != cwd {
+			line += "  (in " + r.Cwd + ")"
+		}
+		output.PrintMessage("%s", line)
+
+		if symbol == "✗" {
+			if r.Error != "" {
+				output.PrintMessage("      %s", r.Error)
+			}
+			for _, l := range strings.Split(r.Stderr, "\n") {
+				if l = strings.TrimSpace(l); l != "" {
+					output.PrintMessage("      %s", l)
+				}
+			}
+		}
+	}
+
+	output.PrintMessage("")
+	output.PrintMessage("%d command(s), %d failed", commands, failed)
+}
+
+// commandLine joins argv into a command line, quoting arguments with spaces
+func commandLine(argv []string) string {
+	parts := make([]string, len(argv))
+	for i, arg := range argv {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts[i] = arg
+	}
+	return strings.Join(parts, " ")
+}
+
+func printDebugError(err error) error {
+	if debugJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
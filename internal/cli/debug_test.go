@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/trace"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDebugLastRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+
+	ws, err := workspace.New("test-ws", tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	require.NoError(t, os.Chdir(ws.Path))
+
+	// No runs logged yet
+	err = runDebugLastRun(debugLastRunCmd, nil)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeFileNotFound, faErr.Code)
+
+	require.NoError(t, trace.AddRunLog(ws.LogsPath()))
+	trace.Run([]string{"fa", "sync"})
+	trace.Exec([]string{"git", "fetch", "origin"}, ws.Path, time.Now(), nil, nil)
+	trace.Write(trace.Record{
+		Type:     trace.TypeExec,
+		Argv:     []string{"git", "pull"},
+		Cwd:      ws.Path,
+		ExitCode: 1,
+		Stderr:   "fatal: Not possible to fast-forward, aborting.",
+	})
+	trace.Close()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	err = runDebugLastRun(debugLastRunCmd, nil)
+
+	w.Close()
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	output := buf.String()
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "Run: fa sync")
+	assert.Contains(t, output, "git fetch origin")
+	assert.Contains(t, output, "✗")
+	assert.Contains(t, output, "fatal: Not possible to fast-forward")
+	assert.Contains(t, output, "2 command(s), 1 failed")
+}
+
+func TestCommandLine(t *testing.T) {
+	assert.Equal(t, "git status", commandLine([]string{"git", "status"}))
+	assert.Equal(t, `git commit -m "fix bug" ""`, commandLine([]string{"git", "commit", "-m", "fix bug", ""}))
+}
@@ -29,8 +29,12 @@ Examples:
   fa doctor --json
 
   # Auto-fix fixable issues
-  fa doctor --fix`,
-	RunE: runDoctor,
+  fa doctor --fix
+
+  # Show what --fix would change, without changing it
+  fa doctor --dry-run`,
+	Annotations: map[string]string{lockAnnotation: "fix"},
+	RunE:        runDoctor,
 }
 
 var (
@@ -45,6 +49,7 @@ func init() {
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed check output")
 	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output results as JSON")
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Auto-fix fixable issues")
+	addDryRunFlag(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
@@ -61,7 +66,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	runner := doctor.NewRunner(checks)
 	results := runner.Run()
 
-	// Apply fixes if requested
+	// Apply fixes if requested. A dry run plans them.
+	if dryRun {
+		return printPlan(ws, doctorJSON, fixProblems(results, applyFixes(ws, results)))
+	}
 	if doctorFix {
 		results = applyFixes(ws, results)
 	}
@@ -79,19 +87,23 @@ func buildChecks(ws *workspace.Workspace) []doctor.Check {
 		// Environment checks
 		doctor.GitCheck{},
 		doctor.GitVersionCheck{},
+		doctor.GitLFSCheck{Workspace: ws},
 
 		// Structure checks
 		doctor.WorkspaceStructureCheck{Workspace: ws},
 		doctor.ConfigValidCheck{Workspace: ws},
 		doctor.StateValidCheck{Workspace: ws},
+		doctor.SchemaVersionCheck{Workspace: ws},
 
 		// Repository checks
 		doctor.RepositoriesCheck{Workspace: ws},
 		doctor.OrphanedReposCheck{Workspace: ws},
+		doctor.CloneOptionsCheck{Workspace: ws},
 
 		// Worktree checks
 		doctor.WorktreesCheck{Workspace: ws},
 		doctor.OrphanedWorktreesCheck{Workspace: ws},
+		doctor.SubmodulesCheck{Workspace: ws},
 
 		// Consistency checks
 		doctor.ConfigStateConsistencyCheck{Workspace: ws},
@@ -116,6 +128,21 @@ func applyFixes(ws *workspace.Workspace, results []doctor.CheckResult) []doctor.
 	return fixed
 }
 
+// fixProblems returns the fixes that failed, given the results before and
+// after applyFixes
+func fixProblems(before, after []doctor.CheckResult) []planProblem {
+	var problems []planProblem
+	for i, result := range before {
+		if result.Fixable && result.Status != doctor.StatusPass && after[i].Status != doctor.StatusPass {
+			problems = append(problems, planProblem{
+				Status:  "error",
+				Message: fmt.Sprintf("%s: %s", after[i].Name, after[i].Message),
+			})
+		}
+	}
+	return problems
+}
+
 func outputDoctorJSON(results []doctor.CheckResult) error {
 	summary := doctor.CalculateSummary(results)
 
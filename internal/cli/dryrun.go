@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// dryRun makes a command print what it would do instead of doing it. It is
+// the --dry-run of every command that changes the workspace; undo refuses it,
+// and migrate prints its own plan. Commit and push have dry runs of their own.
+var dryRun bool
+
+// addDryRunFlag gives cmd the --dry-run flag
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the git commands and file changes this would make, without making them")
+}
+
+// startDryRun sends the git commands that would change a repository, and
+// the file writes of this run, to the plan instead of carrying them out.
+// Repos are worked on one at a time so the plan reads in order.
+func startDryRun() {
+	dryrun.Start()
+	git.SetExecutor(git.DryRun{
+		Next: git.Subprocess{},
+		Record: func(inv git.Invocation) {
+			dryrun.Run(append([]string{"git"}, inv.Args...), inv.Dir)
+		},
+	})
+	workspace.SetJobs(1)
+}
+
+// planProblem is a repo a dry run found would fail, be skipped, or need
+// attention
+type planProblem struct {
+	Repo    string `json:"repo,omitempty"` // Empty for problems of no one repo
+	Status  string `json:"status"`         // error, warning, skipped
+	Message string `json:"message"`
+}
+
+// printPlan prints the plan of a dry run: as JSON with the steps' absolute
+// paths, or one step per line with paths relative to the workspace. Problems
+// with the status "error" make the dry run fail, as the real run would.
+func printPlan(ws *workspace.Workspace, jsonMode bool, problems []planProblem) error {
+	steps := dryrun.Steps()
+	if steps == nil {
+		steps = []dryrun.Step{}
+	}
+	if problems == nil {
+		problems = []planProblem{}
+	}
+
+	failed := 0
+	for _, p := range problems {
+		if p.Status == "error" {
+			failed++
+		}
+	}
+
+	if jsonMode {
+		if err := output.PrintJSON(map[string]interface{}{
+			"dry_run":  true,
+			"steps":    steps,
+			"problems": problems,
+		}); err != nil {
+			return err
+		}
+	} else {
+		printSteps(ws, steps)
+		if len(problems) > 0 {
+			output.PrintMessage("")
+		}
+		for _, p := range problems {
+			message := p.Message
+			if p.Repo != "" {
+				message = p.Repo + ": " + message
+			}
+			switch p.Status {
+			case "error":
+				output.PrintErrorMessage("✗ %s", message)
+			case "warning":
+				output.PrintErrorMessage("Warning: %s", message)
+			default:
+				output.PrintMessage("⊘ %s", message)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("dry run: %d repository(ies) would fail", failed)
+	}
+	return nil
+}
+
+// printSteps prints a plan one step per line, with the diffs of file edits
+// indented below them
+func printSteps(ws *workspace.Workspace, steps []dryrun.Step) {
+	if len(steps) == 0 {
+		output.PrintMessage("Dry run: nothing to do")
+		return
+	}
+
+	output.PrintMessage("Dry run, nothing was changed. fa would:")
+	for _, step := range steps {
+		var line string
+		switch step.Action {
+		case dryrun.ActionRun:
+			argv := make([]string, len(step.Argv))
+			for i, arg := range step.Argv {
+				argv[i] = relativeToWorkspace(ws, arg)
+			}
+			line = commandLine(argv)
+			if step.Dir != "" {
+				line += "  (in " + relativeToWorkspace(ws, step.Dir) + ")"
+			}
+		case dryrun.ActionCopy, dryrun.ActionSymlink:
+			line = relativeToWorkspace(ws, step.Path) + " <- " + relativeToWorkspace(ws, step.Source)
+		default:
+			line = relativeToWorkspace(ws, step.Path)
+		}
+		output.PrintMessage("  %-8s %s", step.Action, line)
+
+		for _, l := range strings.Split(strings.TrimSuffix(step.Diff, "\n"), "\n") {
+			if l != "" {
+				output.PrintMessage("             %s", l)
+			}
+		}
+	}
+}
+
+// relativeToWorkspace shortens the workspace paths in s, such as a path or a
+// --git-dir= option, to paths relative to the workspace root
+func relativeToWorkspace(ws *workspace.Workspace, s string) string {
+	if s == ws.Path {
+		return "."
+	}
+	return strings.ReplaceAll(s, ws.Path+string(filepath.Separator), "")
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestDryRun starts a dry run, as --dry-run does, until the test ends
+func startTestDryRun(t *testing.T) {
+	t.Helper()
+	dryRun = true
+	startDryRun()
+	t.Cleanup(func() {
+		dryRun = false
+		dryrun.Stop()
+		git.SetExecutor(nil)
+		workspace.SetJobs(0)
+	})
+}
+
+func TestDryRun_AddRepository(t *testing.T) {
+	source := createLocalGitRepo(t, "api")
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	stateBefore, err := os.ReadFile(ws.StatePath())
+	require.NoError(t, err)
+
+	startTestDryRun(t)
+	result := addRepository(ws, repoToAdd{URL: "file://" + source}, nil)
+	require.Equal(t, "success", result.Status, result.Error)
+
+	steps := dryrun.Steps()
+	require.NotEmpty(t, steps)
+	assert.Equal(t, []string{"git", "clone", "--bare", "file://" + source, ws.BareRepoPath("api")}, steps[0].Argv)
+
+	var edited []string
+	for _, step := range steps {
+		if step.Action == dryrun.ActionEdit {
+			edited = append(edited, step.Path)
+		}
+	}
+	assert.Contains(t, edited, ws.StatePath())
+	assert.Contains(t, edited, ws.VSCodeWorkspacePath())
+
+	// Nothing was cloned or written
+	assert.NoDirExists(t, ws.BareRepoPath("api"))
+	stateAfter, err := os.ReadFile(ws.StatePath())
+	require.NoError(t, err)
+	assert.Equal(t, string(stateBefore), string(stateAfter))
+}
+
+func TestDryRun_WorktreeCreateAndRemove(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+	startTestDryRun(t)
+
+	result := createWorktreeForRepo(ws, repos[1], "feature", "", false)
+	require.Equal(t, "success", result.Status, result.Error)
+
+	removed := removeWorktreesParallel(ws, config.DefaultConfig("test-ws"), []worktreeToRemove{{
+		RepoName:     "api",
+		Branch:       "feature",
+		WorktreePath: ws.WorktreePath("api", "feature"),
+		BareRepoPath: ws.BareRepoPath("api"),
+	}})
+	require.Len(t, removed, 1)
+	assert.Equal(t, "removed", removed[0].Status, removed[0].Error)
+
+	assert.Equal(t, []dryrun.Step{
+		{
+			Action: dryrun.ActionRun,
+			Argv:   []string{"git", "--git-dir=" + ws.BareRepoPath("lib"), "worktree", "add", "-b", "feature", ws.WorktreePath("lib", "feature"), "main"},
+		},
+		{
+			Action: dryrun.ActionRun,
+			Argv:   []string{"git", "--git-dir=" + ws.BareRepoPath("api"), "worktree", "remove", ws.WorktreePath("api", "feature")},
+		},
+	}, dryrun.Steps())
+
+	// The worktrees are as they were
+	assert.NoDirExists(t, ws.WorktreePath("lib", "feature"))
+	assert.DirExists(t, ws.WorktreePath("api", "feature"))
+}
+
+func TestDryRun_EveryWorkspaceCommand(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if _, ok := cmd.Annotations[lockAnnotation]; ok {
+			assert.NotNil(t, cmd.Flags().Lookup("dry-run"), cmd.CommandPath())
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestDryRun_Extend(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = repos
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(ws.Path))
+	selectRepos = []string{"lib"}
+	defer func() { selectRepos = nil }()
+
+	startTestDryRun(t)
+	out, err := captureConfigOutput(t, func() error { return runExtend(extendCmd, []string{"feature"}) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "git --git-dir=repos/lib/.bare worktree add -b feature repos/lib/worktrees/feature main")
+	assert.NotContains(t, out, "Created worktree")
+	assert.NoDirExists(t, ws.WorktreePath("lib", "feature"))
+}
+
+func TestPrintPlan(t *testing.T) {
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	startTestDryRun(t)
+	dryrun.Run([]string{"git", "--git-dir=" + ws.BareRepoPath("api"), "fetch"}, "")
+	dryrun.WriteFile(ws.StatePath(), []byte("{}\n"))
+	problems := []planProblem{
+		{Repo: "web", Status: "skipped", Message: "uncommitted changes"},
+		{Repo: "lib", Status: "error", Message: "branch not found"},
+	}
+
+	out, err := captureConfigOutput(t, func() error { return printPlan(ws, false, problems) })
+	assert.EqualError(t, err, "dry run: 1 repository(ies) would fail")
+	assert.Contains(t, out, "Dry run, nothing was changed")
+	assert.Contains(t, out, "  run      git --git-dir=repos/api/.bare fetch\n")
+	assert.Contains(t, out, "  edit     .foundagent/state.json\n")
+	assert.Contains(t, out, "+{}")
+	assert.Contains(t, out, "⊘ web: uncommitted changes")
+	assert.NotContains(t, out, ws.Path)
+
+	out, err = captureConfigOutput(t, func() error { return printPlan(ws, true, nil) })
+	require.NoError(t, err)
+	var plan struct {
+		DryRun   bool          `json:"dry_run"`
+		Steps    []dryrun.Step `json:"steps"`
+		Problems []planProblem `json:"problems"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &plan))
+	assert.True(t, plan.DryRun)
+	require.Len(t, plan.Steps, 2)
+	assert.Equal(t, ws.StatePath(), plan.Steps[1].Path)
+	assert.NotNil(t, plan.Problems)
+
+	dryrun.Start()
+	out, err = captureConfigOutput(t, func() error { return printPlan(ws, false, nil) })
+	require.NoError(t, err)
+	assert.Equal(t, "Dry run: nothing to do", strings.TrimSpace(out))
+}
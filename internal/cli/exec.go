@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [--branch <branch>] -- <command> [args...]",
+	Short: "Run a command in every worktree of a branch",
+	Long: `Run an arbitrary command in each repo's worktree for a branch, in parallel.
+
+The command runs with the worktree as its working directory and with
+FA_WORKSPACE, FA_REPO and FA_BRANCH set in its environment. Output is grouped
+by repo once each command finishes, or streamed live with a [repo] prefix
+when --prefix is used.
+
+By default every repo runs to completion, even if some fail. With --fail-fast
+the remaining commands are cancelled as soon as one fails.
+
+Examples:
+  # Run tests in every worktree of the current branch
+  fa exec -- go test ./...
+
+  # Run in a specific branch's worktrees
+  fa exec --branch feature-123 -- make lint
+
+  # Stream output live, stop at the first failure
+  fa exec --prefix --fail-fast -- npm ci
+
+  # Use a shell for pipes and globs
+  fa exec -- sh -c 'git log --oneline | head -3'
+
+  # JSON output with per-repo exit codes
+  fa exec --json -- go vet ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+var (
+	execBranch   string
+	execFailFast bool
+	execPrefix   bool
+	execJSON     bool
+)
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().StringVarP(&execBranch, "branch", "b", "", "Branch whose worktrees to run in (defaults to current)")
+	execCmd.Flags().BoolVar(&execFailFast, "fail-fast", false, "Cancel remaining repos after the first failure")
+	execCmd.Flags().BoolVar(&execPrefix, "prefix", false, "Stream output live, prefixed with the repo name")
+	execCmd.Flags().BoolVar(&execJSON, "json", false, "Output results as JSON")
+	addRepoSelectionFlags(execCmd)
+	_ = execCmd.RegisterFlagCompletionFunc("branch", getBranchCompletions)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printExecError(err)
+	}
+
+	opts := workspace.ExecOptions{
+		Branch:    execBranch,
+		Command:   args,
+		Selection: repoSelector(),
+		FailFast:  execFailFast,
+	}
+	if execPrefix && !execJSON {
+		opts.Stream = os.Stdout
+	}
+
+	results, err := ws.ExecAll(commandContext(cmd), opts)
+	if err != nil {
+		return printExecError(err)
+	}
+
+	if len(results) == 0 {
+		if execJSON {
+			return outputExecJSON(results, args)
+		}
+		fmt.Println("No repositories configured in workspace")
+		fmt.Println("Add repositories with: fa add <url>")
+		return nil
+	}
+
+	if execJSON {
+		if err := outputExecJSON(results, args); err != nil {
+			return err
+		}
+		return checkExecFailures(workspace.CalculateExecSummary(results))
+	}
+
+	// Grouped output, in repo order
+	if !execPrefix {
+		for _, r := range results {
+			if r.Status != workspace.ExecStatusSucceeded && r.Status != workspace.ExecStatusFailed {
+				continue
+			}
+			fmt.Printf("==> %s (%s)\n", r.RepoName, r.Path)
+			if r.Output != "" {
+				fmt.Print(r.Output)
+				if !strings.HasSuffix(r.Output, "\n") {
+					fmt.Println()
+				}
+			}
+			fmt.Println()
+		}
+	}
+
+	fmt.Print(workspace.FormatExecResults(results))
+
+	summary := workspace.CalculateExecSummary(results)
+	fmt.Printf("\nSummary: %d succeeded, %d failed, %d skipped", summary.Succeeded, summary.Failed, summary.Skipped)
+	if summary.Cancelled > 0 {
+		fmt.Printf(", %d cancelled", summary.Cancelled)
+	}
+	fmt.Println()
+
+	return checkExecFailures(summary)
+}
+
+func checkExecFailures(summary workspace.ExecSummary) error {
+	if summary.Failed > 0 {
+		return fmt.Errorf("command failed in %d repository(ies)", summary.Failed)
+	}
+	return nil
+}
+
+func printExecError(err error) error {
+	if execJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
+
+func outputExecJSON(results []workspace.ExecResult, command []string) error {
+	output := struct {
+		Command []string               `json:"command"`
+		Repos   []workspace.ExecResult `json:"repos"`
+		Summary workspace.ExecSummary  `json:"summary"`
+	}{
+		Command: command,
+		Repos:   results,
+		Summary: workspace.CalculateExecSummary(results),
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
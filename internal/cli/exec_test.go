@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetExecFlags() {
+	execBranch = ""
+	execFailFast = false
+	execPrefix = false
+	execJSON = false
+	selectRepos = nil
+	selectGroups = nil
+	selectTags = nil
+}
+
+func setupExecTestWorkspace(t *testing.T, repos ...string) *workspace.Workspace {
+	t.Helper()
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	state := &workspace.State{Repositories: map[string]*workspace.Repository{}}
+	for _, name := range repos {
+		state.Repositories[name] = &workspace.Repository{Name: name, Worktrees: []string{"main"}}
+		require.NoError(t, os.MkdirAll(ws.WorktreePath(name, "main"), 0755))
+	}
+	require.NoError(t, ws.SaveState(state))
+	return ws
+}
+
+func captureExecOutput(t *testing.T, args []string) (string, error) {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runExec(execCmd, args)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	return buf.String(), err
+}
+
+func TestExecCommand_Grouped(t *testing.T) {
+	resetExecFlags()
+	ws := setupExecTestWorkspace(t, "api", "web")
+
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	os.Chdir(ws.Path)
+
+	output, err := captureExecOutput(t, []string{"sh", "-c", "echo hello from $FA_REPO"})
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "==> api")
+	assert.Contains(t, output, "hello from api")
+	assert.Contains(t, output, "hello from web")
+	assert.Contains(t, output, "Summary: 2 succeeded, 0 failed, 0 skipped")
+}
+
+func TestExecCommand_JSONWithFailure(t *testing.T) {
+	resetExecFlags()
+	execJSON = true
+	defer resetExecFlags()
+	ws := setupExecTestWorkspace(t, "api", "web")
+
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	os.Chdir(ws.Path)
+
+	output, err := captureExecOutput(t, []string{"sh", "-c", `[ "$FA_REPO" = web ] && exit 1; exit 0`})
+	assert.Error(t, err)
+
+	var result struct {
+		Command []string               `json:"command"`
+		Repos   []workspace.ExecResult `json:"repos"`
+		Summary workspace.ExecSummary  `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Repos, 2)
+	assert.Equal(t, 1, result.Repos[1].ExitCode)
+	assert.Equal(t, 1, result.Summary.Failed)
+	assert.Equal(t, 1, result.Summary.Succeeded)
+}
+
+func TestExecCommand_JSONError(t *testing.T) {
+	resetExecFlags()
+	execJSON = true
+	selectGroups = []string{"missing"}
+	defer resetExecFlags()
+	ws := setupExecTestWorkspace(t, "api")
+
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	os.Chdir(ws.Path)
+
+	output, err := captureExecOutput(t, []string{"true"})
+	require.Error(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "error", result["status"])
+	assert.Contains(t, result["error"], "missing")
+}
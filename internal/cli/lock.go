@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Pin every repo to its current commit in a lock file",
+	Long: `Record the URL, branch and exact HEAD commit of each repo's worktree for a
+branch in a lock file (.foundagent.lock at the workspace root by default).
+
+The lock captures a known-good state of the whole multi-repo system. Use
+'fa restore --lock <file>' to recreate worktrees at the pinned commits, for
+example to reproduce a bug report or a CI run.
+
+Uncommitted changes are not captured; repos with local modifications are
+marked dirty in the lock file.
+
+Examples:
+  # Lock the current branch
+  fa lock
+
+  # Lock a specific branch to a custom file
+  fa lock --branch release-2.0 --output release.lock
+
+  # JSON output
+  fa lock --json`,
+	Args:        cobra.NoArgs,
+	Annotations: locksWorkspace,
+	RunE:        runLock,
+}
+
+var (
+	lockBranch string
+	lockOutput string
+	lockJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+
+	lockCmd.Flags().StringVarP(&lockBranch, "branch", "b", "", "Branch whose worktrees to lock (defaults to current)")
+	lockCmd.Flags().StringVarP(&lockOutput, "output", "o", "", "Lock file path (defaults to .foundagent.lock in the workspace)")
+	lockCmd.Flags().BoolVar(&lockJSON, "json", false, "Output result as JSON")
+	addRepoSelectionFlags(lockCmd)
+	addDryRunFlag(lockCmd)
+	_ = lockCmd.RegisterFlagCompletionFunc("branch", getBranchCompletions)
+}
+
+func runLock(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printLockError(err)
+	}
+
+	lock, skipped, err := ws.CreateLock(lockBranch, repoSelector())
+	if err != nil {
+		return printLockError(err)
+	}
+
+	path := lockOutput
+	if path == "" {
+		path = ws.LockPath()
+	}
+
+	if err := workspace.SaveLock(path, lock); err != nil {
+		return printLockError(err)
+	}
+
+	if dryRun {
+		var problems []planProblem
+		for _, name := range skipped {
+			problems = append(problems, planProblem{Repo: name, Status: "skipped", Message: "no worktree for branch " + lock.Branch})
+		}
+		return printPlan(ws, lockJSON, problems)
+	}
+
+	if lockJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"path":    path,
+			"lock":    lock,
+			"skipped": skipped,
+		})
+	}
+
+	for _, repo := range lock.Repos {
+		if repo.Dirty {
+			output.PrintMessage("✓ %s: %s (uncommitted changes not captured)", repo.Name, repo.SHA)
+		} else {
+			output.PrintMessage("✓ %s: %s", repo.Name, repo.SHA)
+		}
+	}
+	for _, name := range skipped {
+		output.PrintMessage("⊘ %s: no worktree for branch %s", name, lock.Branch)
+	}
+
+	output.PrintMessage("")
+	output.PrintMessage("✓ Locked %d repository(ies) on branch '%s' to %s", len(lock.Repos), lock.Branch, path)
+	return nil
+}
+
+func printLockError(err error) error {
+	if lockJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
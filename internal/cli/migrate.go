@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the workspace config, state and layout",
+	Long: `Upgrade a workspace created by an older version of foundagent.
+
+Older config and state files are upgraded in memory whenever they are loaded,
+but changes on disk, such as moving worktree directories to the current layout,
+only happen when you run this command. Moved worktrees are re-linked with
+'git worktree repair' and the VS Code workspace folders are rewritten.
+
+Examples:
+  # Show what would change
+  fa migrate --dry-run
+
+  # Apply the migration
+  fa migrate
+
+  # JSON output
+  fa migrate --json`,
+	Args:        cobra.NoArgs,
+	Annotations: locksWorkspace,
+	RunE:        runMigrate,
+}
+
+var migrateJSON bool
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the migration without applying it")
+	migrateCmd.Flags().BoolVar(&migrateJSON, "json", false, "Output result as JSON")
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printMigrateError(err)
+	}
+
+	plan, err := ws.PlanMigration()
+	if err != nil {
+		return printMigrateError(err)
+	}
+
+	applied := false
+	if !plan.IsEmpty() && !dryRun {
+		if err := ws.ApplyMigration(plan); err != nil {
+			return printMigrateError(err)
+		}
+		applied = true
+	}
+
+	if migrateJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"plan":    plan,
+			"applied": applied,
+			"dry_run": dryRun,
+		})
+	}
+
+	if plan.IsEmpty() {
+		output.PrintMessage("✓ Workspace is up to date (config v%d, state v%d)", plan.ConfigTo, plan.StateTo)
+		return nil
+	}
+
+	if dryRun {
+		output.PrintMessage("Migration plan:")
+	}
+	for _, action := range plan.Actions {
+		if dryRun {
+			output.PrintMessage("  - %s", action.Description)
+		} else {
+			output.PrintMessage("✓ %s", action.Description)
+		}
+	}
+
+	output.PrintMessage("")
+	if dryRun {
+		output.PrintMessage("Run 'fa migrate' to apply")
+	} else {
+		output.PrintMessage("✓ Migrated workspace to config v%d, state v%d", plan.ConfigTo, plan.StateTo)
+	}
+	return nil
+}
+
+func printMigrateError(err error) error {
+	if migrateJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
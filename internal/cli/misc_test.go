@@ -44,7 +44,7 @@ func TestRunSyncFetch_NoRepos(t *testing.T) {
 		syncVerbose = false
 	}()
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -77,7 +77,7 @@ func TestRunSyncFetch_JSONMode(t *testing.T) {
 	syncJSON = true
 	defer func() { syncJSON = false }()
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -114,7 +114,7 @@ func TestRunSyncPull_NoRepos(t *testing.T) {
 		syncStash = false
 	}()
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	var buf bytes.Buffer
@@ -147,7 +147,7 @@ func TestRunSyncPull_JSONMode(t *testing.T) {
 	syncJSON = true
 	defer func() { syncJSON = false }()
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	var buf bytes.Buffer
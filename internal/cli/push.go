@@ -17,6 +17,9 @@ var pushCmd = &cobra.Command{
 Only repos with commits ahead of their upstream (in the current branch worktrees) 
 are pushed. Repos already up-to-date are skipped.
 
+Repos with remotes in the config push to the same-named branch on their
+push_remote (origin unless set) and track it from then on.
+
 Examples:
   # Push all repos with unpushed commits
   fa push
@@ -38,7 +41,6 @@ Examples:
 
 var (
 	pushDryRun  bool
-	pushRepos   []string
 	pushJSON    bool
 	pushVerbose bool
 	pushForce   bool
@@ -48,8 +50,8 @@ func init() {
 	rootCmd.AddCommand(pushCmd)
 
 	pushCmd.Flags().BoolVar(&pushDryRun, "dry-run", false, "Preview without pushing")
-	pushCmd.Flags().StringArrayVar(&pushRepos, "repo", nil, "Limit to specific repos (can be repeated)")
 	pushCmd.Flags().BoolVar(&pushJSON, "json", false, "Output as JSON")
+	addRepoSelectionFlags(pushCmd)
 	pushCmd.Flags().BoolVarP(&pushVerbose, "verbose", "v", false, "Show detailed progress")
 	pushCmd.Flags().BoolVarP(&pushForce, "force", "f", false, "Force push (dangerous)")
 }
@@ -68,14 +70,14 @@ func runPush(cmd *cobra.Command, args []string) error {
 
 	// Build options
 	opts := workspace.PushOptions{
-		DryRun:  pushDryRun,
-		Repos:   pushRepos,
-		Verbose: pushVerbose,
-		Force:   pushForce,
+		DryRun:    pushDryRun,
+		Selection: repoSelector(),
+		Verbose:   pushVerbose,
+		Force:     pushForce,
 	}
 
 	// Execute push
-	results, err := ws.PushAllReposNew(opts)
+	results, err := ws.PushAllReposNew(commandContext(cmd), opts)
 	if err != nil {
 		return err
 	}
@@ -141,6 +143,11 @@ func printPushHumanOutput(results []workspace.PushResult, summary workspace.Push
 	}
 
 	fmt.Print(workspace.FormatPushResults(results))
+	if pushVerbose {
+		for _, r := range results {
+			printRetries(r.RepoName, r.Retries)
+		}
+	}
 
 	// Summary line
 	fmt.Println()
@@ -92,7 +92,7 @@ func setupPushTestWorkspaceWithRemote(t *testing.T, repoName string) (*workspace
 func TestPushCommand_NothingToPush(t *testing.T) {
 	// Reset flags
 	pushDryRun = false
-	pushRepos = nil
+	selectRepos = nil
 	pushJSON = false
 	pushVerbose = false
 	pushForce = false
@@ -126,7 +126,7 @@ func TestPushCommand_NothingToPush(t *testing.T) {
 func TestPushCommand_WithUnpushedCommits(t *testing.T) {
 	// Reset flags
 	pushDryRun = false
-	pushRepos = nil
+	selectRepos = nil
 	pushJSON = false
 	pushVerbose = false
 	pushForce = false
@@ -170,7 +170,7 @@ func TestPushCommand_WithUnpushedCommits(t *testing.T) {
 func TestPushCommand_JSONOutput(t *testing.T) {
 	// Reset flags
 	pushDryRun = false
-	pushRepos = nil
+	selectRepos = nil
 	pushJSON = true
 	pushVerbose = false
 	pushForce = false
@@ -209,7 +209,7 @@ func TestPushCommand_JSONOutput(t *testing.T) {
 func TestPushCommand_DryRun(t *testing.T) {
 	// Reset flags
 	pushDryRun = true
-	pushRepos = nil
+	selectRepos = nil
 	pushJSON = false
 	pushVerbose = false
 	pushForce = false
@@ -253,7 +253,7 @@ func TestPushCommand_DryRun(t *testing.T) {
 func TestPushCommand_ForceWithJSON(t *testing.T) {
 	// Reset flags - force + json should error
 	pushDryRun = false
-	pushRepos = nil
+	selectRepos = nil
 	pushJSON = true
 	pushVerbose = false
 	pushForce = true
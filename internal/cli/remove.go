@@ -32,10 +32,14 @@ Examples:
   # Remove from config but keep files
   fa remove api --config-only
 
+  # Show what would be deleted, without deleting it
+  fa remove api --dry-run
+
   # JSON output
   fa remove api --json`,
-	Args: cobra.MinimumNArgs(1),
-	RunE: runRepoRemove,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: locksWorkspace,
+	RunE:        runRepoRemove,
 }
 
 var (
@@ -50,6 +54,7 @@ func init() {
 	repoRemoveCmd.Flags().BoolVar(&repoRemoveForce, "force", false, "Force removal despite uncommitted changes")
 	repoRemoveCmd.Flags().BoolVar(&repoRemoveConfigOnly, "config-only", false, "Remove from config but keep files")
 	repoRemoveCmd.Flags().BoolVar(&repoRemoveJSON, "json", false, "Output results as JSON")
+	addDryRunFlag(repoRemoveCmd)
 }
 
 func runRepoRemove(cmd *cobra.Command, args []string) error {
@@ -67,6 +72,15 @@ func runRepoRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output results
+	if dryRun {
+		var problems []planProblem
+		for _, result := range results {
+			if result.Error != "" {
+				problems = append(problems, planProblem{Repo: result.RepoName, Status: "error", Message: result.Error})
+			}
+		}
+		return printPlan(ws, repoRemoveJSON, problems)
+	}
 	if repoRemoveJSON {
 		return outputRemovalJSON(results)
 	}
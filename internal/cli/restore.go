@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore --lock <file>",
+	Short: "Recreate worktrees at the commits pinned in a lock file",
+	Long: `Create a worktree in every repo at the exact commit recorded in a lock file
+written by 'fa lock'.
+
+By default the worktrees are detached at the pinned commits and named
+lock-<timestamp> after the lock's creation time. Use --branch to create a
+named branch at each pinned commit instead. Commits missing locally are
+fetched from origin first.
+
+Every repo in the lock file must already be in the workspace.
+
+Examples:
+  # Restore detached worktrees from the workspace lock file
+  fa restore --lock .foundagent.lock
+
+  # Restore onto a new branch in every repo
+  fa restore --lock ci-run.lock --branch repro-1234
+
+  # JSON output
+  fa restore --lock .foundagent.lock --json`,
+	Args:        cobra.NoArgs,
+	Annotations: locksWorkspace,
+	RunE:        runRestore,
+}
+
+var (
+	restoreLockFile string
+	restoreBranch   string
+	restoreJSON     bool
+)
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().StringVar(&restoreLockFile, "lock", "", "Lock file to restore from")
+	restoreCmd.Flags().StringVarP(&restoreBranch, "branch", "b", "", "Create a named branch at each pinned commit")
+	restoreCmd.Flags().BoolVar(&restoreJSON, "json", false, "Output result as JSON")
+	addDryRunFlag(restoreCmd)
+	_ = restoreCmd.MarkFlagRequired("lock")
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	if restoreBranch != "" {
+		if err := git.ValidateBranchName(restoreBranch); err != nil {
+			return printRestoreError(err)
+		}
+	}
+
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printRestoreError(err)
+	}
+
+	lock, err := workspace.LoadLock(restoreLockFile)
+	if err != nil {
+		return printRestoreError(err)
+	}
+
+	if len(lock.Repos) == 0 {
+		return printRestoreError(errors.New(
+			errors.ErrCodeInvalidInput,
+			"Lock file does not contain any repositories",
+			"Regenerate the lock file with 'fa lock'",
+		))
+	}
+
+	opts := workspace.RestoreOptions{
+		Lock:   lock,
+		Name:   restoreBranch,
+		Detach: restoreBranch == "",
+	}
+	if opts.Detach {
+		opts.Name = "lock-" + lock.CreatedAt.Format("20060102-150405")
+	}
+
+	results, err := ws.RestoreLock(commandContext(cmd), opts)
+	if err != nil {
+		return printRestoreError(err)
+	}
+
+	failed := 0
+	var worktreePaths []string
+	for _, r := range results {
+		if r.Status == workspace.RestoreStatusFailed {
+			failed++
+		} else {
+			worktreePaths = append(worktreePaths, r.WorktreePath)
+		}
+	}
+
+	if err := ws.AddWorktreeFolders(worktreePaths); err != nil && !restoreJSON {
+		output.PrintErrorMessage("Warning: Failed to update VS Code workspace: %v", err)
+	}
+
+	if dryRun {
+		var problems []planProblem
+		for _, r := range results {
+			switch {
+			case r.Status == workspace.RestoreStatusFailed:
+				problems = append(problems, planProblem{Repo: r.RepoName, Status: "error", Message: r.Error})
+			case r.Warning != "":
+				problems = append(problems, planProblem{Repo: r.RepoName, Status: "warning", Message: r.Warning})
+			}
+		}
+		return printPlan(ws, restoreJSON, problems)
+	}
+
+	if restoreJSON {
+		if err := output.PrintJSON(map[string]interface{}{
+			"name":     opts.Name,
+			"detached": opts.Detach,
+			"repos":    results,
+		}); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Status == workspace.RestoreStatusCreated {
+				output.PrintMessage("✓ %s: %s at %s", r.RepoName, r.WorktreePath, r.SHA)
+				if r.Warning != "" {
+					output.PrintErrorMessage("  Warning: %s", r.Warning)
+				}
+			} else {
+				output.PrintErrorMessage("✗ %s: %s", r.RepoName, r.Error)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to restore %d repository(ies)", failed)
+	}
+
+	if !restoreJSON {
+		output.PrintMessage("")
+		output.PrintMessage("✓ Restored %d repository(ies) into worktree '%s'", len(results), opts.Name)
+		output.PrintMessage("Switch to it with: fa wt switch %s", opts.Name)
+	}
+	return nil
+}
+
+func printRestoreError(err error) error {
+	if restoreJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
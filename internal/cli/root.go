@@ -1,9 +1,23 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/progress"
+	"github.com/foundagent/foundagent/internal/trace"
 	"github.com/foundagent/foundagent/internal/version"
+	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -12,18 +26,113 @@ var rootCmd = &cobra.Command{
 	Short: "Foundagent - Git worktree workspace manager",
 	Long: `Foundagent is a CLI tool for managing multi-repository workspaces
 using git worktrees and VS Code integration.`,
+	PersistentPreRunE: preRun,
+	PersistentPostRun: func(cmd *cobra.Command, args []string) { releaseWorkspaceLock() },
 }
 
-var showVersion bool
+var (
+	showVersion bool
+
+	// jobs limits how many repos are worked on at once; 0 defers to
+	// settings.jobs
+	jobs int
+
+	// nonInteractive makes git fail rather than prompt for credentials
+	nonInteractive bool
+
+	// traceStderr prints a trace record of every subprocess to stderr
+	traceStderr bool
+)
+
+// traceEnv names a file to append trace records to
+const traceEnv = "FA_TRACE"
+
+// noRunLogAnnotation marks commands whose runs are not kept in the
+// workspace's run logs, such as 'fa debug last-run', which would otherwise
+// find its own run rather than the previous one
+const noRunLogAnnotation = "no_run_log"
+
+// noJournalAnnotation marks commands that take the workspace lock but are not
+// recorded in the journal, such as 'fa undo', which would otherwise undo its
+// own run rather than the command before it
+const noJournalAnnotation = "no_journal"
+
+// Repository selection flags shared by every command
+var (
+	selectRepos  []string
+	selectGroups []string
+	selectTags   []string
+)
+
+// lockAnnotation marks commands that change the workspace. They hold the
+// workspace lock while they run, so concurrent fa processes cannot lose each
+// other's updates. A non-empty value names a bool flag that the command only
+// needs the lock with, such as doctor's --fix.
+const lockAnnotation = "locks_workspace"
+
+// locksWorkspace annotates commands that always take the workspace lock
+var locksWorkspace = map[string]string{lockAnnotation: ""}
+
+var (
+	lockTimeout time.Duration
+
+	// workspaceLock is the lock held by the running command, if any
+	workspaceLock *workspace.ProcessLock
+)
 
 // Execute runs the root command
 func Execute() error {
-	return rootCmd.Execute()
+	// PersistentPostRun is skipped when a command fails
+	defer releaseWorkspaceLock()
+	defer trace.Close()
+
+	ctx, stop := interruptContext()
+	defer stop()
+	git.SetContext(ctx)
+
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// interruptContext returns a context cancelled by the first Ctrl-C or
+// SIGTERM, which stops the running git commands and lets the command report
+// and release the workspace lock. A second Ctrl-C exits immediately.
+func interruptContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			fmt.Fprintln(os.Stderr, "Interrupted, stopping running git commands (press Ctrl-C again to force)")
+			cancel(fmt.Errorf("interrupted"))
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(nil)
+	}
+}
+
+// commandContext returns the context a command runs under, which is
+// cancelled when fa is interrupted
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd != nil && cmd.Context() != nil {
+		return cmd.Context()
+	}
+	return context.Background()
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVar(&showVersion, "version", false, "Show version information")
+	rootCmd.PersistentFlags().IntVar(&jobs, "jobs", 0, fmt.Sprintf("Maximum number of repos to work on at once (default settings.jobs, or %d)", workspace.DefaultJobs))
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Fail instead of letting git prompt for credentials or host keys (implied by --json)")
+	rootCmd.PersistentFlags().BoolVar(&traceStderr, "trace", false, "Print every git command and other subprocess to stderr as JSON lines")
+	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", workspace.DefaultLockTimeout, "How long to wait for another fa process to finish changing the workspace")
 
 	// Override RunE to handle --version flag
 	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -34,3 +143,171 @@ func init() {
 		return cmd.Help()
 	}
 }
+
+// addRepoSelectionFlags gives cmd the --repo, --group and --tag flags.
+// Commands that do not select repos leave them out, so they reject them as
+// unknown flags rather than ignore them.
+func addRepoSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&selectRepos, "repo", nil, "Limit to specific repos (can be repeated)")
+	cmd.Flags().StringArrayVar(&selectGroups, "group", nil, "Limit to repos in a group (can be repeated)")
+	cmd.Flags().StringArrayVar(&selectTags, "tag", nil, "Limit to repos with a tag (can be repeated)")
+	_ = cmd.RegisterFlagCompletionFunc("repo", getRepoCompletions)
+	_ = cmd.RegisterFlagCompletionFunc("group", getGroupCompletions)
+	_ = cmd.RegisterFlagCompletionFunc("tag", getTagCompletions)
+}
+
+// repoSelector returns the repository selection from the --repo, --group and
+// --tag flags
+func repoSelector() config.Selector {
+	return config.Selector{
+		Repos:  selectRepos,
+		Groups: selectGroups,
+		Tags:   selectTags,
+	}
+}
+
+// newProgress returns a renderer for each repo's progress, or nil when the
+// output is JSON or the run is a dry run
+func newProgress(jsonMode bool) *progress.Renderer {
+	if jsonMode || dryRun {
+		return nil
+	}
+	return progress.Stdout()
+}
+
+// preRun applies the global flags and takes the workspace lock
+func preRun(cmd *cobra.Command, args []string) error {
+	if jobs < 0 {
+		return errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("--jobs cannot be negative: %d", jobs),
+			"Use a positive number of repos, or omit --jobs for the default",
+		)
+	}
+	workspace.SetJobs(jobs)
+	if dryRun {
+		startDryRun()
+	}
+	startTrace(cmd)
+	configureGit(cmd)
+
+	return lockWorkspace(cmd, args)
+}
+
+// startTrace records the subprocesses of this run: to stderr with --trace, to
+// the file named by FA_TRACE, and to the workspace's run logs, which 'fa debug
+// last-run' reads
+func startTrace(cmd *cobra.Command) {
+	if traceStderr {
+		trace.AddWriter(os.Stderr)
+	}
+	if path := os.Getenv(traceEnv); path != "" {
+		if err := trace.AddFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot write trace to %s: %v\n", path, err)
+		}
+	}
+
+	// Shell completion runs on every tab press and would push real runs out
+	// of the logs, and a dry run leaves the workspace as it was
+	_, noRunLog := cmd.Annotations[noRunLogAnnotation]
+	completion := cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+	if !noRunLog && !completion && !dryRun {
+		if ws, err := workspace.Discover(""); err == nil {
+			_ = trace.AddRunLog(ws.LogsPath())
+		}
+	}
+
+	trace.Run(append([]string{"fa"}, os.Args[1:]...))
+}
+
+// configureGit applies the workspace's git timeouts and retries, and turns
+// off git's prompts when nobody may be there to answer them
+func configureGit(cmd *cobra.Command) {
+	jsonMode, _ := cmd.Flags().GetBool("json")
+	git.SetNonInteractive(nonInteractive || jsonMode)
+
+	var timeouts git.Timeouts
+	retries := git.DefaultRetries
+	if ws, err := workspace.Discover(""); err == nil {
+		if cfg, err := config.Load(ws.Path); err == nil {
+			timeouts = gitTimeouts(cfg.Settings.Timeouts)
+			if cfg.Settings.Retries != 0 {
+				retries = cfg.Settings.Retries
+			}
+		}
+	}
+	git.SetTimeouts(timeouts)
+	git.SetRetries(retries)
+}
+
+// gitTimeouts converts the configured timeouts. Load has already validated
+// them, and unset ones are left zero for the defaults.
+func gitTimeouts(cfg config.TimeoutsConfig) git.Timeouts {
+	parse := func(value string) time.Duration {
+		d, _ := time.ParseDuration(value)
+		return d
+	}
+	return git.Timeouts{
+		Clone: parse(cfg.Clone),
+		Fetch: parse(cfg.Fetch),
+		Push:  parse(cfg.Push),
+		Local: parse(cfg.Local),
+	}
+}
+
+// lockWorkspace takes the workspace lock for commands annotated with
+// lockAnnotation, and starts the journal entry 'fa undo' reverses them with.
+// Outside a workspace there is nothing to lock, and the
+// command itself reports that. A dry run changes nothing and needs no lock.
+func lockWorkspace(cmd *cobra.Command, args []string) error {
+	flag, ok := cmd.Annotations[lockAnnotation]
+	if !ok || dryRun {
+		return nil
+	}
+	if flag != "" {
+		if on, _ := cmd.Flags().GetBool(flag); !on {
+			return nil
+		}
+	}
+
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return nil
+	}
+
+	lock, err := ws.AcquireLock(lockTimeout)
+	if err != nil {
+		if jsonMode, _ := cmd.Flags().GetBool("json"); jsonMode {
+			_ = output.PrintError(err)
+		}
+		return err
+	}
+	workspaceLock = lock
+
+	if _, noJournal := cmd.Annotations[noJournalAnnotation]; !noJournal {
+		journal.Begin(ws.JournalPath(), append([]string{"fa"}, os.Args[1:]...), journaledFiles(ws))
+	}
+	return nil
+}
+
+// journaledFiles returns the workspace files whose contents the journal keeps,
+// so 'fa undo' can put them back
+func journaledFiles(ws *workspace.Workspace) []string {
+	var files []string
+	for _, name := range config.ConfigFileNames {
+		files = append(files, filepath.Join(ws.Path, name))
+	}
+	return append(files, ws.StatePath(), ws.VSCodeWorkspacePath(), ws.LockPath())
+}
+
+// releaseWorkspaceLock writes the command's journal entry and releases the
+// workspace lock, if the command took it
+func releaseWorkspaceLock() {
+	if err := journal.Commit(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot record this command for 'fa undo': %v\n", err)
+	}
+	if workspaceLock != nil {
+		_ = workspaceLock.Release()
+		workspaceLock = nil
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"os"
 	"testing"
 
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRootCommand(t *testing.T) {
@@ -52,3 +56,66 @@ func TestRootCommand_NoArgs(t *testing.T) {
 	// Should return nil (help is shown)
 	assert.NoError(t, err)
 }
+
+func TestRepoSelectionFlags(t *testing.T) {
+	// Commands that select repos take --repo, --group and --tag
+	for _, cmd := range []*cobra.Command{addCmd, execCmd, lockCmd, statusCmd, syncCmd, createCmd, switchCmd} {
+		for _, name := range []string{"repo", "group", "tag"} {
+			assert.NotNil(t, cmd.Flag(name), "%s --%s", cmd.CommandPath(), name)
+		}
+	}
+
+	// The others reject them rather than ignore them
+	for _, cmd := range []*cobra.Command{initCmd, configSetCmd, undoCmd, migrateCmd, restoreCmd} {
+		for _, name := range []string{"repo", "group", "tag"} {
+			assert.Nil(t, cmd.Flag(name), "%s --%s", cmd.CommandPath(), name)
+		}
+	}
+}
+
+func TestLockWorkspace(t *testing.T) {
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(ws.Path))
+
+	// Commands that change the workspace take the lock until they finish
+	require.NoError(t, lockWorkspace(createCmd, nil))
+	assert.FileExists(t, ws.ProcessLockPath())
+
+	// A second process would have to wait for it
+	held, err := ws.AcquireLock(0)
+	assert.Nil(t, held)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeWorkspaceLocked, faErr.Code)
+
+	releaseWorkspaceLock()
+	assert.NoFileExists(t, ws.ProcessLockPath())
+
+	// Read-only commands, and doctor without --fix, do not lock
+	require.NoError(t, lockWorkspace(statusCmd, nil))
+	require.NoError(t, lockWorkspace(doctorCmd, nil))
+	assert.NoFileExists(t, ws.ProcessLockPath())
+}
+
+func TestPreRun_Jobs(t *testing.T) {
+	defer func() {
+		jobs = 0
+		workspace.SetJobs(0)
+	}()
+
+	jobs = -1
+	err := preRun(statusCmd, nil)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeInvalidInput, faErr.Code)
+
+	jobs = 3
+	require.NoError(t, preRun(statusCmd, nil))
+	ws := &workspace.Workspace{Path: t.TempDir()}
+	assert.Equal(t, 3, ws.Jobs())
+}
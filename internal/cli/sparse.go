@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var sparseCmd = &cobra.Command{
+	Use:   "sparse",
+	Short: "Adjust sparse checkout of existing worktrees",
+	Long: `Adjust which directories an existing sparse worktree checks out.
+
+New worktrees are sparse when the repo declares directories in the config:
+
+  repos:
+    - url: git@github.com:org/monorepo.git
+      sparse: [services/api, libs/common]
+
+Files at the top of the repository are always checked out. These commands
+change one worktree only; edit the config to change what new worktrees get.`,
+}
+
+var sparseAddCmd = &cobra.Command{
+	Use:   "add <repo> <path>...",
+	Short: "Check out more directories in a sparse worktree",
+	Example: `  # Add a directory to the current branch's worktree
+  fa sparse add monorepo services/web
+
+  # Add to another branch's worktree
+  fa sparse add monorepo services/web --branch feature-123`,
+	Args:              cobra.MinimumNArgs(2),
+	Annotations:       locksWorkspace,
+	RunE:              runSparseAdd,
+	ValidArgsFunction: sparseRepoCompletions,
+}
+
+var sparseRemoveCmd = &cobra.Command{
+	Use:               "remove <repo> <path>...",
+	Aliases:           []string{"rm"},
+	Short:             "Stop checking out directories in a sparse worktree",
+	Example:           `  fa sparse remove monorepo services/web`,
+	Args:              cobra.MinimumNArgs(2),
+	Annotations:       locksWorkspace,
+	RunE:              runSparseRemove,
+	ValidArgsFunction: sparseRepoCompletions,
+}
+
+var (
+	sparseBranch string
+	sparseJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(sparseCmd)
+	sparseCmd.AddCommand(sparseAddCmd, sparseRemoveCmd)
+
+	sparseCmd.PersistentFlags().StringVarP(&sparseBranch, "branch", "b", "", "Branch whose worktree to change (defaults to the worktree you are in, then the current branch)")
+	sparseCmd.PersistentFlags().BoolVar(&sparseJSON, "json", false, "Output result as JSON")
+	_ = sparseCmd.RegisterFlagCompletionFunc("branch", getBranchCompletions)
+	addDryRunFlag(sparseAddCmd)
+	addDryRunFlag(sparseRemoveCmd)
+}
+
+func sparseRepoCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return getRepoCompletions(cmd, args, toComplete)
+}
+
+func runSparseAdd(cmd *cobra.Command, args []string) error {
+	return runSparse(args, "Added", func(ws *workspace.Workspace, repo string, dirs []string) (*workspace.SparseResult, error) {
+		return ws.SparseAdd(repo, sparseBranch, dirs)
+	})
+}
+
+func runSparseRemove(cmd *cobra.Command, args []string) error {
+	return runSparse(args, "Removed", func(ws *workspace.Workspace, repo string, dirs []string) (*workspace.SparseResult, error) {
+		return ws.SparseRemove(repo, sparseBranch, dirs)
+	})
+}
+
+func runSparse(args []string, verb string, change func(*workspace.Workspace, string, []string) (*workspace.SparseResult, error)) error {
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printSparseError(err)
+	}
+
+	result, err := change(ws, args[0], args[1:])
+	if err != nil {
+		return printSparseError(err)
+	}
+
+	if dryRun {
+		return printPlan(ws, sparseJSON, nil)
+	}
+	if sparseJSON {
+		return output.PrintJSON(result)
+	}
+
+	output.PrintMessage("✓ %s %s in %s (%s)", verb, strings.Join(args[1:], ", "), result.Repo, result.Branch)
+	if len(result.Paths) == 0 {
+		output.PrintMessage("  Sparse checkout: top-level files only")
+	} else {
+		output.PrintMessage("  Sparse checkout: %s", strings.Join(result.Paths, ", "))
+	}
+	return nil
+}
+
+func printSparseError(err error) error {
+	if sparseJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
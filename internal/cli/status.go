@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
@@ -36,7 +37,10 @@ Examples:
   fa status -v
 
   # JSON output for AI agents
-  fa status --json`,
+  fa status --json
+
+  # Status of the frontend group only
+  fa status --group frontend`,
 	RunE: runStatus,
 }
 
@@ -46,6 +50,7 @@ func init() {
 	// Flags
 	statusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "v", false, "Show detailed file-level status")
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output in JSON format")
+	addRepoSelectionFlags(statusCmd)
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -56,7 +61,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Collect workspace status
-	status, err := ws.GetWorkspaceStatus(statusVerbose)
+	status, err := ws.GetWorkspaceStatusFor(repoSelector(), statusVerbose)
 	if err != nil {
 		return err
 	}
@@ -178,8 +183,31 @@ func outputStatusHuman(status *workspace.WorkspaceStatus, verbose bool) error {
 					currentMarker = "*"
 				}
 
+				// Sparse checkout marker
+				if wt.Sparse {
+					statusIndicator += " \033[36m[sparse]\033[0m"
+				}
+
+				// Submodule drift marker
+				if len(wt.SubmoduleDrift) > 0 {
+					statusIndicator += " \033[33m[submodules]\033[0m"
+				}
+
 				fmt.Printf("   %s %s%s\n", currentMarker, wt.Repo, statusIndicator)
 
+				// Verbose mode - show sparse directories
+				if verbose && wt.Sparse {
+					fmt.Printf("      Sparse checkout: %s\n", strings.Join(wt.SparsePaths, ", "))
+				}
+
+				// Verbose mode - show submodules that need updating
+				if verbose && len(wt.SubmoduleDrift) > 0 {
+					fmt.Printf("      Submodules out of sync:\n")
+					for _, sub := range wt.SubmoduleDrift {
+						fmt.Printf("        %s (%s)\n", sub.Path, sub.State)
+					}
+				}
+
 				// Verbose mode - show files (US5)
 				if verbose && len(wt.ModifiedFiles) > 0 {
 					fmt.Printf("      Modified files:\n")
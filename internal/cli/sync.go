@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/foundagent/foundagent/internal/git"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -31,9 +33,16 @@ Examples:
   fa sync --push
 
   # Stash uncommitted changes before pull
-  fa sync --pull --stash`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runSync,
+  fa sync --pull --stash
+
+  # Fetch only repos in the infra group
+  fa sync --group infra
+
+  # Show what a pull would do, without doing it
+  fa sync --pull --dry-run`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: locksWorkspace,
+	RunE:        runSync,
 }
 
 var (
@@ -51,7 +60,9 @@ func init() {
 	syncCmd.Flags().BoolVar(&syncPush, "push", false, "Push local commits to remotes")
 	syncCmd.Flags().BoolVar(&syncStash, "stash", false, "Stash uncommitted changes before pull")
 	syncCmd.Flags().BoolVar(&syncJSON, "json", false, "Output results as JSON")
+	addRepoSelectionFlags(syncCmd)
 	syncCmd.Flags().BoolVarP(&syncVerbose, "verbose", "v", false, "Show detailed progress")
+	addDryRunFlag(syncCmd)
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
@@ -78,7 +89,7 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 	// Execute sync based on flags
 	if syncPush {
-		return runSyncPush(ws)
+		return runSyncPush(commandContext(cmd), ws)
 	} else if syncPull {
 		// Use current branch if not specified
 		if targetBranch == "" {
@@ -91,22 +102,27 @@ func runSync(cmd *cobra.Command, args []string) error {
 				targetBranch = "main" // Default fallback
 			}
 		}
-		return runSyncPull(ws, targetBranch)
+		return runSyncPull(commandContext(cmd), ws, targetBranch)
 	} else {
 		// Default: fetch only
-		return runSyncFetch(ws)
+		return runSyncFetch(commandContext(cmd), ws)
 	}
 }
 
-func runSyncFetch(ws *workspace.Workspace) error {
+func runSyncFetch(ctx context.Context, ws *workspace.Workspace) error {
 	if syncVerbose {
 		fmt.Println("Fetching from all remotes...")
 	}
 
-	results, err := ws.SyncAllRepos(syncVerbose)
+	opts := syncOptions()
+	results, err := ws.FetchRepos(ctx, opts)
+	opts.Progress.Stop()
 	if err != nil {
 		return err
 	}
+	if dryRun {
+		return printSyncPlan(ws, results)
+	}
 
 	// Handle empty workspace
 	if len(results) == 0 {
@@ -125,6 +141,9 @@ func runSyncFetch(ws *workspace.Workspace) error {
 
 	// Human output
 	fmt.Println(workspace.FormatSyncResults(results, "fetch"))
+	if syncVerbose {
+		printSyncRetries(results)
+	}
 
 	summary := workspace.CalculateSummary(results)
 	fmt.Printf("\nSummary: %d synced, %d failed\n", summary.Synced+summary.Updated, summary.Failed)
@@ -137,15 +156,20 @@ func runSyncFetch(ws *workspace.Workspace) error {
 	return nil
 }
 
-func runSyncPull(ws *workspace.Workspace, branch string) error {
+func runSyncPull(ctx context.Context, ws *workspace.Workspace, branch string) error {
 	if syncVerbose {
 		fmt.Printf("Syncing branch '%s' with --pull...\n", branch)
 	}
 
-	results, err := ws.PullAllWorktrees(branch, syncStash, syncVerbose)
+	opts := syncOptions()
+	results, err := ws.PullWorktrees(ctx, branch, opts)
+	opts.Progress.Stop()
 	if err != nil {
 		return err
 	}
+	if dryRun {
+		return printSyncPlan(ws, results)
+	}
 
 	// Handle empty workspace
 	if len(results) == 0 {
@@ -164,6 +188,9 @@ func runSyncPull(ws *workspace.Workspace, branch string) error {
 
 	// Human output
 	fmt.Println(workspace.FormatSyncResults(results, "pull"))
+	if syncVerbose {
+		printSyncRetries(results)
+	}
 
 	summary := workspace.CalculateSummary(results)
 	fmt.Printf("\nSummary: %d updated, %d skipped, %d failed\n", summary.Updated, summary.Skipped, summary.Failed)
@@ -175,15 +202,20 @@ func runSyncPull(ws *workspace.Workspace, branch string) error {
 	return nil
 }
 
-func runSyncPush(ws *workspace.Workspace) error {
+func runSyncPush(ctx context.Context, ws *workspace.Workspace) error {
 	if syncVerbose {
 		fmt.Println("Pushing local commits...")
 	}
 
-	results, err := ws.PushAllRepos(syncVerbose)
+	opts := syncOptions()
+	results, err := ws.PushRepos(ctx, opts)
+	opts.Progress.Stop()
 	if err != nil {
 		return err
 	}
+	if dryRun {
+		return printSyncPlan(ws, results)
+	}
 
 	// Handle empty workspace
 	if len(results) == 0 {
@@ -202,6 +234,9 @@ func runSyncPush(ws *workspace.Workspace) error {
 
 	// Human output
 	fmt.Println(workspace.FormatSyncResults(results, "push"))
+	if syncVerbose {
+		printSyncRetries(results)
+	}
 
 	summary := workspace.CalculateSummary(results)
 
@@ -220,6 +255,54 @@ func runSyncPush(ws *workspace.Workspace) error {
 	return nil
 }
 
+// printSyncPlan prints the plan of a dry run, with the repos that would fail
+// or be skipped
+func printSyncPlan(ws *workspace.Workspace, results []workspace.SyncResult) error {
+	var problems []planProblem
+	for _, r := range results {
+		status := ""
+		switch r.Status {
+		case workspace.SyncStatusFailed:
+			status = "error"
+		case workspace.SyncStatusSkipped:
+			status = "skipped"
+		default:
+			continue
+		}
+		message := r.Status
+		if r.Error != nil {
+			message = r.Error.Error()
+		}
+		problems = append(problems, planProblem{Repo: r.RepoName, Status: status, Message: message})
+	}
+	return printPlan(ws, syncJSON, problems)
+}
+
+// syncOptions builds sync options from the command flags
+func syncOptions() workspace.SyncOptions {
+	return workspace.SyncOptions{
+		Selection: repoSelector(),
+		Stash:     syncStash,
+		Verbose:   syncVerbose,
+		Progress:  newProgress(syncJSON),
+	}
+}
+
+// printSyncRetries lists the retries of transient network failures, for
+// --verbose output
+func printSyncRetries(results []workspace.SyncResult) {
+	for _, r := range results {
+		printRetries(r.RepoName, r.Retries)
+	}
+}
+
+// printRetries prints a repo's retries, one per line
+func printRetries(name string, retries []git.Retry) {
+	for _, r := range retries {
+		fmt.Printf("  %s: %s\n", name, r)
+	}
+}
+
 func outputSyncJSON(results []workspace.SyncResult) error {
 	summary := workspace.CalculateSummary(results)
 
@@ -35,7 +35,7 @@ func TestRunSyncPull_WithStash(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 
 	syncStash = true
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	var buf bytes.Buffer
@@ -68,7 +68,7 @@ func TestRunSyncFetch_Verbose(t *testing.T) {
 	defer func() { os.Stdout = oldStdout }()
 
 	syncVerbose = true
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	var buf bytes.Buffer
@@ -167,5 +167,5 @@ func TestRunSyncPull_Verbose(t *testing.T) {
 	syncJSON = false
 
 	// Will likely fail on git operations but tests verbose path
-	_ = runSyncPull(ws, "main")
+	_ = runSyncPull(t.Context(), ws, "main")
 }
@@ -22,7 +22,7 @@ func TestRunSyncFetch_ErrorFromSyncAllRepos(t *testing.T) {
 	stateFile := ws.Path + "/.foundagent/state.json"
 	require.NoError(t, os.WriteFile(stateFile, []byte("invalid"), 0644))
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	assert.Error(t, err)
 }
@@ -39,7 +39,7 @@ func TestRunSyncFetch_EmptyWorkspaceHuman(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -68,7 +68,7 @@ func TestRunSyncFetch_EmptyWorkspaceJSON(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -104,7 +104,7 @@ func TestRunSyncFetch_WithReposHuman(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -143,7 +143,7 @@ func TestRunSyncFetch_WithReposJSON(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -182,7 +182,7 @@ func TestRunSyncFetch_VerboseMode(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncFetch(ws)
+	err = runSyncFetch(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -212,7 +212,7 @@ func TestRunSyncPull_ErrorFromPullAllWorktrees(t *testing.T) {
 	stateFile := ws.Path + "/.foundagent/state.json"
 	require.NoError(t, os.WriteFile(stateFile, []byte("invalid"), 0644))
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	assert.Error(t, err)
 }
@@ -229,7 +229,7 @@ func TestRunSyncPull_EmptyWorkspaceHuman(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -258,7 +258,7 @@ func TestRunSyncPull_EmptyWorkspaceJSON(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -294,7 +294,7 @@ func TestRunSyncPull_WithReposHuman(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -333,7 +333,7 @@ func TestRunSyncPull_WithReposJSON(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -372,7 +372,7 @@ func TestRunSyncPull_VerboseMode(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -412,7 +412,7 @@ func TestRunSyncPull_WithStashFlag(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPull(ws, "main")
+	err = runSyncPull(t.Context(), ws, "main")
 
 	w.Close()
 	os.Stdout = oldStdout
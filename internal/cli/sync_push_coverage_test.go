@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/foundagent/foundagent/gitexec/gittest"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,7 +23,7 @@ func TestRunSyncPush_ErrorFromPushAllRepos(t *testing.T) {
 	stateFile := ws.Path + "/.foundagent/state.json"
 	require.NoError(t, os.WriteFile(stateFile, []byte("invalid"), 0644))
 
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	assert.Error(t, err)
 }
@@ -49,7 +50,7 @@ func TestRunSyncPush_WithResults(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -63,7 +64,47 @@ func TestRunSyncPush_WithResults(t *testing.T) {
 
 // TestRunSyncPush_WithFailures tests push with failed pushes
 func TestRunSyncPush_WithFailures(t *testing.T) {
-	t.Skip("Complex test - requires simulating push failures")
+	tmpDir := t.TempDir()
+	ws, err := workspace.New("test-ws", tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	repo := &workspace.Repository{
+		Name:          "api",
+		URL:           "https://github.com/test/api.git",
+		DefaultBranch: "main",
+		Worktrees:     []string{"main"},
+		BareRepoPath:  ws.BareRepoPath("api"),
+	}
+	require.NoError(t, ws.AddRepository(repo))
+	require.NoError(t, os.MkdirAll(ws.WorktreePath("api", "main"), 0755))
+
+	// One commit ahead, and the remote has moved on
+	fake := gittest.New()
+	fake.On("rev-parse").Stdout("origin/main\n")
+	fake.On("rev-list").Stdout("1\t0\n")
+	fake.On("push").NonFastForward()
+	t.Cleanup(fake.Install())
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err = runSyncPush(t.Context(), ws)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 failures")
+	assert.Contains(t, output, "Push rejected")
+	assert.Contains(t, output, "Summary: 0 pushed, 1 failed")
+	assert.Equal(t, 1, fake.Count("push"))
 }
 
 // TestRunSyncPush_NothingToPush tests when there are no commits to push
@@ -88,7 +129,7 @@ func TestRunSyncPush_NothingToPush(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -126,7 +167,7 @@ func TestRunSyncPush_VerboseMode(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -164,7 +205,7 @@ func TestRunSyncPush_JSONModeWithResults(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -22,7 +22,7 @@ func TestRunSyncPush_NoRepos(t *testing.T) {
 	require.NoError(t, err)
 
 	syncJSON = false
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	// No repos is not an error
 	if err != nil {
@@ -44,7 +44,7 @@ func TestRunSyncPush_NoReposJSON(t *testing.T) {
 	require.NoError(t, err)
 
 	syncJSON = true
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	// JSON mode should also succeed
 	if err != nil {
@@ -66,7 +66,7 @@ func TestRunSyncPush_Verbose(t *testing.T) {
 	require.NoError(t, err)
 
 	syncVerbose = true
-	err = runSyncPush(ws)
+	err = runSyncPush(t.Context(), ws)
 
 	// Verbose mode should work
 	if err != nil {
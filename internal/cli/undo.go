@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/journal"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	undoList bool
+	undoJSON bool
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Reverse the most recent command that changed the workspace",
+	Long: `Reverse the most recent command that changed the workspace, as far as
+possible. Run it again to reverse the command before that.
+
+Every command that changes the workspace is recorded in .foundagent/journal/,
+keeping the last 20: the config, state and VS Code workspace files as they
+were, the commits of the branches it deleted, and the uncommitted changes of
+the worktrees it removed, including untracked files but not ignored ones.
+Repositories removed with 'fa remove' are kept there until the record is
+pruned.
+
+Undo removes the worktrees a command created, with their new branches, and
+moves the clones 'fa add' made into the journal. It puts back removed
+repositories, deleted branches and removed worktrees with their uncommitted
+changes, then the files. A file changed since the command is left as it is,
+anything that has been recreated since is skipped, a created worktree is kept
+if it has uncommitted changes, and a clone is kept while worktrees are on it.`,
+	Example: `  # Put back the worktrees removed by mistake
+  fa wt remove feature-x --force
+  fa undo
+
+  # Show the commands that can be undone, newest first
+  fa undo --list`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{lockAnnotation: "", noJournalAnnotation: ""},
+	RunE:        runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "List the recorded commands instead of undoing one")
+	undoCmd.Flags().BoolVar(&undoJSON, "json", false, "Output result as JSON")
+	undoCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Not supported; use --list to see what would be undone")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	if dryRun {
+		return printUndoError(errors.New(
+			errors.ErrCodeInvalidInput,
+			"fa undo has no dry run",
+			"Run 'fa undo --list' to see the commands that would be undone",
+		))
+	}
+
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printUndoError(err)
+	}
+
+	if undoList {
+		return listUndo(ws)
+	}
+
+	entry, err := journal.Latest(ws.JournalPath())
+	if err != nil {
+		return printUndoError(errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to read the journal",
+			"Check that you have read permissions in the .foundagent directory",
+			err,
+		))
+	}
+	if entry == nil {
+		return printUndoError(errors.New(
+			errors.ErrCodeFileNotFound,
+			"Nothing to undo",
+			"Only commands that changed the workspace are recorded, in "+filepath.Join(workspace.FoundagentDir, workspace.JournalDir),
+		))
+	}
+
+	outcomes, saveErr := entry.Undo()
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.Status == "failed" {
+			failed++
+		}
+	}
+
+	if undoJSON {
+		if err := output.PrintJSON(map[string]interface{}{
+			"id":      entry.ID,
+			"command": entry.Argv,
+			"results": outcomes,
+		}); err != nil {
+			return err
+		}
+	} else {
+		output.PrintMessage("Undoing: %s", commandLine(entry.Argv))
+		output.PrintMessage("  run at %s", entry.Time.Local().Format(time.DateTime))
+		output.PrintMessage("")
+		for _, o := range outcomes {
+			symbol := "✓"
+			switch o.Status {
+			case "skipped":
+				symbol = "⊘"
+			case "failed":
+				symbol = "✗"
+			}
+			line := fmt.Sprintf("  %s %s", symbol, describeOutcome(ws, o))
+			if o.Message != "" {
+				line += ": " + o.Message
+			}
+			output.PrintMessage("%s", line)
+		}
+		if saveErr != nil {
+			output.PrintErrorMessage("Warning: cannot mark the command as undone: %v", saveErr)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to undo %d change(s)", failed)
+	}
+	return nil
+}
+
+// describeOutcome names what undo put back, with paths relative to the
+// workspace
+func describeOutcome(ws *workspace.Workspace, o journal.Outcome) string {
+	switch o.Kind {
+	case journal.ActionRemoveRepo:
+		return "repository " + o.Repo
+	case journal.ActionDeleteBranch:
+		return fmt.Sprintf("branch %s in %s", o.Target, o.Repo)
+	case journal.ActionRemoveWorktree:
+		return "worktree " + relativeToWorkspace(ws, o.Target)
+	case journal.ActionCreateWorktree:
+		return "new worktree " + relativeToWorkspace(ws, o.Target)
+	case journal.ActionCloneRepo:
+		return "clone of " + o.Repo
+	default:
+		return "file " + relativeToWorkspace(ws, o.Target)
+	}
+}
+
+// listUndo prints the recorded commands, newest first
+func listUndo(ws *workspace.Workspace) error {
+	entries, err := journal.List(ws.JournalPath())
+	if err != nil {
+		return printUndoError(errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to read the journal",
+			"Check that you have read permissions in the .foundagent directory",
+			err,
+		))
+	}
+
+	// Newest first, the order undo goes in
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	if undoJSON {
+		if entries == nil {
+			entries = []*journal.Entry{}
+		}
+		return output.PrintJSON(map[string]interface{}{
+			"entries": entries,
+		})
+	}
+
+	if len(entries) == 0 {
+		output.PrintMessage("Nothing to undo")
+		return nil
+	}
+	for _, e := range entries {
+		line := fmt.Sprintf("%s  %s", e.Time.Local().Format(time.DateTime), commandLine(e.Argv))
+		if e.Undone != nil {
+			line += "  (undone)"
+		}
+		output.PrintMessage("%s", line)
+		output.PrintMessage("    %s", summarizeEntry(e))
+	}
+	return nil
+}
+
+// summarizeEntry counts what an entry recorded, such as "removed 2
+// worktree(s), changed 2 file(s)"
+func summarizeEntry(e *journal.Entry) string {
+	counts := map[string]int{}
+	for _, a := range e.Actions {
+		counts[a.Kind]++
+	}
+
+	var parts []string
+	if n := counts[journal.ActionRemoveRepo]; n > 0 {
+		parts = append(parts, fmt.Sprintf("removed %d repository(ies)", n))
+	}
+	if n := counts[journal.ActionRemoveWorktree]; n > 0 {
+		parts = append(parts, fmt.Sprintf("removed %d worktree(s)", n))
+	}
+	if n := counts[journal.ActionDeleteBranch]; n > 0 {
+		parts = append(parts, fmt.Sprintf("deleted %d branch(es)", n))
+	}
+	if n := counts[journal.ActionCloneRepo]; n > 0 {
+		parts = append(parts, fmt.Sprintf("cloned %d repository(ies)", n))
+	}
+	if n := counts[journal.ActionCreateWorktree]; n > 0 {
+		parts = append(parts, fmt.Sprintf("created %d worktree(s)", n))
+	}
+	if n := len(e.Files); n > 0 {
+		parts = append(parts, fmt.Sprintf("changed %d file(s)", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printUndoError(err error) error {
+	if undoJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUndo_WorktreeRemove(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = repos
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(ws.Path))
+
+	// Nothing has been recorded yet
+	err = runUndo(undoCmd, nil)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeFileNotFound, faErr.Code)
+
+	// A forced removal throws away uncommitted work and the branch
+	worktreePath := ws.WorktreePath("api", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "wip.txt"), []byte("wip\n"), 0644))
+	removeForce, removeDeleteBranch = true, true
+	defer func() { removeForce, removeDeleteBranch = false, false }()
+
+	require.NoError(t, lockWorkspace(removeCmd, nil))
+	_, err = captureConfigOutput(t, func() error { return runRemove(removeCmd, []string{"feature"}) })
+	releaseWorkspaceLock()
+	require.NoError(t, err)
+	require.NoDirExists(t, worktreePath)
+
+	out, err := captureConfigOutput(t, func() error { return runUndo(undoCmd, nil) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ branch feature in api")
+	assert.Contains(t, out, "✓ worktree repos/api/worktrees/feature")
+
+	data, err := os.ReadFile(filepath.Join(worktreePath, "wip.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "wip\n", string(data))
+	branch, err := git.GetCurrentBranch(worktreePath)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", branch)
+
+	// The removal is listed as undone, and there is nothing left to undo
+	undoList = true
+	out, err = captureConfigOutput(t, func() error { return runUndo(undoCmd, nil) })
+	undoList = false
+	require.NoError(t, err)
+	assert.Contains(t, out, "(undone)")
+	assert.Contains(t, out, "removed 1 worktree(s), deleted 1 branch(es)")
+
+	err = runUndo(undoCmd, nil)
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeFileNotFound, faErr.Code)
+}
+
+func TestRunUndo_Add(t *testing.T) {
+	source := createLocalGitRepo(t, "web")
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(ws.Path))
+
+	require.NoError(t, lockWorkspace(addCmd, nil))
+	result := addRepository(ws, repoToAdd{URL: "file://" + source}, nil)
+	releaseWorkspaceLock()
+	require.Equal(t, "success", result.Status, result.Error)
+	require.DirExists(t, ws.BareRepoPath("web"))
+
+	out, err := captureConfigOutput(t, func() error { return runUndo(undoCmd, nil) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ new worktree repos/web/worktrees/")
+	assert.Contains(t, out, "✓ clone of web")
+	assert.NoDirExists(t, filepath.Join(ws.Path, workspace.ReposDir, "web"))
+
+	hasRepo, err := ws.HasRepository("web")
+	require.NoError(t, err)
+	assert.False(t, hasRepo)
+}
+
+func TestRunUndo_ExtendKeepsExistingBranch(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+	require.NoError(t, git.CreateBranch(ws.BareRepoPath("lib"), "feature", "main"))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(ws.Path))
+
+	require.NoError(t, lockWorkspace(extendCmd, nil))
+	result := extendWorktreeForRepo(ws, repos[1], "feature", "")
+	releaseWorkspaceLock()
+	require.Equal(t, "success", result.Status, result.Error)
+
+	out, err := captureConfigOutput(t, func() error { return runUndo(undoCmd, nil) })
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ new worktree repos/lib/worktrees/feature")
+	assert.NoDirExists(t, ws.WorktreePath("lib", "feature"))
+	exists, err := git.BranchExists(ws.BareRepoPath("lib"), "feature")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestRunUndo_RefusesDryRun(t *testing.T) {
+	dryRun = true
+	defer func() { dryRun = false }()
+
+	err := runUndo(undoCmd, nil)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeInvalidInput, faErr.Code)
+}
@@ -19,6 +19,10 @@ worktrees atomically across all repos in your workspace.`,
   # Create from a specific branch
   fa wt create hotfix-1 --from release-2.0
 
+  # Create a worktree in two repos, then add a third later
+  fa wt create feature-123 --repo api --repo web
+  fa wt extend feature-123 --repo lib
+
   # List all worktrees
   fa wt list
 
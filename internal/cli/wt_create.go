@@ -1,13 +1,15 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"sync"
 
 	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/errors"
 	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
 	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/progress"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -28,7 +30,10 @@ This command creates a new branch and worktree in every repository, based on
 each repo's default branch (or a branch specified with --from). The worktrees
 are created atomically - if validation fails for any repo, no worktrees are created.
 
-Worktrees are created at: repos/worktrees/<repo>/<branch>/
+Use --repo, --group or --tag to create the branch only in some repositories.
+More can be added later with 'fa wt extend'.
+
+Worktrees are created at: repos/<repo>/worktrees/<branch>/
 
 The VS Code workspace file is automatically updated to include the new worktree
 directories.`,
@@ -38,19 +43,34 @@ directories.`,
   # Create worktree from specific branch
   fa wt create hotfix-1 --from release-2.0
 
+  # Create worktree from a branch of another remote, e.g. the repo you forked
+  fa wt create feature-123 --from upstream/main
+
   # Force recreate existing worktree
   fa wt create feature-123 --force
 
+  # Create worktree only in the api and web repos
+  fa wt create feature-123 --repo api --repo web
+
+  # Create worktree only in repos tagged go
+  fa wt create feature-123 --tag go
+
+  # Show the git commands and file changes, without making them
+  fa wt create feature-123 --dry-run
+
   # JSON output for automation
   fa wt create feature-123 --json`,
-	Args: cobra.ExactArgs(1),
-	RunE: runCreate,
+	Args:        cobra.ExactArgs(1),
+	Annotations: locksWorkspace,
+	RunE:        runCreate,
 }
 
 func init() {
-	createCmd.Flags().StringVar(&createFrom, "from", "", "Source branch to create from (defaults to each repo's default branch)")
+	createCmd.Flags().StringVar(&createFrom, "from", "", "Source branch to create from, local or remote such as upstream/main (defaults to each repo's default branch)")
 	createCmd.Flags().BoolVar(&createForce, "force", false, "Force recreate if worktree already exists")
 	createCmd.Flags().BoolVar(&createJSON, "json", false, "Output result as JSON")
+	addRepoSelectionFlags(createCmd)
+	addDryRunFlag(createCmd)
 	worktreeCmd.AddCommand(createCmd)
 }
 
@@ -61,6 +81,7 @@ type createResult struct {
 	WorktreePath string `json:"worktree_path"`
 	Status       string `json:"status"`
 	Error        string `json:"error,omitempty"`
+	Warning      string `json:"warning,omitempty"`
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
@@ -113,6 +134,18 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Narrow to the selected repos
+	repos, err := repoSelector().Filter(cfg.Repos)
+	if err != nil {
+		if createJSON {
+			_ = output.PrintError(err)
+		} else {
+			output.PrintErrorMessage("Error: %v", err)
+		}
+		return err
+	}
+	cfg.Repos = repos
+
 	// Phase 1: Pre-validation (atomic all-or-nothing)
 	if err := preValidateWorktreeCreate(ws, cfg, targetBranch, createFrom, createForce); err != nil {
 		if createJSON {
@@ -124,7 +157,7 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Phase 2: Create worktrees in parallel
-	results := createWorktreesParallel(ws, cfg.Repos, targetBranch, createFrom, createForce)
+	results := createWorktreesParallel(commandContext(cmd), ws, cfg.Repos, targetBranch, createFrom, createForce)
 
 	// Check for failures
 	failed := 0
@@ -148,6 +181,9 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output results
+	if dryRun {
+		return printPlan(ws, createJSON, createProblems(results))
+	}
 	if createJSON {
 		if len(results) == 1 {
 			return output.PrintJSON(results[0])
@@ -159,6 +195,9 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	for _, r := range results {
 		if r.Status == "success" {
 			output.PrintMessage("✓ Created worktree for %s: %s", r.RepoName, r.WorktreePath)
+			if r.Warning != "" {
+				output.PrintErrorMessage("  Warning: %s", r.Warning)
+			}
 		} else {
 			output.PrintErrorMessage("✗ Failed to create worktree for %s: %s", r.RepoName, r.Error)
 		}
@@ -203,13 +242,14 @@ func preValidateWorktreeCreate(ws *workspace.Workspace, cfg *config.Config, targ
 			continue
 		}
 
-		// If --from specified, validate it exists in all repos
+		// If --from specified, validate it exists in all repos, either as a
+		// local branch or a remote one such as upstream/main
 		if sourceBranch != "" {
 			exists, err := git.BranchExists(bareRepoPath, sourceBranch)
 			if err != nil {
 				return err
 			}
-			if !exists {
+			if !exists && !git.RemoteBranchExists(bareRepoPath, sourceBranch) {
 				validationErrors = append(validationErrors,
 					fmt.Sprintf("%s: source branch '%s' not found", repo.Name, sourceBranch))
 			}
@@ -238,22 +278,63 @@ func preValidateWorktreeCreate(ws *workspace.Workspace, cfg *config.Config, targ
 	return nil
 }
 
-func createWorktreesParallel(ws *workspace.Workspace, repos []config.RepoConfig, targetBranch, sourceBranch string, force bool) []createResult {
+func createWorktreesParallel(ctx context.Context, ws *workspace.Workspace, repos []config.RepoConfig, targetBranch, sourceBranch string, force bool) []createResult {
 	results := make([]createResult, len(repos))
-	var wg sync.WaitGroup
-
-	for i, repo := range repos {
-		wg.Add(1)
-		go func(index int, r config.RepoConfig) {
-			defer wg.Done()
-			results[index] = createWorktreeForRepo(ws, r, targetBranch, sourceBranch, force)
-		}(i, repo)
+	bar := newProgress(createJSON)
+	started := workspace.ForEachParallel(ctx, ws.Jobs(), len(repos), func(ctx context.Context, i int) {
+		results[i] = trackCreate(bar, repos[i].Name, func() createResult {
+			return createWorktreeForRepo(ws, repos[i], targetBranch, sourceBranch, force)
+		})
+	})
+	bar.Stop()
+
+	for i, ok := range started {
+		if !ok {
+			results[i] = notStartedResult(ctx, ws, repos[i].Name, targetBranch)
+		}
 	}
-
-	wg.Wait()
 	return results
 }
 
+// createProblems returns the repos a dry run of creating worktrees found
+// would fail or need attention
+func createProblems(results []createResult) []planProblem {
+	var problems []planProblem
+	for _, r := range results {
+		switch {
+		case r.Status == "error":
+			problems = append(problems, planProblem{Repo: r.RepoName, Status: "error", Message: r.Error})
+		case r.Warning != "":
+			problems = append(problems, planProblem{Repo: r.RepoName, Status: "warning", Message: r.Warning})
+		}
+	}
+	return problems
+}
+
+// trackCreate shows the creation of a repo's worktree as a progress task
+func trackCreate(bar *progress.Renderer, repoName string, create func() createResult) createResult {
+	task := bar.Start(repoName, "creating worktree")
+	result := create()
+	if result.Status == "error" {
+		task.Fail()
+	} else {
+		task.Done()
+	}
+	return result
+}
+
+// notStartedResult is the result of a repo whose worktree was never created
+// because the run was cancelled
+func notStartedResult(ctx context.Context, ws *workspace.Workspace, repoName, branch string) createResult {
+	return createResult{
+		RepoName:     repoName,
+		Branch:       branch,
+		WorktreePath: ws.WorktreePath(repoName, branch),
+		Status:       "error",
+		Error:        context.Cause(ctx).Error(),
+	}
+}
+
 func createWorktreeForRepo(ws *workspace.Workspace, repo config.RepoConfig, targetBranch, sourceBranch string, force bool) createResult {
 	bareRepoPath := ws.BareRepoPath(repo.Name)
 	worktreePath := ws.WorktreePath(repo.Name, targetBranch)
@@ -282,7 +363,8 @@ func createWorktreeForRepo(ws *workspace.Workspace, repo config.RepoConfig, targ
 	if force {
 		exists, _ := ws.WorktreeExists(repo.Name, targetBranch)
 		if exists {
-			// Remove worktree
+			// Remove worktree, keeping its changes for 'fa undo'
+			saved := journal.SaveWorktree(repo.Name, bareRepoPath, worktreePath)
 			if err := git.WorktreeRemove(bareRepoPath, worktreePath, true); err != nil {
 				return createResult{
 					RepoName: repo.Name,
@@ -291,10 +373,12 @@ func createWorktreeForRepo(ws *workspace.Workspace, repo config.RepoConfig, targ
 					Error:    fmt.Sprintf("Failed to remove existing worktree: %v", err),
 				}
 			}
+			journal.Record(saved)
 
 			// Delete branch if it exists
 			branchExists, _ := git.BranchExists(bareRepoPath, targetBranch)
 			if branchExists {
+				saved := journal.SaveBranch(repo.Name, bareRepoPath, targetBranch)
 				if err := git.DeleteBranch(bareRepoPath, targetBranch, true); err != nil {
 					return createResult{
 						RepoName: repo.Name,
@@ -303,12 +387,13 @@ func createWorktreeForRepo(ws *workspace.Workspace, repo config.RepoConfig, targ
 						Error:    fmt.Sprintf("Failed to delete existing branch: %v", err),
 					}
 				}
+				journal.Record(saved)
 			}
 		}
 	}
 
 	// Create worktree with new branch
-	if err := git.WorktreeAddNew(bareRepoPath, worktreePath, targetBranch, source); err != nil {
+	if err := git.WorktreeAddNew(bareRepoPath, worktreePath, targetBranch, source, repo.Sparse...); err != nil {
 		return createResult{
 			RepoName:     repo.Name,
 			Branch:       targetBranch,
@@ -318,11 +403,20 @@ func createWorktreeForRepo(ws *workspace.Workspace, repo config.RepoConfig, targ
 		}
 	}
 
-	return createResult{
+	journal.WorktreeCreated(repo.Name, bareRepoPath, worktreePath, targetBranch)
+
+	result := createResult{
 		RepoName:     repo.Name,
 		Branch:       targetBranch,
 		SourceBranch: source,
 		WorktreePath: worktreePath,
 		Status:       "success",
 	}
+
+	// The worktree is usable without submodules or LFS files, so only warn
+	if err := ws.SetupWorktree(repo.Name, worktreePath); err != nil {
+		result.Warning = err.Error()
+	}
+
+	return result
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -160,7 +161,7 @@ func TestCreateWorktreesParallel_EmptyRepos(t *testing.T) {
 	require.NoError(t, ws.Create(false))
 
 	// Test with empty repos list
-	results := createWorktreesParallel(ws, []config.RepoConfig{}, "feature", "", false)
+	results := createWorktreesParallel(t.Context(), ws, []config.RepoConfig{}, "feature", "", false)
 	assert.Empty(t, results)
 }
 
@@ -179,10 +180,42 @@ func TestCreateWorktreesParallel_MultipleRepos(t *testing.T) {
 	}
 
 	// Test parallel creation (will fail on git operations but tests parallelism)
-	results := createWorktreesParallel(ws, repos, "feature", "", false)
+	results := createWorktreesParallel(t.Context(), ws, repos, "feature", "", false)
 	assert.Len(t, results, 2)
 	// All should fail since we don't have real git repos
 	for _, r := range results {
 		assert.Equal(t, "error", r.Status)
 	}
 }
+
+func TestPreValidateWorktreeCreate_FromRemoteBranch(t *testing.T) {
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	// A bare clone whose upstream remote has a main branch
+	src := filepath.Join(t.TempDir(), "src")
+	bareRepoPath := ws.BareRepoPath("app")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", src},
+		{"-C", src, "-c", "user.email=t@t.com", "-c", "user.name=T", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"clone", "-q", "--bare", src, bareRepoPath},
+		{"--git-dir=" + bareRepoPath, "remote", "add", "upstream", src},
+		{"--git-dir=" + bareRepoPath, "fetch", "-q", "upstream"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	cfg := &config.Config{
+		Workspace: config.WorkspaceConfig{Name: "test-ws"},
+		Repos:     []config.RepoConfig{{Name: "app", URL: "file://" + src, DefaultBranch: "main"}},
+	}
+
+	assert.NoError(t, preValidateWorktreeCreate(ws, cfg, "feature", "upstream/main", false))
+	assert.NoError(t, preValidateWorktreeCreate(ws, cfg, "feature", "main", false))
+
+	err = preValidateWorktreeCreate(ws, cfg, "feature", "upstream/nope", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source branch 'upstream/nope' not found")
+}
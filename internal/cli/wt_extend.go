@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extendFrom string
+	extendJSON bool
+)
+
+var extendCmd = &cobra.Command{
+	Use:   "extend <branch>",
+	Short: "Add repositories to an existing branch's worktrees",
+	Long: `Add worktrees for a branch to more repositories.
+
+A branch does not need a worktree in every repository. Create it only where the
+change is ('fa wt create <branch> --repo api'), then extend it when the change
+reaches another repository. Repositories are chosen with --repo, --group or --tag.
+
+A repository that already has the branch, without a worktree, gets a worktree
+for it. Otherwise the branch is created from --from or the repo's default branch.`,
+	Example: `  # Add lib to the feature-123 worktrees
+  fa wt extend feature-123 --repo lib
+
+  # Add every repo in the backend group, branching from develop
+  fa wt extend feature-123 --group backend --from develop`,
+	Args:              cobra.ExactArgs(1),
+	Annotations:       locksWorkspace,
+	RunE:              runExtend,
+	ValidArgsFunction: getBranchCompletions,
+}
+
+func init() {
+	extendCmd.Flags().StringVar(&extendFrom, "from", "", "Source branch for new branches, local or remote such as upstream/main (defaults to each repo's default branch)")
+	extendCmd.Flags().BoolVar(&extendJSON, "json", false, "Output result as JSON")
+	addRepoSelectionFlags(extendCmd)
+	addDryRunFlag(extendCmd)
+	worktreeCmd.AddCommand(extendCmd)
+}
+
+func runExtend(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+
+	if err := git.ValidateBranchName(branch); err != nil {
+		return printExtendError(err)
+	}
+
+	sel := repoSelector()
+	if sel.IsEmpty() {
+		return printExtendError(errors.New(
+			errors.ErrCodeInvalidInput,
+			"No repositories selected",
+			"Choose the repositories to add with --repo, --group or --tag",
+		))
+	}
+
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printExtendError(err)
+	}
+
+	cfg, err := config.Load(ws.Path)
+	if err != nil {
+		return printExtendError(err)
+	}
+
+	// The branch must already have worktrees somewhere
+	inSet := 0
+	for _, repo := range cfg.Repos {
+		if exists, _ := ws.WorktreeExists(repo.Name, branch); exists {
+			inSet++
+		}
+	}
+	if inSet == 0 {
+		return printExtendError(errors.New(
+			errors.ErrCodeWorktreeNotFound,
+			fmt.Sprintf("No worktrees found for branch '%s'", branch),
+			fmt.Sprintf("Create them with 'fa wt create %s --repo <name>'", branch),
+		))
+	}
+
+	repos, err := sel.Filter(cfg.Repos)
+	if err != nil {
+		return printExtendError(err)
+	}
+
+	var toAdd []config.RepoConfig
+	for _, repo := range repos {
+		if exists, _ := ws.WorktreeExists(repo.Name, branch); !exists {
+			toAdd = append(toAdd, repo)
+		}
+	}
+
+	if err := preValidateWorktreeExtend(ws, toAdd, branch, extendFrom); err != nil {
+		return printExtendError(err)
+	}
+
+	results := extendWorktreesParallel(commandContext(cmd), ws, toAdd, branch, extendFrom)
+
+	failed := 0
+	var worktreePaths []string
+	for _, r := range results {
+		if r.Status == "error" {
+			failed++
+		} else {
+			worktreePaths = append(worktreePaths, r.WorktreePath)
+		}
+	}
+
+	// Show the new worktrees if the branch is the one being worked on
+	if len(worktreePaths) > 0 {
+		var err error
+		if current, _ := ws.GetCurrentBranchFromWorkspace(); current == branch {
+			err = ws.ReplaceWorktreeFolders(branch)
+		} else {
+			err = ws.AddWorktreeFolders(worktreePaths)
+		}
+		if err != nil && !extendJSON {
+			output.PrintErrorMessage("Warning: Failed to update VS Code workspace: %v", err)
+		}
+	}
+
+	if dryRun {
+		return printPlan(ws, extendJSON, createProblems(results))
+	}
+	if extendJSON {
+		return output.PrintJSON(results)
+	}
+
+	if len(results) == 0 {
+		output.PrintMessage("✓ Branch '%s' already has worktrees in the selected repositories", branch)
+		return nil
+	}
+
+	for _, r := range results {
+		if r.Status == "success" {
+			output.PrintMessage("✓ Created worktree for %s: %s", r.RepoName, r.WorktreePath)
+			if r.Warning != "" {
+				output.PrintErrorMessage("  Warning: %s", r.Warning)
+			}
+		} else {
+			output.PrintErrorMessage("✗ Failed to create worktree for %s: %s", r.RepoName, r.Error)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create worktrees in %d repository(ies)", failed)
+	}
+
+	output.PrintMessage("")
+	output.PrintMessage("✓ Branch '%s' now has worktrees in %d repository(ies)", branch, inSet+len(results))
+	return nil
+}
+
+// preValidateWorktreeExtend checks that every repo that needs a new branch
+// has the source branch, before any worktree is created
+func preValidateWorktreeExtend(ws *workspace.Workspace, repos []config.RepoConfig, branch, sourceBranch string) error {
+	if sourceBranch == "" {
+		return nil
+	}
+
+	var missing []string
+	for _, repo := range repos {
+		bareRepoPath := ws.BareRepoPath(repo.Name)
+		if exists, _ := git.BranchExists(bareRepoPath, branch); exists {
+			continue
+		}
+		if exists, _ := git.BranchExists(bareRepoPath, sourceBranch); !exists && !git.RemoteBranchExists(bareRepoPath, sourceBranch) {
+			missing = append(missing, repo.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("Source branch '%s' not found in: %s", sourceBranch, strings.Join(missing, ", ")),
+			"Fetch it with 'fa sync' or choose another branch with --from",
+		)
+	}
+	return nil
+}
+
+func extendWorktreesParallel(ctx context.Context, ws *workspace.Workspace, repos []config.RepoConfig, branch, sourceBranch string) []createResult {
+	results := make([]createResult, len(repos))
+	bar := newProgress(extendJSON)
+	started := workspace.ForEachParallel(ctx, ws.Jobs(), len(repos), func(ctx context.Context, i int) {
+		results[i] = trackCreate(bar, repos[i].Name, func() createResult {
+			return extendWorktreeForRepo(ws, repos[i], branch, sourceBranch)
+		})
+	})
+	bar.Stop()
+
+	for i, ok := range started {
+		if !ok {
+			results[i] = notStartedResult(ctx, ws, repos[i].Name, branch)
+		}
+	}
+	return results
+}
+
+// extendWorktreeForRepo checks out the branch if the repo already has it, and
+// otherwise creates it as 'fa wt create' does
+func extendWorktreeForRepo(ws *workspace.Workspace, repo config.RepoConfig, branch, sourceBranch string) createResult {
+	bareRepoPath := ws.BareRepoPath(repo.Name)
+	if exists, _ := git.BranchExists(bareRepoPath, branch); !exists {
+		return createWorktreeForRepo(ws, repo, branch, sourceBranch, false)
+	}
+
+	worktreePath := ws.WorktreePath(repo.Name, branch)
+	if err := git.WorktreeAdd(git.WorktreeAddOptions{
+		BareRepoPath: bareRepoPath,
+		WorktreePath: worktreePath,
+		Branch:       branch,
+		Sparse:       repo.Sparse,
+	}); err != nil {
+		return createResult{
+			RepoName: repo.Name,
+			Branch:   branch,
+			Status:   "error",
+			Error:    err.Error(),
+		}
+	}
+	journal.WorktreeAdded(repo.Name, bareRepoPath, worktreePath, branch)
+
+	result := createResult{
+		RepoName:     repo.Name,
+		Branch:       branch,
+		WorktreePath: worktreePath,
+		Status:       "success",
+	}
+
+	// The worktree is usable without submodules or LFS files, so only warn
+	if err := ws.SetupWorktree(repo.Name, worktreePath); err != nil {
+		result.Warning = err.Error()
+	}
+
+	return result
+}
+
+func printExtendError(err error) error {
+	if extendJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupExtendWorkspace returns a workspace with bare clones of two repos, api
+// and lib, where only api has a worktree for the feature branch
+func setupExtendWorkspace(t *testing.T) (*workspace.Workspace, []config.RepoConfig) {
+	t.Helper()
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	var repos []config.RepoConfig
+	for _, name := range []string{"api", "lib"} {
+		src := filepath.Join(t.TempDir(), name)
+		for _, args := range [][]string{
+			{"init", "-q", "-b", "main", src},
+			{"-C", src, "-c", "user.email=t@t.com", "-c", "user.name=T", "commit", "-q", "--allow-empty", "-m", "init"},
+			{"clone", "-q", "--bare", src, ws.BareRepoPath(name)},
+		} {
+			out, err := exec.Command("git", args...).CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+		repos = append(repos, config.RepoConfig{Name: name, URL: "file://" + src, DefaultBranch: "main"})
+	}
+
+	require.NoError(t, git.WorktreeAddNew(ws.BareRepoPath("api"), ws.WorktreePath("api", "feature"), "feature", "main"))
+	return ws, repos
+}
+
+func TestRunExtend_RequiresSelection(t *testing.T) {
+	selectRepos, selectGroups, selectTags = nil, nil, nil
+
+	err := runExtend(extendCmd, []string{"feature"})
+
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeInvalidInput, faErr.Code)
+}
+
+func TestExtendWorktreeForRepo_NewBranch(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+
+	result := extendWorktreeForRepo(ws, repos[1], "feature", "")
+
+	assert.Equal(t, "success", result.Status, result.Error)
+	exists, err := ws.WorktreeExists("lib", "feature")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestExtendWorktreeForRepo_ExistingBranch(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+
+	// The branch exists in lib without a worktree, e.g. after 'fa wt remove'
+	out, err := exec.Command("git", "--git-dir="+ws.BareRepoPath("lib"), "branch", "feature", "main").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	result := extendWorktreeForRepo(ws, repos[1], "feature", "")
+
+	assert.Equal(t, "success", result.Status, result.Error)
+	assert.Equal(t, ws.WorktreePath("lib", "feature"), result.WorktreePath)
+}
+
+func TestPreValidateWorktreeExtend_MissingSource(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+
+	assert.NoError(t, preValidateWorktreeExtend(ws, repos[1:], "feature", ""))
+	assert.NoError(t, preValidateWorktreeExtend(ws, repos[1:], "feature", "main"))
+
+	err := preValidateWorktreeExtend(ws, repos[1:], "feature", "develop")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Source branch 'develop' not found in: lib")
+}
@@ -62,6 +62,7 @@ type listOutput struct {
 
 func init() {
 	listCmd.Flags().BoolVar(&listJSONFlag, "json", false, "Output in JSON format")
+	addRepoSelectionFlags(listCmd)
 	worktreeCmd.AddCommand(listCmd)
 }
 
@@ -103,6 +104,18 @@ func runList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Narrow to the selected repos
+	repos, err := repoSelector().Filter(cfg.Repos)
+	if err != nil {
+		if listJSONFlag {
+			_ = output.PrintError(err)
+		} else {
+			output.PrintErrorMessage("Error: %v", err)
+		}
+		return err
+	}
+	cfg.Repos = repos
+
 	// Optional branch filter
 	var branchFilter string
 	if len(args) > 0 {
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/output"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var overlayJSON bool
+
+var overlayCmd = &cobra.Command{
+	Use:   "overlay",
+	Short: "Manage files overlaid onto worktrees",
+	Long: `Manage untracked files, such as .env files, local certificates or editor
+settings, that every new worktree gets.
+
+Overlay sources live in .foundagent/overlays/. Workspace-wide overlays are
+read from its top, and a repo's own overlays from .foundagent/overlays/<repo>/:
+
+  workspace:
+    name: my-workspace
+    overlays:
+      symlink: [AGENTS.md]
+  repos:
+    - url: git@github.com:org/api.git
+      overlays:
+        copy: [.env, certs]
+
+Copied files can be changed in each worktree; symlinked files are shared by
+all of them. 'fa wt create', 'fa wt extend' and 'fa wt switch --create' apply
+overlays to the worktrees they create.`,
+}
+
+var overlayRefreshCmd = &cobra.Command{
+	Use:   "refresh [branch]",
+	Short: "Re-apply overlays to existing worktrees",
+	Long: `Re-apply overlays to existing worktrees, after changing the overlay sources
+or the overlays in the config.
+
+Copied files are overwritten with the current source and symlinks are
+recreated. Files that are not overlays are left alone.`,
+	Example: `  # Refresh every worktree
+  fa wt overlay refresh
+
+  # Refresh the feature-123 worktrees of the api repo
+  fa wt overlay refresh feature-123 --repo api`,
+	Args:              cobra.MaximumNArgs(1),
+	Annotations:       locksWorkspace,
+	RunE:              runOverlayRefresh,
+	ValidArgsFunction: getBranchCompletions,
+}
+
+func init() {
+	worktreeCmd.AddCommand(overlayCmd)
+	overlayCmd.AddCommand(overlayRefreshCmd)
+
+	overlayRefreshCmd.Flags().BoolVar(&overlayJSON, "json", false, "Output result as JSON")
+	addRepoSelectionFlags(overlayRefreshCmd)
+	addDryRunFlag(overlayRefreshCmd)
+}
+
+func runOverlayRefresh(cmd *cobra.Command, args []string) error {
+	branch := ""
+	if len(args) > 0 {
+		branch = args[0]
+	}
+
+	ws, err := workspace.Discover("")
+	if err != nil {
+		return printOverlayError(err)
+	}
+
+	results, err := ws.RefreshOverlays(branch, repoSelector())
+	if err != nil {
+		return printOverlayError(err)
+	}
+
+	if dryRun {
+		var problems []planProblem
+		for _, r := range results {
+			if r.Error != "" {
+				problems = append(problems, planProblem{Repo: r.Repo, Status: "error", Message: r.Error})
+			}
+		}
+		return printPlan(ws, overlayJSON, problems)
+	}
+	if overlayJSON {
+		return output.PrintJSON(results)
+	}
+
+	failed := 0
+	for _, r := range results {
+		applied := append(append([]string{}, r.Copied...), r.Linked...)
+		switch {
+		case r.Error != "":
+			failed++
+			output.PrintErrorMessage("✗ %s (%s): %s", r.Repo, r.Branch, r.Error)
+		case len(applied) == 0:
+			output.PrintMessage("  %s (%s): no overlays", r.Repo, r.Branch)
+		default:
+			output.PrintMessage("✓ %s (%s): %s", r.Repo, r.Branch, strings.Join(applied, ", "))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to apply overlays to %d worktree(s)", failed)
+	}
+	return nil
+}
+
+func printOverlayError(err error) error {
+	if overlayJSON {
+		_ = output.PrintError(err)
+	}
+	return err
+}
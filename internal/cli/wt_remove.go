@@ -1,16 +1,18 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 
 	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/errors"
 	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
 	"github.com/foundagent/foundagent/internal/output"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
@@ -45,10 +47,14 @@ After removal, the VS Code workspace file is updated to remove those folders.`,
   # Remove worktrees AND delete branches
   fa wt remove feature-123 --delete-branch
 
+  # Show what would be removed, without removing it
+  fa wt remove feature-123 --delete-branch --dry-run
+
   # JSON output for automation
   fa wt remove feature-123 --json`,
-	Args: cobra.ExactArgs(1),
-	RunE: runRemove,
+	Args:        cobra.ExactArgs(1),
+	Annotations: locksWorkspace,
+	RunE:        runRemove,
 }
 
 type removeResult struct {
@@ -73,6 +79,8 @@ func init() {
 	removeCmd.Flags().BoolVar(&removeForce, "force", false, "Force removal despite uncommitted changes")
 	removeCmd.Flags().BoolVar(&removeDeleteBranch, "delete-branch", false, "Delete branches after removing worktrees")
 	removeCmd.Flags().BoolVar(&removeJSON, "json", false, "Output result as JSON")
+	addRepoSelectionFlags(removeCmd)
+	addDryRunFlag(removeCmd)
 	worktreeCmd.AddCommand(removeCmd)
 }
 
@@ -116,6 +124,18 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Narrow to the selected repos
+	repos, err := repoSelector().Filter(cfg.Repos)
+	if err != nil {
+		if removeJSON {
+			_ = output.PrintError(err)
+		} else {
+			output.PrintErrorMessage("Error: %v", err)
+		}
+		return err
+	}
+	cfg.Repos = repos
+
 	// Find all worktrees for this branch
 	worktreesToRemove, err := findWorktreesForBranch(ws, cfg, targetBranch)
 	if err != nil {
@@ -171,11 +191,15 @@ func runRemove(cmd *cobra.Command, args []string) error {
 
 	// Delete branches if requested and all removals succeeded
 	branchesDeleted := false
+	var branchErr error
 	if removeDeleteBranch && failed == 0 {
 		if err := deleteBranchesForRepos(ws, cfg, targetBranch, worktreesToRemove); err != nil {
-			if removeJSON {
+			switch {
+			case dryRun:
+				branchErr = err // Reported with the plan
+			case removeJSON:
 				_ = output.PrintError(err)
-			} else {
+			default:
 				output.PrintErrorMessage("Warning: Failed to delete branches: %v", err)
 			}
 		} else {
@@ -194,6 +218,18 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output results
+	if dryRun {
+		var problems []planProblem
+		for _, r := range results {
+			if r.Status == "failed" {
+				problems = append(problems, planProblem{Repo: r.RepoName, Status: "error", Message: r.Error})
+			}
+		}
+		if branchErr != nil {
+			problems = append(problems, planProblem{Status: "warning", Message: "Branches would not be deleted: " + branchErr.Error()})
+		}
+		return printPlan(ws, removeJSON, problems)
+	}
 	if removeJSON {
 		return printRemoveJSON(removeOutput{
 			Branch:          targetBranch,
@@ -336,46 +372,44 @@ func preValidateRemoval(ws *workspace.Workspace, cfg *config.Config, worktrees [
 }
 
 func removeWorktreesParallel(ws *workspace.Workspace, cfg *config.Config, worktrees []worktreeToRemove) []removeResult {
-	var wg sync.WaitGroup
 	results := make([]removeResult, len(worktrees))
 
-	for i, wt := range worktrees {
-		wg.Add(1)
-		go func(idx int, w worktreeToRemove) {
-			defer wg.Done()
+	workspace.ForEachParallel(context.Background(), ws.Jobs(), len(worktrees), func(ctx context.Context, idx int) {
+		w := worktrees[idx]
+		result := removeResult{
+			RepoName:     w.RepoName,
+			Branch:       w.Branch,
+			WorktreePath: w.WorktreePath,
+		}
 
-			result := removeResult{
-				RepoName:     w.RepoName,
-				Branch:       w.Branch,
-				WorktreePath: w.WorktreePath,
-			}
+		// Remove using git worktree remove, keeping the worktree's changes for
+		// 'fa undo'
+		saved := journal.SaveWorktree(w.RepoName, w.BareRepoPath, w.WorktreePath)
+		err := git.WorktreeRemove(w.BareRepoPath, w.WorktreePath, removeForce)
+		if err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+			results[idx] = result
+			return
+		}
 
-			// Remove using git worktree remove
-			err := git.WorktreeRemove(w.BareRepoPath, w.WorktreePath, removeForce)
-			if err != nil {
+		// Verify directory is gone (git should have removed it). In a dry run
+		// git has not run, so the directory is still there.
+		if _, err := os.Stat(w.WorktreePath); err == nil && !dryrun.Enabled() {
+			// Directory still exists, try to remove it manually
+			if err := os.RemoveAll(w.WorktreePath); err != nil {
 				result.Status = "failed"
-				result.Error = err.Error()
+				result.Error = fmt.Sprintf("failed to remove directory: %v", err)
 				results[idx] = result
 				return
 			}
+		}
 
-			// Verify directory is gone (git should have removed it)
-			if _, err := os.Stat(w.WorktreePath); err == nil {
-				// Directory still exists, try to remove it manually
-				if err := os.RemoveAll(w.WorktreePath); err != nil {
-					result.Status = "failed"
-					result.Error = fmt.Sprintf("failed to remove directory: %v", err)
-					results[idx] = result
-					return
-				}
-			}
-
-			result.Status = "removed"
-			results[idx] = result
-		}(i, wt)
-	}
+		journal.Record(saved)
+		result.Status = "removed"
+		results[idx] = result
+	})
 
-	wg.Wait()
 	return results
 }
 
@@ -404,9 +438,11 @@ func deleteBranchesForRepos(ws *workspace.Workspace, cfg *config.Config, branch
 
 	// Delete branches
 	for _, wt := range worktrees {
+		saved := journal.SaveBranch(wt.RepoName, wt.BareRepoPath, branch)
 		if err := git.DeleteBranch(wt.BareRepoPath, branch, removeForce); err != nil {
 			return err
 		}
+		journal.Record(saved)
 	}
 
 	return nil
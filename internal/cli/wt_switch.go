@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
 	"github.com/foundagent/foundagent/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -26,6 +28,10 @@ var switchCmd = &cobra.Command{
 This command updates the .code-workspace file to point to the target branch's
 worktrees across all repositories. Your current worktrees remain unchanged.
 
+A branch may only have worktrees in some repositories. Repositories without it
+keep showing their default branch worktree. With --create, worktrees are added
+to the repositories that lack them, limited by --repo, --group or --tag.
+
 Examples:
   # Switch to feature-123 branch
   fa wt switch feature-123
@@ -36,12 +42,16 @@ Examples:
   # Create worktrees and switch if they don't exist
   fa wt switch new-feature --create
 
+  # Create the branch in the api and web repos only
+  fa wt switch new-feature --create --repo api --repo web
+
   # Create from a specific branch
   fa wt switch hotfix --create --from release-1.0
 
   # Switch with JSON output
   fa wt switch feature-123 --json`,
 	ValidArgsFunction: getBranchCompletions,
+	Annotations:       locksWorkspace,
 	RunE:              runSwitch,
 }
 
@@ -53,6 +63,8 @@ func init() {
 	switchCmd.Flags().StringVarP(&switchFrom, "from", "f", "", "Source branch for new worktrees (only with --create)")
 	switchCmd.Flags().BoolVarP(&switchQuiet, "quiet", "q", false, "Suppress warnings")
 	switchCmd.Flags().BoolVar(&switchJSON, "json", false, "Output in JSON format")
+	addRepoSelectionFlags(switchCmd)
+	addDryRunFlag(switchCmd)
 }
 
 func runSwitch(cmd *cobra.Command, args []string) error {
@@ -151,18 +163,24 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else if switchCreate && isPartial {
-		// Create missing worktrees for partial branch
+		// Create missing worktrees for partial branch, in the selected repos only
+		selected, err := ws.SelectRepoNames(state, repoSelector())
+		if err != nil {
+			return err
+		}
+		missingRepos = slices.DeleteFunc(missingRepos, func(name string) bool {
+			return !slices.Contains(selected, name)
+		})
 		if err := createMissingWorktrees(ws, targetBranch, missingRepos); err != nil {
 			return err
 		}
-	} else if isPartial && !switchQuiet {
-		// Warn about partial worktrees
-		fmt.Printf("\n⚠️  Warning: Branch '%s' only exists in %d of %d repositories\n", targetBranch, reposWithBranch, totalRepos)
-		fmt.Println("Missing in:")
-		for _, repo := range missingRepos {
-			fmt.Printf("  - %s\n", repo)
+	}
+
+	// Creating worktrees saved the state with them
+	if switchCreate {
+		if state, err = ws.LoadState(); err != nil {
+			return err
 		}
-		fmt.Println()
 	}
 
 	// Warn about uncommitted changes (US2)
@@ -185,6 +203,9 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output result
+	if dryRun {
+		return printPlan(ws, switchJSON, nil)
+	}
 	if switchJSON {
 		return outputJSON(map[string]interface{}{
 			"switched_to":     targetBranch,
@@ -301,10 +322,20 @@ func createWorktreesForBranch(ws *workspace.Workspace, branch string) error {
 		}
 	}
 
-	fmt.Printf("Creating worktrees for branch '%s' from '%s'...\n", branch, sourceBranch)
+	if !dryRun {
+		fmt.Printf("Creating worktrees for branch '%s' from '%s'...\n", branch, sourceBranch)
+	}
+
+	sparse := ws.SparsePaths()
 
-	// Create worktrees for all repos
-	for repoName, repo := range state.Repositories {
+	selected, err := ws.SelectRepoNames(state, repoSelector())
+	if err != nil {
+		return err
+	}
+
+	// Create worktrees for the selected repos
+	for _, repoName := range selected {
+		repo := state.Repositories[repoName]
 		bareRepoPath := ws.BareRepoPath(repoName)
 		worktreePath := ws.WorktreePath(repoName, branch)
 
@@ -314,9 +345,14 @@ func createWorktreesForBranch(ws *workspace.Workspace, branch string) error {
 		}
 
 		// Create the worktree with new branch
-		if err := git.WorktreeAddNew(bareRepoPath, worktreePath, branch, sourceBranch); err != nil {
+		if err := git.WorktreeAddNew(bareRepoPath, worktreePath, branch, sourceBranch, sparse[repoName]...); err != nil {
 			return fmt.Errorf("Failed to create worktree for %s: %w", repoName, err)
 		}
+		journal.WorktreeCreated(repoName, bareRepoPath, worktreePath, branch)
+
+		if err := ws.SetupWorktree(repoName, worktreePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", repoName, err)
+		}
 
 		// Add worktree to VS Code workspace
 		if err := ws.AddWorktreeFolder(worktreePath); err != nil {
@@ -332,7 +368,9 @@ func createWorktreesForBranch(ws *workspace.Workspace, branch string) error {
 		return err
 	}
 
-	fmt.Printf("✓ Created worktrees for branch '%s'\n", branch)
+	if !dryRun {
+		fmt.Printf("✓ Created worktrees for branch '%s'\n", branch)
+	}
 	return nil
 }
 
@@ -356,7 +394,11 @@ func createMissingWorktrees(ws *workspace.Workspace, branch string, missingRepos
 		}
 	}
 
-	fmt.Printf("Creating missing worktrees for branch '%s' from '%s'...\n", branch, sourceBranch)
+	if !dryRun {
+		fmt.Printf("Creating missing worktrees for branch '%s' from '%s'...\n", branch, sourceBranch)
+	}
+
+	sparse := ws.SparsePaths()
 
 	for _, repoName := range missingRepos {
 		repo := state.Repositories[repoName]
@@ -364,9 +406,14 @@ func createMissingWorktrees(ws *workspace.Workspace, branch string, missingRepos
 		worktreePath := ws.WorktreePath(repoName, branch)
 
 		// Create the worktree with new branch
-		if err := git.WorktreeAddNew(bareRepoPath, worktreePath, branch, sourceBranch); err != nil {
+		if err := git.WorktreeAddNew(bareRepoPath, worktreePath, branch, sourceBranch, sparse[repoName]...); err != nil {
 			return fmt.Errorf("Failed to create worktree for %s: %w", repoName, err)
 		}
+		journal.WorktreeCreated(repoName, bareRepoPath, worktreePath, branch)
+
+		if err := ws.SetupWorktree(repoName, worktreePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", repoName, err)
+		}
 
 		// Add worktree to VS Code workspace
 		if err := ws.AddWorktreeFolder(worktreePath); err != nil {
@@ -382,7 +429,9 @@ func createMissingWorktrees(ws *workspace.Workspace, branch string, missingRepos
 		return err
 	}
 
-	fmt.Printf("✓ Created missing worktrees for branch '%s'\n", branch)
+	if !dryRun {
+		fmt.Printf("✓ Created missing worktrees for branch '%s'\n", branch)
+	}
 	return nil
 }
 
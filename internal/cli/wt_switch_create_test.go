@@ -169,3 +169,31 @@ func TestCheckForUpdates_PrintsVersion(t *testing.T) {
 	// Should not return error even if update check fails
 	assert.NoError(t, err)
 }
+
+func TestRunSwitch_CreateKeepsWorktreesInState(t *testing.T) {
+	ws, repos := setupExtendWorkspace(t)
+	for _, repo := range repos {
+		require.NoError(t, ws.AddRepository(&workspace.Repository{
+			Name:          repo.Name,
+			URL:           repo.URL,
+			DefaultBranch: repo.DefaultBranch,
+			BareRepoPath:  ws.BareRepoPath(repo.Name),
+		}))
+	}
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(ws.Path))
+	switchCreate = true
+	defer func() { switchCreate = false }()
+
+	_, err := captureConfigOutput(t, func() error { return runSwitch(switchCmd, []string{"other"}) })
+	require.NoError(t, err)
+
+	state, err := ws.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, "other", state.CurrentBranch)
+	for _, repo := range repos {
+		assert.Contains(t, state.Repositories[repo.Name].Worktrees, "other", repo.Name)
+	}
+}
@@ -62,6 +62,41 @@ func TestLoadSaveTOML(t *testing.T) {
 	assert.Equal(t, "repo", loadedCfg.Repos[0].Name)
 }
 
+func TestSaveTOML_KeyOrderAndQuoting(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".foundagent.toml")
+
+	cfg := DefaultConfig("test-ws")
+	cfg.Repos = []RepoConfig{{
+		URL:     "https://github.com/org/repo.git",
+		Name:    "repo",
+		Tags:    []string{"go", "be"},
+		Remotes: map[string]string{"up.stream": `say "hi"`},
+	}}
+	require.NoError(t, SaveTOML(configPath, cfg))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, `version = 1
+
+[workspace]
+  name = "test-ws"
+
+[[repos]]
+  url = "https://github.com/org/repo.git"
+  name = "repo"
+  tags = ["go", "be"]
+  [repos.remotes]
+    "up.stream" = "say \"hi\""
+
+[settings]
+  auto_create_worktree = true
+`, string(data))
+
+	loaded, err := LoadTOML(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Repos, loaded.Repos)
+}
+
 func TestLoadSaveJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, ".foundagent.json")
@@ -228,6 +263,46 @@ func TestValidate(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "valid timeouts",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "test"},
+				Settings:  SettingsConfig{Timeouts: TimeoutsConfig{Fetch: "90s", Clone: "1h"}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid timeout",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "test"},
+				Settings:  SettingsConfig{Timeouts: TimeoutsConfig{Push: "10"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "negative timeout",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "test"},
+				Settings:  SettingsConfig{Timeouts: TimeoutsConfig{Local: "-1m"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "retries turned off",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "test"},
+				Settings:  SettingsConfig{Retries: -1},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid retries",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "test"},
+				Settings:  SettingsConfig{Retries: -2},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -502,3 +577,62 @@ func TestSaveTOML_WriteError(t *testing.T) {
 	err := SaveTOML("/root/.foundagent.toml", cfg)
 	assert.Error(t, err)
 }
+
+func TestAddRepo_KeepsExistingEntry(t *testing.T) {
+	cfg := DefaultConfig("test")
+	cfg.Repos = []RepoConfig{{
+		URL:    "https://github.com/org/mono.git",
+		Name:   "mono",
+		Sparse: []string{"services/api"},
+		Tags:   []string{"go"},
+	}}
+
+	AddRepo(cfg, "git@github.com:org/mono.git", "mono", "develop")
+
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, "git@github.com:org/mono.git", cfg.Repos[0].URL)
+	assert.Equal(t, "develop", cfg.Repos[0].DefaultBranch)
+	assert.Equal(t, []string{"services/api"}, cfg.Repos[0].Sparse)
+	assert.Equal(t, []string{"go"}, cfg.Repos[0].Tags)
+}
+
+func TestValidSparseDir(t *testing.T) {
+	valid := []string{"api", "services/api", "libs/common/"}
+	invalid := []string{"", ".", "..", "../api", "/abs/path", "web/*", "src/[ab]", "!api"}
+
+	for _, dir := range valid {
+		assert.True(t, ValidSparseDir(dir), dir)
+	}
+	for _, dir := range invalid {
+		assert.False(t, ValidSparseDir(dir), dir)
+	}
+}
+
+func TestValidFilter(t *testing.T) {
+	for _, filter := range []string{"blob:none", "blob:limit=1m", "blob:limit=1024", "tree:0"} {
+		assert.True(t, ValidFilter(filter), filter)
+	}
+	for _, filter := range []string{"", "none", "blob:limit=", "tree:", "sparse:oid=abc", "blob:none "} {
+		assert.False(t, ValidFilter(filter), filter)
+	}
+}
+
+func TestAddRepo_MatchesInferredName(t *testing.T) {
+	cfg := DefaultConfig("test")
+	cfg.Repos = []RepoConfig{{URL: "https://github.com/org/app.git", Tags: []string{"web"}}}
+
+	// An entry listed by URL only is updated rather than duplicated
+	AddRepo(cfg, "https://github.com/org/app.git", "app", "main")
+
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, "main", cfg.Repos[0].DefaultBranch)
+	assert.Equal(t, []string{"web"}, cfg.Repos[0].Tags)
+	assert.True(t, HasRepo(cfg, "app"))
+
+	// Changes through GetRepo land in the config
+	GetRepo(cfg, "app").CloneDepth = 1
+	assert.Equal(t, 1, cfg.Repos[0].CloneDepth)
+
+	assert.True(t, RemoveRepo(cfg, "app"))
+	assert.Empty(t, cfg.Repos)
+}
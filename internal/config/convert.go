@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// String returns the format's name as accepted by ParseFormat
+func (f ConfigFormat) String() string {
+	switch f {
+	case FormatTOML:
+		return "toml"
+	case FormatJSON:
+		return "json"
+	default:
+		return "yaml"
+	}
+}
+
+// FileName returns the config file name written for the format
+func (f ConfigFormat) FileName() string {
+	return ".foundagent." + f.String()
+}
+
+// ParseFormat parses a config format name: yaml, yml, toml or json
+func ParseFormat(name string) (ConfigFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatYAML, errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("Unknown config format: %s", name),
+			"Use yaml, toml or json",
+		)
+	}
+}
+
+// HasConfig reports whether dir contains a workspace config file in any format
+func HasConfig(dir string) bool {
+	for _, name := range ConfigFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertResult describes a completed config conversion
+type ConvertResult struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Converted bool   `json:"converted"`
+}
+
+// Convert rewrites the workspace config file in another format and removes
+// the old file. The config must load and validate first. Only the local file
+// is converted; included files and include paths are left as they are.
+//
+// The new file is written under a temporary name and renamed into place, so a
+// failed write never leaves a partial config. If the old file cannot be
+// removed the new one is removed again, leaving the workspace unchanged.
+func Convert(workspaceRoot string, to ConfigFormat) (*ConvertResult, error) {
+	fromPath, _, err := FindConfig(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse to convert a config that does not load
+	if _, err := Load(workspaceRoot); err != nil {
+		return nil, err
+	}
+
+	local, err := LoadLocal(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	toPath := filepath.Join(workspaceRoot, to.FileName())
+	result := &ConvertResult{From: fromPath, To: toPath}
+	if toPath == fromPath {
+		return result, nil
+	}
+
+	if _, err := os.Stat(toPath); err == nil {
+		return nil, errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("%s already exists", to.FileName()),
+			"Remove or rename it before converting",
+		)
+	}
+
+	// A dry run plans the new file in place and the removal of the old one
+	if dryrun.Enabled() {
+		if err := saveAs(toPath, to, local); err != nil {
+			return nil, err
+		}
+		if err := dryrun.RemoveAll(fromPath); err != nil {
+			return nil, err
+		}
+		result.Converted = true
+		return result, nil
+	}
+
+	tmpPath := toPath + ".tmp"
+	if err := saveAs(tmpPath, to, local); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	// Make sure the new file reads back the same before replacing the old one
+	if _, err := loadFile(tmpPath, to); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	if err := os.Rename(tmpPath, toPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to write converted config",
+			"Check file permissions",
+			err,
+		)
+	}
+
+	if err := os.Remove(fromPath); err != nil {
+		os.Remove(toPath)
+		return nil, errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			fmt.Sprintf("Failed to remove %s", filepath.Base(fromPath)),
+			"Check file permissions",
+			err,
+		)
+	}
+
+	result.Converted = true
+	return result, nil
+}
+
+// saveAs writes config to path in the given format
+func saveAs(path string, format ConfigFormat, config *Config) error {
+	switch format {
+	case FormatTOML:
+		return SaveTOML(path, config)
+	case FormatJSON:
+		return SaveJSON(path, config)
+	default:
+		return SaveYAML(path, config)
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]ConfigFormat{
+		"yaml": FormatYAML,
+		"yml":  FormatYAML,
+		"TOML": FormatTOML,
+		"json": FormatJSON,
+	}
+	for name, want := range tests {
+		got, err := ParseFormat(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestConfigFormat_FileName(t *testing.T) {
+	assert.Equal(t, ".foundagent.yaml", FormatYAML.FileName())
+	assert.Equal(t, ".foundagent.toml", FormatTOML.FileName())
+	assert.Equal(t, ".foundagent.json", FormatJSON.FileName())
+}
+
+func TestConvert_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), keysTestConfig)
+
+	original, err := Load(dir)
+	require.NoError(t, err)
+
+	for _, format := range []ConfigFormat{FormatTOML, FormatJSON, FormatYAML} {
+		result, err := Convert(dir, format)
+		require.NoError(t, err, format.String())
+		assert.True(t, result.Converted)
+		assert.Equal(t, filepath.Join(dir, format.FileName()), result.To)
+		assert.NoFileExists(t, result.From)
+
+		path, found, err := FindConfig(dir)
+		require.NoError(t, err)
+		assert.Equal(t, result.To, path)
+		assert.Equal(t, format, found)
+
+		converted, err := Load(dir)
+		require.NoError(t, err)
+		assert.Equal(t, original, converted, format.String())
+	}
+}
+
+func TestConvert_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, ".foundagent.yaml")
+	writeFile(t, yamlPath, keysTestConfig)
+
+	dryrun.Start()
+	defer dryrun.Stop()
+	result, err := Convert(dir, FormatTOML)
+	require.NoError(t, err)
+	assert.True(t, result.Converted)
+
+	steps := dryrun.Steps()
+	require.Len(t, steps, 2)
+	assert.Equal(t, dryrun.ActionCreate, steps[0].Action)
+	assert.Equal(t, filepath.Join(dir, ".foundagent.toml"), steps[0].Path)
+	assert.Equal(t, dryrun.Step{Action: dryrun.ActionRemove, Path: yamlPath}, steps[1])
+
+	// Nothing was written or removed
+	assert.FileExists(t, yamlPath)
+	assert.NoFileExists(t, filepath.Join(dir, ".foundagent.toml"))
+	assert.NoFileExists(t, filepath.Join(dir, ".foundagent.toml.tmp"))
+}
+
+func TestConvert_SameFormat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".foundagent.yaml")
+	writeFile(t, path, keysTestConfig)
+
+	result, err := Convert(dir, FormatYAML)
+	require.NoError(t, err)
+	assert.False(t, result.Converted)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, keysTestConfig, string(data))
+}
+
+func TestConvert_KeepsIncludesSeparate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), `
+workspace:
+  name: team
+repos:
+  - url: git@github.com:org/shared.git
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: ws
+include:
+  - team.yaml
+repos: []
+`)
+
+	_, err := Convert(dir, FormatJSON)
+	require.NoError(t, err)
+
+	local, err := LoadLocal(dir)
+	require.NoError(t, err)
+	assert.Empty(t, local.Repos)
+	assert.Equal(t, []string{"team.yaml"}, local.Include)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Repos, 1)
+}
+
+func TestConvert_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".foundagent.yaml")
+	writeFile(t, path, "workspace:\n  name: \"\"\n")
+
+	_, err := Convert(dir, FormatTOML)
+	assert.Error(t, err)
+	assert.FileExists(t, path)
+	assert.NoFileExists(t, filepath.Join(dir, ".foundagent.toml"))
+}
+
+func TestConvert_TargetExists(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yml"), keysTestConfig)
+	writeFile(t, filepath.Join(dir, ".foundagent.json"), "{}")
+
+	_, err := Convert(dir, FormatJSON)
+	assert.Error(t, err)
+	assert.FileExists(t, filepath.Join(dir, ".foundagent.yml"))
+}
+
+func TestHasConfig(t *testing.T) {
+	dir := t.TempDir()
+	assert.False(t, HasConfig(dir))
+
+	writeFile(t, filepath.Join(dir, ".foundagent.toml"), "")
+	assert.True(t, HasConfig(dir))
+}
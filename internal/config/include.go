@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+// IsIncluded reports whether a repo is listed by the workspace config's
+// included files, ignoring the local file's own repos and excludes
+func IsIncluded(workspaceRoot, name string) (bool, error) {
+	configPath, format, err := FindConfig(workspaceRoot)
+	if err != nil {
+		return false, err
+	}
+
+	local, err := loadFile(configPath, format)
+	if err != nil {
+		return false, err
+	}
+	if len(local.Include) == 0 {
+		return false, nil
+	}
+
+	local.Repos = nil
+	local.Exclude = nil
+	merged, _, err := resolveIncludes(local, configPath, format, map[string]bool{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, repo := range merged.Repos {
+		if repoKey(repo) == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveIncludes merges the files listed in config.Include underneath config.
+//
+// Precedence, lowest first: included files in list order (each with its own
+// includes resolved first), then the including file itself. Repos are merged
+// by name, with non-empty fields of a later entry overriding earlier ones, and
+// names listed in exclude are dropped from the result. A settings key from an
+// included file applies unless a later file sets the same key.
+//
+// Include paths are relative to the including file. Missing include files are
+// skipped with a warning, since they often live inside a repo that has not
+// been cloned yet. Returns the merged config and the set of settings keys
+// defined anywhere in the chain.
+func resolveIncludes(config *Config, path string, format ConfigFormat, seen map[string]bool) (*Config, map[string]bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	if seen[absPath] {
+		return nil, nil, errors.New(
+			errors.ErrCodeInvalidConfig,
+			fmt.Sprintf("Config include cycle detected at %s", path),
+			"Remove the circular entry from the include list",
+		)
+	}
+	seen[absPath] = true
+	defer delete(seen, absPath)
+
+	defined, err := definedSettings(path, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := &Config{Version: config.Version}
+	mergedDefined := make(map[string]bool)
+	skipped := false
+
+	for _, include := range config.Include {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(path), includePath)
+		}
+
+		if _, err := os.Stat(includePath); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Included config %s not found, skipping\n", include)
+			skipped = true
+			continue
+		}
+
+		includeFormat := detectFormat(includePath)
+		included, err := loadFile(includePath, includeFormat)
+		if err != nil {
+			return nil, nil, errors.Wrap(
+				errors.ErrCodeInvalidConfig,
+				fmt.Sprintf("Failed to load included config %s", include),
+				"Check the included file's syntax",
+				err,
+			)
+		}
+
+		if _, err := Migrate(included); err != nil {
+			return nil, nil, err
+		}
+
+		included, includedDefined, err := resolveIncludes(included, includePath, includeFormat, seen)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		merged = mergeConfig(merged, mergedDefined, included, includedDefined, false)
+	}
+
+	// A local override may target a repo from an include that is not
+	// available yet; keep it out of the result rather than failing validation
+	result := mergeConfig(merged, mergedDefined, config, defined, skipped)
+	result.Include = config.Include
+	return result, mergedDefined, nil
+}
+
+// mergeConfig layers over on top of base. baseDefined is updated with the
+// settings keys defined by over. When dropOrphans is set, URL-less entries in
+// over that do not match a base repo are discarded.
+func mergeConfig(base *Config, baseDefined map[string]bool, over *Config, overDefined map[string]bool, dropOrphans bool) *Config {
+	result := &Config{
+		Version:   over.Version,
+		Workspace: base.Workspace,
+		Repos:     make([]RepoConfig, 0, len(base.Repos)+len(over.Repos)),
+		Exclude:   over.Exclude,
+		Settings:  base.Settings,
+	}
+	if over.Workspace.Name != "" {
+		result.Workspace.Name = over.Workspace.Name
+	}
+	result.Workspace.Overlays = mergeOverlays(base.Workspace.Overlays, over.Workspace.Overlays)
+
+	result.Repos = append(result.Repos, base.Repos...)
+	index := make(map[string]int, len(result.Repos))
+	for i, repo := range result.Repos {
+		index[repoKey(repo)] = i
+	}
+
+	for _, repo := range over.Repos {
+		key := repoKey(repo)
+		if i, ok := index[key]; ok {
+			result.Repos[i] = mergeRepo(result.Repos[i], repo)
+			continue
+		}
+		if repo.URL == "" && dropOrphans {
+			continue
+		}
+		index[key] = len(result.Repos)
+		result.Repos = append(result.Repos, repo)
+	}
+
+	if len(over.Exclude) > 0 {
+		kept := result.Repos[:0]
+		for _, repo := range result.Repos {
+			if !containsAny(over.Exclude, repoKey(repo)) {
+				kept = append(kept, repo)
+			}
+		}
+		result.Repos = kept
+	}
+
+	mergeSettings(&result.Settings, &over.Settings, overDefined)
+	for key := range overDefined {
+		baseDefined[key] = true
+	}
+
+	return result
+}
+
+// mergeRepo overlays the non-empty fields of over onto base
+func mergeRepo(base, over RepoConfig) RepoConfig {
+	if over.Remotes != nil {
+		remotes := maps.Clone(base.Remotes)
+		if remotes == nil {
+			remotes = make(map[string]string, len(over.Remotes))
+		}
+		maps.Copy(remotes, over.Remotes)
+		base.Remotes = remotes
+	}
+	// url and remotes.origin name the same remote, so overriding one overrides both
+	if url := over.CloneURL(); url != "" {
+		base.URL = url
+		if _, ok := base.Remotes[DefaultRemote]; ok {
+			base.Remotes = maps.Clone(base.Remotes)
+			base.Remotes[DefaultRemote] = url
+		}
+	}
+	if over.Name != "" {
+		base.Name = over.Name
+	}
+	if over.DefaultBranch != "" {
+		base.DefaultBranch = over.DefaultBranch
+	}
+	if over.Groups != nil {
+		base.Groups = over.Groups
+	}
+	if over.Tags != nil {
+		base.Tags = over.Tags
+	}
+	if over.Sparse != nil {
+		base.Sparse = over.Sparse
+	}
+	if over.CloneDepth != 0 {
+		base.CloneDepth = over.CloneDepth
+	}
+	if over.Filter != "" {
+		base.Filter = over.Filter
+	}
+	if over.SingleBranch {
+		base.SingleBranch = true
+	}
+	if over.Submodules != "" {
+		base.Submodules = over.Submodules
+	}
+	if over.PushRemote != "" {
+		base.PushRemote = over.PushRemote
+	}
+	base.Overlays = mergeOverlays(base.Overlays, over.Overlays)
+	return base
+}
+
+// mergeOverlays replaces each overlay list that over declares
+func mergeOverlays(base, over Overlays) Overlays {
+	if over.Copy != nil {
+		base.Copy = over.Copy
+	}
+	if over.Symlink != nil {
+		base.Symlink = over.Symlink
+	}
+	return base
+}
+
+// mergeSettings copies each settings field whose key is in defined from over to base
+func mergeSettings(base, over *SettingsConfig, defined map[string]bool) {
+	baseValue := reflect.ValueOf(base).Elem()
+	overValue := reflect.ValueOf(over).Elem()
+	settingsType := baseValue.Type()
+
+	for i := 0; i < settingsType.NumField(); i++ {
+		key := strings.Split(settingsType.Field(i).Tag.Get("yaml"), ",")[0]
+		if defined[key] {
+			baseValue.Field(i).Set(overValue.Field(i))
+		}
+	}
+}
+
+// definedSettings returns the settings keys explicitly present in a config file
+func definedSettings(path string, format ConfigFormat) (map[string]bool, error) {
+	var raw map[string]interface{}
+
+	if format == FormatTOML {
+		data, err := dryrun.ReadFile(path)
+		if err == nil {
+			_, err = toml.Decode(string(data), &raw)
+		}
+		if err != nil {
+			return nil, errors.Wrap(
+				errors.ErrCodeInvalidConfig,
+				"Failed to parse TOML config",
+				"Check TOML syntax",
+				err,
+			)
+		}
+	} else {
+		// YAML is a superset of JSON, so one decoder covers both
+		data, err := dryrun.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(
+				errors.ErrCodeConfigNotFound,
+				"Failed to read config file",
+				"Check that the file exists and is readable",
+				err,
+			)
+		}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, errors.Wrap(
+				errors.ErrCodeInvalidConfig,
+				"Failed to parse config",
+				"Check the config file syntax",
+				err,
+			)
+		}
+	}
+
+	defined := make(map[string]bool)
+	if settings, ok := raw["settings"].(map[string]interface{}); ok {
+		for key := range settings {
+			defined[key] = true
+		}
+	}
+	return defined, nil
+}
+
+// repoKey returns the name a repo entry is merged under
+func repoKey(repo RepoConfig) string {
+	if repo.Name != "" {
+		return repo.Name
+	}
+	if name, err := git.InferName(repo.CloneURL()); err == nil {
+		return name
+	}
+	return repo.CloneURL()
+}
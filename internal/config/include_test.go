@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "shared", "team.foundagent.yaml"), `
+workspace:
+  name: team
+repos:
+  - url: git@github.com:org/api.git
+    groups: [backend]
+  - url: git@github.com:org/web.git
+  - url: git@github.com:org/legacy.git
+settings:
+  auto_create_worktree: false
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include:
+  - shared/team.foundagent.yaml
+repos:
+  - name: api
+    default_branch: develop
+  - url: git@github.com:me/scratch.git
+exclude:
+  - legacy
+`)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mine", cfg.Workspace.Name)
+	assert.Equal(t, []string{"shared/team.foundagent.yaml"}, cfg.Include)
+	require.Len(t, cfg.Repos, 3)
+
+	assert.Equal(t, "api", cfg.Repos[0].Name)
+	assert.Equal(t, "git@github.com:org/api.git", cfg.Repos[0].URL)
+	assert.Equal(t, "develop", cfg.Repos[0].DefaultBranch)
+	assert.Equal(t, []string{"backend"}, cfg.Repos[0].Groups)
+	assert.Equal(t, "web", cfg.Repos[1].Name)
+	assert.Equal(t, "scratch", cfg.Repos[2].Name)
+
+	// Local file does not set the key, so the included value applies
+	assert.False(t, cfg.Settings.AutoCreateWorktree)
+}
+
+func TestLoad_IncludeSettingsPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.toml"), `
+[workspace]
+name = "team"
+
+[settings]
+auto_create_worktree = false
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include: [team.toml]
+repos: []
+settings:
+  auto_create_worktree: true
+`)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	assert.True(t, cfg.Settings.AutoCreateWorktree)
+}
+
+func TestLoad_IncludeNested(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a", "base.yaml"), `
+repos:
+  - url: git@github.com:org/api.git
+`)
+	writeFile(t, filepath.Join(dir, "a", "team.yaml"), `
+include: [base.yaml]
+repos:
+  - name: api
+    tags: [go]
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include: [a/team.yaml]
+repos: []
+`)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, []string{"go"}, cfg.Repos[0].Tags)
+}
+
+func TestLoad_IncludeMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include: [repos/platform/worktrees/main/team.yaml]
+repos:
+  - name: api
+    default_branch: develop
+  - url: git@github.com:org/platform.git
+`)
+
+	// Overrides for the not-yet-available include are dropped, not rejected
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, "platform", cfg.Repos[0].Name)
+}
+
+func TestLoad_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), `
+include: [.foundagent.yaml]
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include: [team.yaml]
+`)
+
+	_, err := Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
+
+func TestLoad_IncludeInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), "repos: [")
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include: [team.yaml]
+`)
+
+	_, err := Load(dir)
+	assert.Error(t, err)
+}
+
+func TestLoadLocal_DoesNotMergeIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), `
+repos:
+  - url: git@github.com:org/api.git
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include: [team.yaml]
+repos: []
+`)
+
+	cfg, err := LoadLocal(dir)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Repos)
+
+	included, err := IsIncluded(dir, "api")
+	require.NoError(t, err)
+	assert.True(t, included)
+
+	included, err = IsIncluded(dir, "web")
+	require.NoError(t, err)
+	assert.False(t, included)
+}
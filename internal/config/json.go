@@ -1,15 +1,18 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
-	"os"
 
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/errors"
+	"gopkg.in/yaml.v3"
 )
 
 // LoadJSON loads config from a JSON file
 func LoadJSON(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	data, err := dryrun.ReadFile(path)
 	if err != nil {
 		return nil, errors.Wrap(
 			errors.ErrCodeConfigNotFound,
@@ -34,7 +37,15 @@ func LoadJSON(path string) (*Config, error) {
 
 // SaveJSON saves config to a JSON file
 func SaveJSON(path string, config *Config) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+	var compact bytes.Buffer
+	var data bytes.Buffer
+	node, err := encodable(config)
+	if err == nil {
+		err = writeJSONNode(&compact, node)
+	}
+	if err == nil {
+		err = json.Indent(&data, compact.Bytes(), "", "  ")
+	}
 	if err != nil {
 		return errors.Wrap(
 			errors.ErrCodeUnknown,
@@ -44,7 +55,7 @@ func SaveJSON(path string, config *Config) error {
 		)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(path, data.Bytes(), 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write config file",
@@ -55,3 +66,49 @@ func SaveJSON(path string, config *Config) error {
 
 	return nil
 }
+
+// writeJSONNode writes a YAML node as compact JSON, keeping the order of its
+// keys
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
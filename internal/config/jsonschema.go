@@ -0,0 +1,154 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect emitted by GenerateJSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema needed to describe the config
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	PatternProperties    map[string]*JSONSchema `json:"patternProperties,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Maximum              *int                   `json:"maximum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+}
+
+// GenerateJSONSchema builds a JSON Schema for the workspace config file from
+// the Config struct. Property names come from the yaml tags, and the
+// description, enum (comma-separated), pattern and minimum tags add
+// documentation and constraints; on a map the pattern constrains its keys.
+// Fields tagged jsonschema:"required" are required. Defaults are taken from
+// DefaultConfig.
+func GenerateJSONSchema() *JSONSchema {
+	schema := schemaForType(reflect.TypeOf(Config{}), reflect.ValueOf(*DefaultConfig("")))
+	schema.Schema = JSONSchemaDraft
+	schema.Title = "Foundagent workspace configuration"
+	schema.Description = "Schema for .foundagent.yaml, .foundagent.toml and .foundagent.json"
+
+	version := schema.Properties["version"]
+	version.Minimum = intPtr(0)
+	version.Maximum = intPtr(CurrentVersion)
+
+	// A repo needs a URL, either directly or as remotes.origin, unless it only
+	// overrides an included repo by name
+	schema.Properties["repos"].Items.AnyOf = []*JSONSchema{
+		{Required: []string{"url"}},
+		{Required: []string{"name"}},
+		{
+			Required:   []string{"remotes"},
+			Properties: map[string]*JSONSchema{"remotes": {Required: []string{DefaultRemote}}},
+		},
+	}
+
+	return schema
+}
+
+// schemaForType returns the schema for t. defaults holds a value of type t
+// whose non-zero scalar fields are recorded as defaults.
+func schemaForType(t reflect.Type, defaults reflect.Value) *JSONSchema {
+	switch t.Kind() {
+	case reflect.Struct:
+		schema := &JSONSchema{
+			Type:                 "object",
+			Properties:           make(map[string]*JSONSchema),
+			AdditionalProperties: boolPtr(false),
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key := yamlKey(field)
+			if key == "" || key == "-" {
+				continue
+			}
+
+			var fieldDefaults reflect.Value
+			if defaults.IsValid() {
+				fieldDefaults = defaults.Field(i)
+			}
+
+			property := schemaForType(field.Type, fieldDefaults)
+			property.Description = field.Tag.Get("description")
+			if enum := field.Tag.Get("enum"); enum != "" {
+				property.Enum = strings.Split(enum, ",")
+			}
+			if pattern := field.Tag.Get("pattern"); pattern != "" {
+				// Patterns on lists constrain each item, on maps each key
+				if property.Items != nil {
+					property.Items.Pattern = pattern
+				} else if property.PatternProperties != nil {
+					property.PatternProperties = map[string]*JSONSchema{pattern: property.PatternProperties[""]}
+				} else {
+					property.Pattern = pattern
+				}
+			}
+			if minimum := field.Tag.Get("minimum"); minimum != "" {
+				if n, err := strconv.Atoi(minimum); err == nil {
+					property.Minimum = intPtr(n)
+				}
+			}
+			if field.Tag.Get("jsonschema") == "required" {
+				schema.Required = append(schema.Required, key)
+				if property.Type == "string" {
+					property.MinLength = intPtr(1)
+				}
+			}
+
+			schema.Properties[key] = property
+		}
+		return schema
+
+	case reflect.Slice:
+		return &JSONSchema{
+			Type:  "array",
+			Items: schemaForType(t.Elem(), reflect.Value{}),
+		}
+
+	case reflect.Map:
+		// The empty pattern matches any key until a pattern tag replaces it
+		return &JSONSchema{
+			Type:                 "object",
+			PatternProperties:    map[string]*JSONSchema{"": schemaForType(t.Elem(), reflect.Value{})},
+			AdditionalProperties: boolPtr(false),
+		}
+
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean", Default: scalarDefault(defaults)}
+
+	case reflect.Int, reflect.Int64:
+		return &JSONSchema{Type: "integer", Default: scalarDefault(defaults)}
+
+	default:
+		return &JSONSchema{Type: "string", Default: scalarDefault(defaults)}
+	}
+}
+
+// scalarDefault returns the value of v if it is set and non-zero
+func scalarDefault(v reflect.Value) interface{} {
+	if !v.IsValid() || v.IsZero() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
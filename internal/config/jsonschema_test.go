@@ -0,0 +1,217 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	schema := GenerateJSONSchema()
+
+	assert.Equal(t, JSONSchemaDraft, schema.Schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"workspace"}, schema.Required)
+	require.NotNil(t, schema.AdditionalProperties)
+	assert.False(t, *schema.AdditionalProperties)
+
+	workspace := schema.Properties["workspace"]
+	require.NotNil(t, workspace)
+	assert.Equal(t, []string{"name"}, workspace.Required)
+	assert.Equal(t, 1, *workspace.Properties["name"].MinLength)
+	assert.Equal(t, "Workspace name", workspace.Properties["name"].Description)
+
+	repos := schema.Properties["repos"]
+	require.NotNil(t, repos)
+	assert.Equal(t, "array", repos.Type)
+	require.NotNil(t, repos.Items)
+	assert.Len(t, repos.Items.AnyOf, 3)
+	for _, key := range []string{"url", "name", "default_branch", "groups", "tags"} {
+		assert.Contains(t, repos.Items.Properties, key)
+		assert.NotEmpty(t, repos.Items.Properties[key].Description, key)
+	}
+	assert.Equal(t, labelPattern, repos.Items.Properties["groups"].Items.Pattern)
+	assert.Equal(t, labelPattern, repos.Items.Properties["tags"].Items.Pattern)
+	assert.Equal(t, filterPattern, repos.Items.Properties["filter"].Pattern)
+	assert.Equal(t, 0, *repos.Items.Properties["clone_depth"].Minimum)
+	remotes := repos.Items.Properties["remotes"]
+	assert.Equal(t, "object", remotes.Type)
+	require.Contains(t, remotes.PatternProperties, remotePattern)
+	assert.Equal(t, "string", remotes.PatternProperties[remotePattern].Type)
+	assert.False(t, *remotes.AdditionalProperties)
+
+	setting := schema.Properties["settings"].Properties["auto_create_worktree"]
+	assert.Equal(t, "boolean", setting.Type)
+	assert.Equal(t, true, setting.Default)
+
+	version := schema.Properties["version"]
+	assert.Equal(t, CurrentVersion, *version.Maximum)
+	assert.Equal(t, CurrentVersion, version.Default)
+}
+
+func TestGenerateJSONSchema_CoversEveryField(t *testing.T) {
+	var check func(t *testing.T, typ reflect.Type, schema *JSONSchema)
+	check = func(t *testing.T, typ reflect.Type, schema *JSONSchema) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				// Not part of the file
+				continue
+			}
+			property, ok := schema.Properties[yamlKey(field)]
+			if !assert.True(t, ok, "missing %s.%s", typ.Name(), field.Name) {
+				continue
+			}
+			assert.NotEmpty(t, property.Description, "%s.%s has no description", typ.Name(), field.Name)
+
+			switch field.Type.Kind() {
+			case reflect.Struct:
+				check(t, field.Type, property)
+			case reflect.Slice:
+				if field.Type.Elem().Kind() == reflect.Struct {
+					check(t, field.Type.Elem(), property.Items)
+				}
+			}
+		}
+	}
+
+	check(t, reflect.TypeOf(Config{}), GenerateJSONSchema())
+}
+
+func TestGenerateJSONSchema_IsValidJSON(t *testing.T) {
+	data, err := json.Marshal(GenerateJSONSchema())
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, JSONSchemaDraft, decoded["$schema"])
+}
+
+func TestSchemaForType_Enum(t *testing.T) {
+	type withEnum struct {
+		Mode string `yaml:"mode" enum:"fast,slow" description:"Mode"`
+	}
+
+	schema := schemaForType(reflect.TypeOf(withEnum{}), reflect.Value{})
+	assert.Equal(t, []string{"fast", "slow"}, schema.Properties["mode"].Enum)
+
+	assert.NoError(t, validateEnums("", reflect.ValueOf(withEnum{Mode: "fast"})))
+	assert.NoError(t, validateEnums("", reflect.ValueOf(withEnum{})))
+
+	err := validateEnums("", reflect.ValueOf(withEnum{Mode: "other"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid config at mode")
+}
+
+func TestValidate_FieldPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		path   string
+	}{
+		{
+			name:   "workspace name",
+			config: &Config{},
+			path:   "workspace.name",
+		},
+		{
+			name: "missing url",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{Name: "api"}},
+			},
+			path: "repos[0].url",
+		},
+		{
+			name: "invalid url",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git"}, {URL: "nope"}},
+			},
+			path: "repos[1].url",
+		},
+		{
+			name: "duplicate name",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git"}, {URL: "git@github.com:other/api.git"}},
+			},
+			path: "repos[1].name",
+		},
+		{
+			name: "invalid tag",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git", Tags: []string{"go", "two words"}}},
+			},
+			path: "repos[0].tags[1]",
+		},
+		{
+			name: "invalid sparse dir",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git", Sparse: []string{"services/api", "../libs"}}},
+			},
+			path: "repos[0].sparse[1]",
+		},
+		{
+			name: "negative clone depth",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git", CloneDepth: -1}},
+			},
+			path: "repos[0].clone_depth",
+		},
+		{
+			name: "unsupported filter",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git", Filter: "sparse:oid=abc"}},
+			},
+			path: "repos[0].filter",
+		},
+		{
+			name: "invalid submodules mode",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:org/api.git", Submodules: "shallow"}},
+			},
+			path: "repos[0].submodules",
+		},
+		{
+			name: "invalid remote url",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:me/api.git", Remotes: map[string]string{"upstream": "nope"}}},
+			},
+			path: "repos[0].remotes.upstream",
+		},
+		{
+			name: "origin does not match url",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:me/api.git", Remotes: map[string]string{"origin": "git@github.com:org/api.git"}}},
+			},
+			path: "repos[0].remotes.origin",
+		},
+		{
+			name: "unknown push remote",
+			config: &Config{
+				Workspace: WorkspaceConfig{Name: "ws"},
+				Repos:     []RepoConfig{{URL: "git@github.com:me/api.git", PushRemote: "fork"}},
+			},
+			path: "repos[0].push_remote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "Invalid config at "+tt.path+":")
+		})
+	}
+}
@@ -0,0 +1,499 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// KeyValue is a single flattened config entry
+type KeyValue struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// GetValue returns the effective value of a dotted config key, such as
+// settings.auto_create_worktree or repos.api.default_branch, with includes
+// merged. Repos are addressed by name or by index, as in repos[0].url.
+func GetValue(workspaceRoot, key string) (interface{}, error) {
+	cfg, err := Load(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := lookupKey(cfg, key, false)
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// ListValues returns every set config value with includes merged, as
+// flattened dotted keys
+func ListValues(workspaceRoot string) ([]KeyValue, error) {
+	cfg, err := Load(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []KeyValue
+	flatten("", reflect.ValueOf(cfg).Elem(), &values)
+	return values, nil
+}
+
+// SetValue sets a config key in the workspace's own config file. The value is
+// parsed according to the key's type; lists accept "a,b" or "[a, b]". The
+// result is validated with includes merged before anything is written.
+func SetValue(workspaceRoot, key, raw string) error {
+	if key == "version" {
+		return versionManaged()
+	}
+
+	local, err := LoadLocal(workspaceRoot)
+	if err != nil {
+		return err
+	}
+
+	// Overriding an included repo adds a name-only entry to the local file
+	if name := repoNameInKey(key); name != "" && !hasRepo(local, name) {
+		merged, err := Load(workspaceRoot)
+		if err != nil {
+			return err
+		}
+		if !hasRepo(merged, name) {
+			return repoNotFound(name)
+		}
+		local.Repos = append(local.Repos, RepoConfig{Name: name})
+	}
+
+	// Map entries, such as repos.api.remotes.upstream, are added or replaced
+	if m, entry, ok := lookupMapEntry(local, key); ok {
+		value, err := parseValue(key, m.Type().Elem(), raw)
+		if err != nil {
+			return err
+		}
+		if m.IsNil() {
+			m.Set(reflect.MakeMap(m.Type()))
+		}
+		m.SetMapIndex(reflect.ValueOf(entry), value)
+		return saveValidated(workspaceRoot, local)
+	}
+
+	target, err := lookupKey(local, key, true)
+	if err != nil {
+		return err
+	}
+
+	value, err := parseValue(key, target.Type(), raw)
+	if err != nil {
+		return err
+	}
+	target.Set(value)
+	defineSetting(local, key, true)
+
+	return saveValidated(workspaceRoot, local)
+}
+
+// UnsetValue resets a config key in the workspace's own config file to its
+// zero value, removing optional keys and whole repo entries from the file
+func UnsetValue(workspaceRoot, key string) error {
+	if key == "version" {
+		return versionManaged()
+	}
+
+	local, err := LoadLocal(workspaceRoot)
+	if err != nil {
+		return err
+	}
+
+	segments, err := parseKey(key)
+	if err != nil {
+		return err
+	}
+
+	// Removing a whole repo entry
+	if len(segments) >= 2 && segments[0] == "repos" {
+		if index, err := findRepo(local.Repos, strings.Join(segments[1:], ".")); err == nil {
+			local.Repos = append(local.Repos[:index], local.Repos[index+1:]...)
+			return saveValidated(workspaceRoot, local)
+		}
+		if len(segments) == 2 {
+			return repoNotFound(segments[1])
+		}
+	}
+
+	if m, entry, ok := lookupMapEntry(local, key); ok {
+		m.SetMapIndex(reflect.ValueOf(entry), reflect.Value{})
+		if m.Len() == 0 {
+			m.Set(reflect.Zero(m.Type()))
+		}
+		return saveValidated(workspaceRoot, local)
+	}
+
+	target, err := lookupKey(local, key, true)
+	if err != nil {
+		// Repos that are not in the local file have nothing to unset
+		if name := repoNameInKey(key); name != "" && !hasRepo(local, name) {
+			return nil
+		}
+		return err
+	}
+	target.Set(reflect.Zero(target.Type()))
+	if len(segments) == 2 {
+		defineSetting(local, key, false)
+	}
+
+	return saveValidated(workspaceRoot, local)
+}
+
+// defineSetting records whether the local file defines the setting a
+// settings.<name> key, possibly nested, falls under, so that saving writes it
+// or leaves it out. Other keys are ignored.
+func defineSetting(local *Config, key string, defined bool) {
+	segments, err := parseKey(key)
+	if err != nil || len(segments) < 2 || segments[0] != "settings" || local.fileSettings == nil {
+		return
+	}
+	if defined {
+		local.fileSettings[segments[1]] = true
+	} else {
+		delete(local.fileSettings, segments[1])
+	}
+}
+
+// saveValidated validates the local config merged with its includes and saves it
+func saveValidated(workspaceRoot string, local *Config) error {
+	configPath, format, err := FindConfig(workspaceRoot)
+	if err != nil {
+		return err
+	}
+
+	merged, _, err := resolveIncludes(local, configPath, format, map[string]bool{})
+	if err != nil {
+		return err
+	}
+	if err := Validate(merged); err != nil {
+		return err
+	}
+
+	// TOML is re-encoded as a whole, so comments would be lost
+	if format == FormatTOML {
+		if data, err := dryrun.ReadFile(configPath); err == nil && tomlHasComments(data) {
+			return commentedTOML(configPath)
+		}
+	}
+
+	return Save(workspaceRoot, local)
+}
+
+// parseKey splits a dotted key into segments, turning repos[0] into repos, 0
+func parseKey(key string) ([]string, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, invalidKey(key)
+	}
+
+	var segments []string
+	for _, part := range strings.Split(key, ".") {
+		if open := strings.Index(part, "["); open >= 0 && strings.HasSuffix(part, "]") {
+			segments = append(segments, part[:open], part[open+1:len(part)-1])
+			continue
+		}
+		if part == "" {
+			return nil, invalidKey(key)
+		}
+		segments = append(segments, part)
+	}
+	return segments, nil
+}
+
+// lookupKey walks a config to the field named by key. With settable set the
+// returned value can be assigned to.
+func lookupKey(cfg *Config, key string, settable bool) (reflect.Value, error) {
+	segments, err := parseKey(key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	current := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < len(segments); i++ {
+		segment := segments[i]
+
+		switch current.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByTag(current, segment)
+			if !ok {
+				return reflect.Value{}, invalidKey(key)
+			}
+			current = field
+
+		case reflect.Slice:
+			repos, ok := current.Interface().([]RepoConfig)
+			if !ok {
+				return reflect.Value{}, invalidKey(key)
+			}
+			// Repo names may contain dots, so try the longest match first
+			found := false
+			for j := len(segments); j > i; j-- {
+				name := strings.Join(segments[i:j], ".")
+				if index, err := findRepo(repos, name); err == nil {
+					current = current.Index(index)
+					i = j - 1
+					found = true
+					break
+				}
+			}
+			if !found {
+				return reflect.Value{}, repoNotFound(segment)
+			}
+
+		case reflect.Map:
+			// Map keys may contain dots, so the rest of the key names the entry
+			entry := current.MapIndex(reflect.ValueOf(strings.Join(segments[i:], ".")))
+			if !entry.IsValid() {
+				return reflect.Value{}, invalidKey(key)
+			}
+			current = entry
+			i = len(segments)
+
+		default:
+			return reflect.Value{}, invalidKey(key)
+		}
+	}
+
+	if settable && !current.CanSet() {
+		return reflect.Value{}, invalidKey(key)
+	}
+	return current, nil
+}
+
+// lookupMapEntry splits a key naming an entry of a map field, such as
+// repos.api.remotes.upstream, into the settable map and the entry's key
+func lookupMapEntry(cfg *Config, key string) (reflect.Value, string, bool) {
+	for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+		m, err := lookupKey(cfg, key[:i], true)
+		if err == nil && m.Kind() == reflect.Map {
+			return m, key[i+1:], true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// fieldByTag finds a struct field by its yaml key
+func fieldByTag(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if yamlKey(t.Field(i)) == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// findRepo returns the index of a repo by name or numeric index
+func findRepo(repos []RepoConfig, nameOrIndex string) (int, error) {
+	for i, repo := range repos {
+		if repoKey(repo) == nameOrIndex {
+			return i, nil
+		}
+	}
+	if index, err := strconv.Atoi(nameOrIndex); err == nil && index >= 0 && index < len(repos) {
+		return index, nil
+	}
+	return -1, repoNotFound(nameOrIndex)
+}
+
+// hasRepo reports whether cfg has a repo with the given name, inferred or explicit
+func hasRepo(cfg *Config, name string) bool {
+	_, err := findRepo(cfg.Repos, name)
+	return err == nil
+}
+
+// repoNameInKey returns the repo name in a repos.<name>.<field> key, where
+// the field may be nested, such as overlays.copy, or a map entry, such as
+// remotes.upstream
+func repoNameInKey(key string) string {
+	segments, err := parseKey(key)
+	if err != nil || len(segments) < 3 || segments[0] != "repos" {
+		return ""
+	}
+	if _, err := strconv.Atoi(segments[1]); err == nil {
+		return ""
+	}
+
+	// The repo name runs up to the segments that name a repo field
+	repoType := reflect.TypeOf(RepoConfig{})
+	for j := len(segments) - 1; j >= 2; j-- {
+		if isFieldPath(repoType, segments[j:]) {
+			return strings.Join(segments[1:j], ".")
+		}
+	}
+	return strings.Join(segments[1:len(segments)-1], ".")
+}
+
+// isFieldPath reports whether segments name a field of struct type t: a
+// field, a field of a nested struct, or a map field followed by an entry key
+func isFieldPath(t reflect.Type, segments []string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if yamlKey(field) != segments[0] {
+			continue
+		}
+		switch {
+		case len(segments) == 1:
+			return true
+		case field.Type.Kind() == reflect.Map:
+			return true
+		case field.Type.Kind() == reflect.Struct:
+			return isFieldPath(field.Type, segments[1:])
+		}
+	}
+	return false
+}
+
+// parseValue converts a command-line string to a value of type t
+func parseValue(key string, t reflect.Type, raw string) (reflect.Value, error) {
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(raw).Convert(t), nil
+	}
+
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return reflect.ValueOf(items).Convert(t), nil
+	}
+
+	if t.Kind() == reflect.Struct || t.Kind() == reflect.Map || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct) {
+		return reflect.Value{}, errors.New(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("Cannot set '%s' directly", key),
+			"Set one of its fields instead, e.g. 'fa config list' to see available keys",
+		)
+	}
+
+	value := reflect.New(t)
+	if err := yaml.Unmarshal([]byte(raw), value.Interface()); err != nil {
+		return reflect.Value{}, errors.Wrap(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("Invalid value for '%s': %s", key, raw),
+			fmt.Sprintf("Expected a %s", typeName(t)),
+			err,
+		)
+	}
+	return value.Elem(), nil
+}
+
+// flatten appends the leaf values under v to values
+func flatten(prefix string, v reflect.Value, values *[]KeyValue) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if strings.Contains(field.Tag.Get("yaml"), "omitempty") && v.Field(i).IsZero() {
+				continue
+			}
+			flatten(joinKey(prefix, yamlKey(field)), v.Field(i), values)
+		}
+
+	case reflect.Slice:
+		if repos, ok := v.Interface().([]RepoConfig); ok {
+			for i, repo := range repos {
+				flatten(joinKey(prefix, repoKey(repo)), v.Index(i), values)
+			}
+			return
+		}
+		*values = append(*values, KeyValue{Key: prefix, Value: v.Interface()})
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+		for _, key := range keys {
+			flatten(joinKey(prefix, key.String()), v.MapIndex(key), values)
+		}
+
+	default:
+		*values = append(*values, KeyValue{Key: prefix, Value: v.Interface()})
+	}
+}
+
+// FormatValue renders a config value for display
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Struct || rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map {
+		data, err := yaml.Marshal(value)
+		if err == nil {
+			return strings.TrimRight(string(data), "\n")
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func yamlKey(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("yaml"), ",")[0]
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean (true or false)"
+	case reflect.Int, reflect.Int64:
+		return "whole number"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return t.Kind().String()
+	}
+}
+
+func invalidKey(key string) error {
+	return errors.New(
+		errors.ErrCodeInvalidInput,
+		fmt.Sprintf("Unknown config key '%s'", key),
+		"Run 'fa config list' to see available keys",
+	)
+}
+
+func versionManaged() error {
+	return errors.New(
+		errors.ErrCodeInvalidInput,
+		"The config version cannot be changed directly",
+		"Run 'fa migrate' to upgrade the config",
+	)
+}
+
+func repoNotFound(name string) error {
+	return errors.New(
+		errors.ErrCodeRepoNotFound,
+		fmt.Sprintf("Repository '%s' not found in config", name),
+		"Run 'fa config list' to see configured repositories",
+	)
+}
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const keysTestConfig = `# Workspace config
+version: 1
+
+workspace:
+  # Workspace name
+  name: ws
+
+# Repositories
+repos:
+  - url: git@github.com:org/api.git
+    tags: [go]
+  - url: git@github.com:org/web.v2.git
+    name: web.v2
+
+settings:
+  # Keep this comment
+  auto_create_worktree: true
+`
+
+func TestGetValue(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), keysTestConfig)
+
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{"workspace.name", "ws"},
+		{"settings.auto_create_worktree", true},
+		{"repos.api.url", "git@github.com:org/api.git"},
+		{"repos.api.tags", []string{"go"}},
+		{"repos.web.v2.name", "web.v2"},
+		{"repos[1].url", "git@github.com:org/web.v2.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			value, err := GetValue(dir, tt.key)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestGetValue_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), keysTestConfig)
+
+	for _, key := range []string{"", "nope", "settings.nope", "repos.missing.url", "workspace.name.extra", "repos[9]"} {
+		_, err := GetValue(dir, key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestSetValue_PreservesComments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".foundagent.yaml")
+	writeFile(t, path, keysTestConfig)
+
+	require.NoError(t, SetValue(dir, "settings.auto_create_worktree", "false"))
+	require.NoError(t, SetValue(dir, "repos.api.default_branch", "develop"))
+	require.NoError(t, SetValue(dir, "repos.web.v2.tags", "js, frontend"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# Workspace config")
+	assert.Contains(t, content, "# Workspace name")
+	assert.Contains(t, content, "# Keep this comment")
+	assert.Contains(t, content, "tags: [js, frontend]")
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	assert.False(t, cfg.Settings.AutoCreateWorktree)
+	assert.Equal(t, "develop", cfg.Repos[0].DefaultBranch)
+	assert.Equal(t, []string{"go"}, cfg.Repos[0].Tags)
+	assert.Equal(t, []string{"js", "frontend"}, cfg.Repos[1].Tags)
+}
+
+func TestSetValue_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".foundagent.yaml")
+	writeFile(t, path, keysTestConfig)
+
+	assert.Error(t, SetValue(dir, "settings.auto_create_worktree", "maybe"))
+	assert.Error(t, SetValue(dir, "workspace.name", " "))
+	assert.Error(t, SetValue(dir, "repos.api.url", "not a url"))
+	assert.Error(t, SetValue(dir, "repos.api.tags", "has space"))
+	assert.Error(t, SetValue(dir, "repos.missing.url", "git@github.com:org/x.git"))
+	assert.Error(t, SetValue(dir, "repos.api", "x"))
+	assert.Error(t, SetValue(dir, "version", "9"))
+
+	// Nothing invalid was written
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, keysTestConfig, string(data))
+}
+
+func TestSetValue_KeepsIncludedSettings(t *testing.T) {
+	locals := map[string]string{
+		".foundagent.yaml": "workspace:\n  name: ws\ninclude:\n  - team.yaml\nrepos:\n  - url: git@github.com:org/api.git\n",
+		".foundagent.toml": "include = [\"team.yaml\"]\n\n[workspace]\nname = \"ws\"\n\n[[repos]]\nurl = \"git@github.com:org/api.git\"\n",
+		".foundagent.json": `{"workspace": {"name": "ws"}, "include": ["team.yaml"], "repos": [{"url": "git@github.com:org/api.git"}]}`,
+	}
+
+	for name, content := range locals {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, name)
+			writeFile(t, filepath.Join(dir, "team.yaml"), "settings:\n  auto_create_worktree: true\n  jobs: 4\n")
+			writeFile(t, path, content)
+
+			// Editing a repo leaves the included settings alone
+			require.NoError(t, SetValue(dir, "repos.api.default_branch", "develop"))
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "develop")
+			assert.NotContains(t, string(data), "auto_create_worktree")
+			value, err := GetValue(dir, "settings.auto_create_worktree")
+			require.NoError(t, err)
+			assert.Equal(t, true, value)
+
+			// A setting set locally is written, even with its zero value
+			require.NoError(t, SetValue(dir, "settings.auto_create_worktree", "false"))
+			data, err = os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "auto_create_worktree")
+			assert.NotContains(t, string(data), "jobs")
+			value, err = GetValue(dir, "settings.auto_create_worktree")
+			require.NoError(t, err)
+			assert.Equal(t, false, value)
+
+			// And unsetting it hands it back to the included file
+			require.NoError(t, UnsetValue(dir, "settings.auto_create_worktree"))
+			data, err = os.ReadFile(path)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "auto_create_worktree")
+			value, err = GetValue(dir, "settings.auto_create_worktree")
+			require.NoError(t, err)
+			assert.Equal(t, true, value)
+		})
+	}
+}
+
+func TestSetValue_IncludedRepoAddsOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), `
+workspace:
+  name: team
+repos:
+  - url: git@github.com:org/shared.git
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: ws
+include:
+  - team.yaml
+repos: []
+`)
+
+	require.NoError(t, SetValue(dir, "repos.shared.default_branch", "develop"))
+
+	local, err := LoadLocal(dir)
+	require.NoError(t, err)
+	require.Len(t, local.Repos, 1)
+	assert.Equal(t, RepoConfig{Name: "shared", DefaultBranch: "develop"}, local.Repos[0])
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, "git@github.com:org/shared.git", cfg.Repos[0].URL)
+	assert.Equal(t, "develop", cfg.Repos[0].DefaultBranch)
+}
+
+func TestUnsetValue(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".foundagent.yaml")
+	writeFile(t, path, keysTestConfig)
+
+	require.NoError(t, UnsetValue(dir, "repos.api.tags"))
+	require.NoError(t, UnsetValue(dir, "repos.web.v2"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "tags:")
+	assert.NotContains(t, string(data), "web.v2")
+	assert.Contains(t, string(data), "# Keep this comment")
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Repos, 1)
+	assert.Nil(t, cfg.Repos[0].Tags)
+
+	// Unsetting a required key fails validation
+	assert.Error(t, UnsetValue(dir, "workspace.name"))
+	assert.Error(t, UnsetValue(dir, "repos.missing"))
+}
+
+func TestListValues(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), keysTestConfig)
+
+	values, err := ListValues(dir)
+	require.NoError(t, err)
+
+	keys := make([]string, len(values))
+	for i, kv := range values {
+		keys[i] = kv.Key
+	}
+	assert.Equal(t, []string{
+		"version",
+		"workspace.name",
+		"repos.api.url",
+		"repos.api.name",
+		"repos.api.tags",
+		"repos.web.v2.url",
+		"repos.web.v2.name",
+		"settings.auto_create_worktree",
+	}, keys)
+}
+
+func TestSaveYAML_NewFileWithoutComments(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig("ws")
+	cfg.Repos = []RepoConfig{{URL: "git@github.com:org/api.git", Groups: []string{"backend"}}}
+
+	require.NoError(t, Save(dir, cfg))
+
+	data, err := os.ReadFile(filepath.Join(dir, ".foundagent.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "groups: [backend]")
+
+	loaded, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend"}, loaded.Repos[0].Groups)
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "text", FormatValue("text"))
+	assert.Equal(t, "true", FormatValue(true))
+	assert.Equal(t, "[a, b]", FormatValue([]string{"a", "b"}))
+	assert.Equal(t, "name: ws", FormatValue(WorkspaceConfig{Name: "ws"}))
+}
+
+func TestSetValue_CommentedTOML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".foundagent.toml")
+	commented := `# Workspace config
+version = 1
+
+[workspace]
+name = "ws" # Workspace name
+`
+	writeFile(t, path, commented)
+
+	// Rewriting would drop the comments, so nothing is written
+	err := SetValue(dir, "workspace.name", "other")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "comments")
+	assert.Error(t, UnsetValue(dir, "settings.auto_create_worktree"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, commented, string(data))
+
+	// Without comments the file is rewritten
+	writeFile(t, path, "version = 1\n\n[workspace]\nname = \"ws#1\"\n")
+	require.NoError(t, SetValue(dir, "workspace.name", "other"))
+	value, err := GetValue(dir, "workspace.name")
+	require.NoError(t, err)
+	assert.Equal(t, "other", value)
+}
+
+func TestTOMLHasComments(t *testing.T) {
+	tests := map[string]bool{
+		"a = 1\n":                         false,
+		"# top\na = 1\n":                  true,
+		"a = 1 # trailing\n":              true,
+		"a = \"#not\"\n":                  false,
+		"a = 'C:\\#'\n":                   false,
+		"a = \"q\\\"#\"\n":                false,
+		"a = \"\"\"\n#not\n\"\"\"\nb = 2": false,
+		"a = '''\n#not\n''' # yes\n":      true,
+	}
+	for src, want := range tests {
+		assert.Equal(t, want, tomlHasComments([]byte(src)), src)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/foundagent/foundagent/internal/errors"
+	"gopkg.in/yaml.v3"
 )
 
 // ConfigFormat represents the configuration file format
@@ -25,7 +26,8 @@ var ConfigFileNames = []string{
 	".foundagent.json",
 }
 
-// Load loads configuration from the workspace directory
+// Load loads configuration from the workspace directory, merging any
+// included config files
 func Load(workspaceRoot string) (*Config, error) {
 	// Try to find config file
 	configPath, format, err := FindConfig(workspaceRoot)
@@ -33,27 +35,24 @@ func Load(workspaceRoot string) (*Config, error) {
 		return nil, err
 	}
 
-	// Load based on format
-	var config *Config
-	switch format {
-	case FormatYAML:
-		config, err = LoadYAML(configPath)
-	case FormatTOML:
-		config, err = LoadTOML(configPath)
-	case FormatJSON:
-		config, err = LoadJSON(configPath)
-	default:
-		return nil, errors.New(
-			errors.ErrCodeInvalidConfig,
-			"Unknown config format",
-			"Use .yaml, .toml, or .json extension",
-		)
+	config, err := loadFile(configPath, format)
+	if err != nil {
+		return nil, err
 	}
 
-	if err != nil {
+	// Upgrade older schema versions in memory
+	if _, err := Migrate(config); err != nil {
 		return nil, err
 	}
 
+	// Merge included files, local entries taking precedence
+	if len(config.Include) > 0 {
+		config, _, err = resolveIncludes(config, configPath, format, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate config
 	if err := Validate(config); err != nil {
 		return nil, err
@@ -62,6 +61,60 @@ func Load(workspaceRoot string) (*Config, error) {
 	return config, nil
 }
 
+// LoadLocal loads the workspace config file on its own, without merging
+// includes or validating. Use it when modifying and saving the config so
+// that included entries are not copied into the local file.
+func LoadLocal(workspaceRoot string) (*Config, error) {
+	config, err := LoadRaw(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := Migrate(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadRaw loads the workspace config file exactly as written, without
+// migrating, merging includes or validating
+func LoadRaw(workspaceRoot string) (*Config, error) {
+	configPath, format, err := FindConfig(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+	config, err := loadFile(configPath, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Settings the file leaves out come from defaults or includes, and
+	// must not be written into it when the config is saved
+	config.fileSettings, err = definedSettings(configPath, format)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// loadFile loads a single config file in the given format
+func loadFile(path string, format ConfigFormat) (*Config, error) {
+	switch format {
+	case FormatYAML:
+		return LoadYAML(path)
+	case FormatTOML:
+		return LoadTOML(path)
+	case FormatJSON:
+		return LoadJSON(path)
+	default:
+		return nil, errors.New(
+			errors.ErrCodeInvalidConfig,
+			"Unknown config format",
+			"Use .yaml, .toml, or .json extension",
+		)
+	}
+}
+
 // FindConfig finds the configuration file in the workspace
 func FindConfig(workspaceRoot string) (string, ConfigFormat, error) {
 	var foundPaths []string
@@ -98,7 +151,7 @@ func Save(workspaceRoot string, config *Config) error {
 	configPath, format, err := FindConfig(workspaceRoot)
 	if err != nil {
 		// No existing config, create YAML
-		configPath = filepath.Join(workspaceRoot, ".foundagent.yaml")
+		configPath = filepath.Join(workspaceRoot, FormatYAML.FileName())
 		format = FormatYAML
 	}
 
@@ -132,3 +185,38 @@ func detectFormat(filename string) ConfigFormat {
 		return FormatYAML
 	}
 }
+
+// encodable encodes config as a YAML mapping for the YAML, TOML and JSON
+// writers. When config was read from a file, settings keep only the keys that
+// file defines; otherwise a setting the file leaves to a default or to an
+// included file would be written out with its zero value, overriding it.
+func encodable(config *Config) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := root.Encode(config); err != nil {
+		return nil, err
+	}
+	if config.fileSettings == nil {
+		return &root, nil
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "settings" {
+			continue
+		}
+		settings := root.Content[i+1]
+		kept := settings.Content[:0]
+		for j := 0; j+1 < len(settings.Content); j += 2 {
+			if config.fileSettings[settings.Content[j].Value] {
+				kept = append(kept, settings.Content[j], settings.Content[j+1])
+			}
+		}
+		settings.Content = kept
+
+		// A file without settings keeps going without them
+		if len(kept) == 0 {
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		}
+		break
+	}
+	return &root, nil
+}
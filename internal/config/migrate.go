@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// CurrentVersion is the config schema version written by this release
+const CurrentVersion = 1
+
+// Migration upgrades a config from one schema version to the next
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(config *Config) error
+}
+
+// migrations is the ordered registry of config upgrades. Each entry upgrades
+// a config at version From to From+1.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "Add config schema version",
+		Apply:       func(config *Config) error { return nil },
+	},
+}
+
+// PendingMigrations returns the migrations needed to bring a config at the
+// given version up to CurrentVersion
+func PendingMigrations(version int) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if m.From >= version && m.From < CurrentVersion {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// Migrate upgrades a config in place to CurrentVersion and returns the
+// migrations that were applied
+func Migrate(config *Config) ([]Migration, error) {
+	if config.Version > CurrentVersion {
+		return nil, errors.New(
+			errors.ErrCodeInvalidConfig,
+			fmt.Sprintf("Config version %d is newer than supported version %d", config.Version, CurrentVersion),
+			"Upgrade foundagent to use this workspace",
+		)
+	}
+
+	applied := PendingMigrations(config.Version)
+	for _, m := range applied {
+		if err := m.Apply(config); err != nil {
+			return nil, errors.Wrap(
+				errors.ErrCodeInvalidConfig,
+				fmt.Sprintf("Failed to migrate config from version %d", m.From),
+				"Fix the config manually or restore it from version control",
+				err,
+			)
+		}
+		config.Version = m.From + 1
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	cfg := &Config{Workspace: WorkspaceConfig{Name: "test"}}
+
+	applied, err := Migrate(cfg)
+	require.NoError(t, err)
+	assert.Len(t, applied, CurrentVersion)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+
+	// Already current
+	applied, err = Migrate(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestMigrate_NewerVersion(t *testing.T) {
+	cfg := &Config{Version: CurrentVersion + 1}
+	_, err := Migrate(cfg)
+	assert.Error(t, err)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	assert.Len(t, PendingMigrations(0), CurrentVersion)
+	assert.Empty(t, PendingMigrations(CurrentVersion))
+}
+
+func TestLoad_UnversionedConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), "workspace:\n  name: old\nrepos: []\n")
+
+	raw, err := LoadRaw(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, raw.Version)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+
+	local, err := LoadLocal(dir)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, local.Version)
+}
+
+func TestLoad_NewerConfigVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), "version: 99\nworkspace:\n  name: new\n")
+
+	_, err := Load(dir)
+	assert.Error(t, err)
+}
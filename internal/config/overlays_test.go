@@ -0,0 +1,89 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Overlays(t *testing.T) {
+	for _, path := range []string{".env", "certs", ".vscode/settings.json", "config/../.env"} {
+		assert.True(t, ValidOverlayPath(path), path)
+	}
+	for _, path := range []string{"", ".", "..", "../.env", "/etc/hosts", ".git", ".git/config"} {
+		assert.False(t, ValidOverlayPath(path), path)
+	}
+
+	cfg := &Config{
+		Workspace: WorkspaceConfig{Name: "ws", Overlays: Overlays{Symlink: []string{"AGENTS.md"}}},
+		Repos: []RepoConfig{{
+			URL:      "git@github.com:org/api.git",
+			Overlays: Overlays{Copy: []string{".env"}, Symlink: []string{"../secrets"}},
+		}},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid config at repos[0].overlays.symlink[0]:")
+
+	cfg.Repos[0].Overlays.Symlink = []string{"./.env"}
+	err = Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listed more than once")
+
+	cfg.Repos[0].Overlays.Symlink = nil
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestLoad_IncludeOverlays(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), `
+workspace:
+  name: team
+  overlays:
+    symlink: [AGENTS.md]
+repos:
+  - url: git@github.com:org/api.git
+    overlays:
+      copy: [.env]
+      symlink: [certs]
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include:
+  - team.yaml
+repos:
+  - name: api
+    overlays:
+      copy: [.env, .envrc]
+`)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AGENTS.md"}, cfg.Workspace.Overlays.Symlink)
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, []string{".env", ".envrc"}, cfg.Repos[0].Overlays.Copy)
+	assert.Equal(t, []string{"certs"}, cfg.Repos[0].Overlays.Symlink)
+}
+
+func TestSetValue_Overlays(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), keysTestConfig)
+
+	require.NoError(t, SetValue(dir, "workspace.overlays.symlink", "AGENTS.md"))
+	require.NoError(t, SetValue(dir, "repos.web.v2.overlays.copy", ".env, certs"))
+	assert.Error(t, SetValue(dir, "repos.api.overlays.copy", "../.env"))
+	assert.Error(t, SetValue(dir, "repos.api.overlays", ".env"))
+
+	value, err := GetValue(dir, "repos.web.v2.overlays.copy")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".env", "certs"}, value)
+
+	require.NoError(t, UnsetValue(dir, "workspace.overlays.symlink"))
+	cfg, err := LoadLocal(dir)
+	require.NoError(t, err)
+	assert.True(t, cfg.Workspace.Overlays.IsEmpty())
+	assert.Equal(t, []string{".env", "certs"}, cfg.Repos[1].Overlays.Copy)
+}
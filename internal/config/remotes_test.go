@@ -0,0 +1,106 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_RemotesOriginStandsInForURL(t *testing.T) {
+	cfg := &Config{
+		Workspace: WorkspaceConfig{Name: "ws"},
+		Repos: []RepoConfig{{
+			Remotes: map[string]string{
+				"origin":   "git@github.com:me/api.git",
+				"upstream": "git@github.com:org/api.git",
+			},
+		}},
+	}
+
+	require.NoError(t, Validate(cfg))
+	assert.Equal(t, "git@github.com:me/api.git", cfg.Repos[0].URL)
+	assert.Equal(t, "api", cfg.Repos[0].Name)
+}
+
+func TestValidate_RemoteNames(t *testing.T) {
+	for _, name := range []string{"upstream", "my-fork", "team.mirror", "fork_2"} {
+		assert.True(t, ValidRemoteName(name), name)
+	}
+	for _, name := range []string{"", "-x", ".hidden", "a/b", "two words"} {
+		assert.False(t, ValidRemoteName(name), name)
+	}
+
+	cfg := &Config{
+		Workspace: WorkspaceConfig{Name: "ws"},
+		Repos:     []RepoConfig{{URL: "git@github.com:me/api.git", Remotes: map[string]string{"a/b": "git@github.com:org/api.git"}}},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid config at repos[0].remotes:")
+}
+
+func TestRepoConfig_PushTarget(t *testing.T) {
+	assert.Equal(t, "", RepoConfig{URL: "git@github.com:org/api.git"}.PushTarget())
+	assert.Equal(t, "origin", RepoConfig{Remotes: map[string]string{"upstream": "x"}}.PushTarget())
+	assert.Equal(t, "upstream", RepoConfig{PushRemote: "upstream"}.PushTarget())
+}
+
+func TestLoad_IncludeForkOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), `
+workspace:
+  name: team
+repos:
+  - url: git@github.com:org/api.git
+`)
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), `
+workspace:
+  name: mine
+include:
+  - team.yaml
+repos:
+  - name: api
+    remotes:
+      origin: git@github.com:me/api.git
+      upstream: git@github.com:org/api.git
+`)
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Repos, 1)
+	assert.Equal(t, "git@github.com:me/api.git", cfg.Repos[0].URL)
+	assert.Equal(t, "git@github.com:org/api.git", cfg.Repos[0].Remotes["upstream"])
+}
+
+func TestSetValue_Remotes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".foundagent.yaml"), keysTestConfig)
+
+	require.NoError(t, SetValue(dir, "repos.api.remotes.upstream", "git@github.com:upstream/api.git"))
+	require.NoError(t, SetValue(dir, "repos.web.v2.remotes.mirror", "git@github.com:mirror/web.v2.git"))
+	require.NoError(t, SetValue(dir, "repos.api.push_remote", "upstream"))
+
+	value, err := GetValue(dir, "repos.api.remotes.upstream")
+	require.NoError(t, err)
+	assert.Equal(t, "git@github.com:upstream/api.git", value)
+
+	values, err := ListValues(dir)
+	require.NoError(t, err)
+	assert.Contains(t, values, KeyValue{Key: "repos.web.v2.remotes.mirror", Value: "git@github.com:mirror/web.v2.git"})
+
+	assert.Error(t, SetValue(dir, "repos.api.remotes.upstream", "not a url"))
+	assert.Error(t, SetValue(dir, "repos.api.remotes", "x"))
+
+	// The push remote still names upstream, so it cannot be removed yet
+	assert.Error(t, UnsetValue(dir, "repos.api.remotes.upstream"))
+	require.NoError(t, UnsetValue(dir, "repos.api.push_remote"))
+	require.NoError(t, UnsetValue(dir, "repos.api.remotes.upstream"))
+
+	cfg, err := LoadLocal(dir)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Repos[0].Remotes)
+	_, err = GetValue(dir, "repos.api.remotes.upstream")
+	assert.Error(t, err)
+}
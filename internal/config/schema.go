@@ -2,31 +2,111 @@ package config
 
 // Config represents the workspace configuration
 type Config struct {
-	Workspace WorkspaceConfig `yaml:"workspace" toml:"workspace" json:"workspace"`
-	Repos     []RepoConfig    `yaml:"repos" toml:"repos" json:"repos"`
-	Settings  SettingsConfig  `yaml:"settings" toml:"settings" json:"settings"`
+	Version   int             `yaml:"version" toml:"version" json:"version" description:"Config schema version, upgraded by 'fa migrate'"`
+	Workspace WorkspaceConfig `yaml:"workspace" toml:"workspace" json:"workspace" jsonschema:"required" description:"Workspace-level settings"`
+	Include   []string        `yaml:"include,omitempty" toml:"include,omitempty" json:"include,omitempty" description:"Shared config files to merge in, relative to this file"`
+	Repos     []RepoConfig    `yaml:"repos" toml:"repos" json:"repos" description:"Repositories in this workspace"`
+	Exclude   []string        `yaml:"exclude,omitempty" toml:"exclude,omitempty" json:"exclude,omitempty" description:"Names of included repos to leave out"`
+	Settings  SettingsConfig  `yaml:"settings" toml:"settings" json:"settings" description:"Workspace settings"`
+
+	// fileSettings holds the settings keys of the file the config was read
+	// from, so that saving it writes only those. Nil writes every setting.
+	fileSettings map[string]bool
 }
 
 // WorkspaceConfig represents workspace-level configuration
 type WorkspaceConfig struct {
-	Name string `yaml:"name" toml:"name" json:"name"`
+	Name     string   `yaml:"name" toml:"name" json:"name" jsonschema:"required" description:"Workspace name"`
+	Overlays Overlays `yaml:"overlays,omitempty" toml:"overlays,omitempty" json:"overlays,omitzero" description:"Untracked files placed into every new worktree, from .foundagent/overlays/"`
 }
 
 // RepoConfig represents a repository configuration entry
 type RepoConfig struct {
-	URL           string `yaml:"url" toml:"url" json:"url"`
-	Name          string `yaml:"name,omitempty" toml:"name,omitempty" json:"name,omitempty"`
-	DefaultBranch string `yaml:"default_branch,omitempty" toml:"default_branch,omitempty" json:"default_branch,omitempty"`
+	URL           string            `yaml:"url,omitempty" toml:"url,omitempty" json:"url,omitempty" description:"Git remote URL (the origin remote), e.g. git@github.com:org/repo.git or https://github.com/org/repo.git"`
+	Name          string            `yaml:"name,omitempty" toml:"name,omitempty" json:"name,omitempty" description:"Repository name, inferred from the URL if omitted"`
+	DefaultBranch string            `yaml:"default_branch,omitempty" toml:"default_branch,omitempty" json:"default_branch,omitempty" description:"Branch new worktrees start from, detected from the remote if omitted"`
+	Groups        []string          `yaml:"groups,omitempty" toml:"groups,omitempty" json:"groups,omitempty" pattern:"^[^\\s,]+$" description:"Groups this repo belongs to, selected with --group"`
+	Tags          []string          `yaml:"tags,omitempty" toml:"tags,omitempty" json:"tags,omitempty" pattern:"^[^\\s,]+$" description:"Tags for this repo, selected with --tag"`
+	Sparse        []string          `yaml:"sparse,omitempty" toml:"sparse,omitempty" json:"sparse,omitempty" description:"Directories to check out in new worktrees (cone-mode sparse checkout); omit to check out everything"`
+	CloneDepth    int               `yaml:"clone_depth,omitempty" toml:"clone_depth,omitempty" json:"clone_depth,omitempty" minimum:"0" description:"Clone and fetch only this many recent commits; omit for full history"`
+	Filter        string            `yaml:"filter,omitempty" toml:"filter,omitempty" json:"filter,omitempty" pattern:"^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+)$" description:"Partial clone filter, e.g. blob:none to fetch file contents on demand"`
+	SingleBranch  bool              `yaml:"single_branch,omitempty" toml:"single_branch,omitempty" json:"single_branch,omitempty" description:"Clone and fetch only the default branch"`
+	Submodules    string            `yaml:"submodules,omitempty" toml:"submodules,omitempty" json:"submodules,omitempty" enum:"recursive,none" description:"recursive initialises and updates submodules in new worktrees and on 'fa sync --pull'; none (the default) leaves them alone"`
+	Remotes       map[string]string `yaml:"remotes,omitempty" toml:"remotes,omitempty" json:"remotes,omitempty" pattern:"^[A-Za-z0-9][A-Za-z0-9._-]*$" description:"Git remotes by name, e.g. origin: <your fork> and upstream: <canonical repo>; origin defaults to url and 'fa sync' fetches them all"`
+	PushRemote    string            `yaml:"push_remote,omitempty" toml:"push_remote,omitempty" json:"push_remote,omitempty" description:"Remote 'fa push' pushes to; defaults to origin when remotes are set, otherwise to each branch's upstream"`
+	Overlays      Overlays          `yaml:"overlays,omitempty" toml:"overlays,omitempty" json:"overlays,omitzero" description:"Untracked files placed into this repo's new worktrees, from .foundagent/overlays/<name>/"`
+}
+
+// Overlays lists untracked files and directories, such as .env files or
+// editor settings, placed into new worktrees. Each entry is a path relative
+// to the overlay directory, and lands at the same path in the worktree.
+type Overlays struct {
+	Copy    []string `yaml:"copy,omitempty" toml:"copy,omitempty" json:"copy,omitempty" description:"Paths copied into new worktrees, so each worktree can change its own copy"`
+	Symlink []string `yaml:"symlink,omitempty" toml:"symlink,omitempty" json:"symlink,omitempty" description:"Paths symlinked into new worktrees, so every worktree shares one file"`
+}
+
+// IsEmpty reports whether no overlays are declared
+func (o Overlays) IsEmpty() bool {
+	return len(o.Copy) == 0 && len(o.Symlink) == 0
+}
+
+// Submodule modes for RepoConfig.Submodules
+const (
+	SubmodulesRecursive = "recursive"
+	SubmodulesNone      = "none"
+)
+
+// UpdatesSubmodules reports whether foundagent keeps the repo's submodules
+// checked out
+func (r RepoConfig) UpdatesSubmodules() bool {
+	return r.Submodules == SubmodulesRecursive
+}
+
+// DefaultRemote is the remote a repo is cloned from
+const DefaultRemote = "origin"
+
+// CloneURL returns the URL the repo is cloned from: url, or remotes.origin
+// when url is omitted
+func (r RepoConfig) CloneURL() string {
+	if r.URL != "" {
+		return r.URL
+	}
+	return r.Remotes[DefaultRemote]
+}
+
+// PushTarget returns the remote 'fa push' pushes to, or "" to push each
+// branch to its upstream as plain 'git push' does
+func (r RepoConfig) PushTarget() string {
+	if r.PushRemote != "" {
+		return r.PushRemote
+	}
+	if len(r.Remotes) > 0 {
+		return DefaultRemote
+	}
+	return ""
 }
 
 // SettingsConfig represents workspace settings
 type SettingsConfig struct {
-	AutoCreateWorktree bool `yaml:"auto_create_worktree" toml:"auto_create_worktree" json:"auto_create_worktree"`
+	AutoCreateWorktree bool           `yaml:"auto_create_worktree" toml:"auto_create_worktree" json:"auto_create_worktree" description:"Create a worktree for the default branch when adding a repo"`
+	Jobs               int            `yaml:"jobs,omitempty" toml:"jobs,omitempty" json:"jobs,omitzero" description:"Maximum number of repos to work on at once (0 = default of 8)"`
+	Timeouts           TimeoutsConfig `yaml:"timeouts,omitempty" toml:"timeouts,omitempty" json:"timeouts,omitzero" description:"How long git commands may run, as durations such as 90s or 10m"`
+	Retries            int            `yaml:"retries,omitempty" toml:"retries,omitempty" json:"retries,omitzero" description:"How many times to retry a clone, fetch or push that failed for a transient network reason (0 = default of 2, -1 = never)"`
+}
+
+// TimeoutsConfig limits how long each kind of git command may run. Empty
+// values keep the defaults.
+type TimeoutsConfig struct {
+	Clone string `yaml:"clone,omitempty" toml:"clone,omitempty" json:"clone,omitempty" description:"Timeout for git clone (default 30m)"`
+	Fetch string `yaml:"fetch,omitempty" toml:"fetch,omitempty" json:"fetch,omitempty" description:"Timeout for fetch, pull and other reads from a remote (default 10m)"`
+	Push  string `yaml:"push,omitempty" toml:"push,omitempty" json:"push,omitempty" description:"Timeout for git push (default 10m)"`
+	Local string `yaml:"local,omitempty" toml:"local,omitempty" json:"local,omitempty" description:"Timeout for git commands that do not contact a remote (default 5m)"`
 }
 
 // DefaultConfig returns a default configuration
 func DefaultConfig(workspaceName string) *Config {
 	return &Config{
+		Version: CurrentVersion,
 		Workspace: WorkspaceConfig{
 			Name: workspaceName,
 		},
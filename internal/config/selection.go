@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// Selector narrows an operation to a subset of the workspace repositories.
+// A repository matches when it is named explicitly or belongs to any of the
+// requested groups or tags. An empty selector matches every repository.
+type Selector struct {
+	Repos  []string
+	Groups []string
+	Tags   []string
+}
+
+// IsEmpty returns true if the selector does not filter anything
+func (s Selector) IsEmpty() bool {
+	return len(s.Repos) == 0 && len(s.Groups) == 0 && len(s.Tags) == 0
+}
+
+// Matches checks if a repository is selected
+func (s Selector) Matches(repo RepoConfig) bool {
+	if s.IsEmpty() {
+		return true
+	}
+	return containsAny(s.Repos, repo.Name) ||
+		containsAny(s.Groups, repo.Groups...) ||
+		containsAny(s.Tags, repo.Tags...)
+}
+
+// Validate checks that every requested repo, group and tag exists in the given repos
+func (s Selector) Validate(repos []RepoConfig) error {
+	names := make(map[string]bool)
+	groups := make(map[string]bool)
+	tags := make(map[string]bool)
+	for _, repo := range repos {
+		names[repo.Name] = true
+		for _, g := range repo.Groups {
+			groups[g] = true
+		}
+		for _, t := range repo.Tags {
+			tags[t] = true
+		}
+	}
+
+	for _, name := range s.Repos {
+		if !names[name] {
+			return errors.New(
+				errors.ErrCodeRepoNotFound,
+				fmt.Sprintf("Repository '%s' not found in workspace", name),
+				"Run 'fa status' to see available repositories",
+			)
+		}
+	}
+
+	for _, group := range s.Groups {
+		if !groups[group] {
+			return errors.New(
+				errors.ErrCodeInvalidInput,
+				fmt.Sprintf("No repositories in group '%s'", group),
+				fmt.Sprintf("Available groups: %s", joinKeys(groups)),
+			)
+		}
+	}
+
+	for _, tag := range s.Tags {
+		if !tags[tag] {
+			return errors.New(
+				errors.ErrCodeInvalidInput,
+				fmt.Sprintf("No repositories tagged '%s'", tag),
+				fmt.Sprintf("Available tags: %s", joinKeys(tags)),
+			)
+		}
+	}
+
+	return nil
+}
+
+// Filter validates the selector and returns the matching repos in config order
+func (s Selector) Filter(repos []RepoConfig) ([]RepoConfig, error) {
+	if s.IsEmpty() {
+		return repos, nil
+	}
+
+	if err := s.Validate(repos); err != nil {
+		return nil, err
+	}
+
+	selected := make([]RepoConfig, 0, len(repos))
+	for _, repo := range repos {
+		if s.Matches(repo) {
+			selected = append(selected, repo)
+		}
+	}
+	return selected, nil
+}
+
+// Groups returns all group names used in the configuration, sorted
+func Groups(config *Config) []string {
+	set := make(map[string]bool)
+	for _, repo := range config.Repos {
+		for _, g := range repo.Groups {
+			set[g] = true
+		}
+	}
+	return sortedKeys(set)
+}
+
+// Tags returns all tag names used in the configuration, sorted
+func Tags(config *Config) []string {
+	set := make(map[string]bool)
+	for _, repo := range config.Repos {
+		for _, t := range repo.Tags {
+			set[t] = true
+		}
+	}
+	return sortedKeys(set)
+}
+
+func containsAny(wanted []string, values ...string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if w == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinKeys(set map[string]bool) string {
+	keys := sortedKeys(set)
+	if len(keys) == 0 {
+		return "none"
+	}
+	return strings.Join(keys, ", ")
+}
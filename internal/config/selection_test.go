@@ -0,0 +1,117 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selectionTestRepos() []RepoConfig {
+	return []RepoConfig{
+		{Name: "api", URL: "https://github.com/org/api.git", Groups: []string{"backend"}, Tags: []string{"go"}},
+		{Name: "worker", URL: "https://github.com/org/worker.git", Groups: []string{"backend"}, Tags: []string{"go", "infra"}},
+		{Name: "web", URL: "https://github.com/org/web.git", Groups: []string{"frontend"}},
+		{Name: "docs", URL: "https://github.com/org/docs.git"},
+	}
+}
+
+func selectedNames(repos []RepoConfig) []string {
+	names := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = r.Name
+	}
+	return names
+}
+
+func TestSelector_Filter(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector Selector
+		expected []string
+	}{
+		{
+			name:     "empty selects all",
+			selector: Selector{},
+			expected: []string{"api", "worker", "web", "docs"},
+		},
+		{
+			name:     "by repo name",
+			selector: Selector{Repos: []string{"web", "docs"}},
+			expected: []string{"web", "docs"},
+		},
+		{
+			name:     "by group",
+			selector: Selector{Groups: []string{"backend"}},
+			expected: []string{"api", "worker"},
+		},
+		{
+			name:     "by tag",
+			selector: Selector{Tags: []string{"infra"}},
+			expected: []string{"worker"},
+		},
+		{
+			name:     "union of repo and group",
+			selector: Selector{Repos: []string{"docs"}, Groups: []string{"frontend"}},
+			expected: []string{"web", "docs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := tt.selector.Filter(selectionTestRepos())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, selectedNames(selected))
+		})
+	}
+}
+
+func TestSelector_FilterUnknown(t *testing.T) {
+	_, err := Selector{Repos: []string{"missing"}}.Filter(selectionTestRepos())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Repository 'missing' not found")
+
+	_, err = Selector{Groups: []string{"mobile"}}.Filter(selectionTestRepos())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No repositories in group 'mobile'")
+
+	_, err = Selector{Tags: []string{"rust"}}.Filter(selectionTestRepos())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No repositories tagged 'rust'")
+}
+
+func TestGroupsAndTags(t *testing.T) {
+	cfg := &Config{Repos: selectionTestRepos()}
+	assert.Equal(t, []string{"backend", "frontend"}, Groups(cfg))
+	assert.Equal(t, []string{"go", "infra"}, Tags(cfg))
+}
+
+func TestValidate_InvalidLabels(t *testing.T) {
+	cfg := DefaultConfig("test")
+	cfg.Repos = []RepoConfig{
+		{URL: "https://github.com/org/api.git", Name: "api", Groups: []string{"back end"}},
+	}
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repos[0].groups")
+
+	cfg.Repos[0].Groups = nil
+	cfg.Repos[0].Tags = []string{""}
+	err = Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repos[0].tags")
+}
+
+func TestLoadSaveYAML_GroupsAndTags(t *testing.T) {
+	path := t.TempDir() + "/.foundagent.yaml"
+	cfg := DefaultConfig("test")
+	cfg.Repos = []RepoConfig{
+		{URL: "https://github.com/org/api.git", Name: "api", Groups: []string{"backend"}, Tags: []string{"go"}},
+	}
+	require.NoError(t, SaveYAML(path, cfg))
+
+	loaded, err := LoadYAML(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend"}, loaded.Repos[0].Groups)
+	assert.Equal(t, []string{"go"}, loaded.Repos[0].Tags)
+}
@@ -7,49 +7,73 @@ func DefaultTemplate(workspaceName string) string {
 	return fmt.Sprintf(`# Foundagent Workspace Configuration
 # This file defines your multi-repository workspace
 
+# Config schema version, upgraded by 'fa migrate'
+version: %d
+
 workspace:
   # Workspace name
   name: %s
 
+# Shared config files to merge in, relative to this file. Entries here
+# override included ones by name; list names under exclude: to drop them.
+# include:
+#   - repos/platform/worktrees/main/team.foundagent.yaml
+
 # List of repositories in this workspace
 repos: []
   # Example repository entry:
   # - url: git@github.com:org/my-repo.git
   #   name: my-repo              # Optional: override inferred name
   #   default_branch: main       # Optional: override detected default branch
+  #   groups: [backend]          # Optional: select with --group backend
+  #   tags: [go]                 # Optional: select with --tag go
 
 # Workspace settings
 settings:
   # Automatically create a worktree for the default branch when adding a repo
   auto_create_worktree: true
-`, workspaceName)
+  # Maximum number of repos to work on at once; --jobs overrides it
+  # jobs: 8
+  # How long git commands may run before they are stopped
+  # timeouts:
+  #   clone: 30m
+  #   fetch: 10m
+  #   push: 10m
+  #   local: 5m
+  # How many times to retry a clone, fetch or push after a connection reset
+  # or server error; -1 never retries
+  # retries: 2
+`, CurrentVersion, workspaceName)
 }
 
-// AddRepo adds a repository to the configuration
+// AddRepo adds a repository to the configuration. Existing entries are
+// matched by name, explicit or inferred from the URL.
 func AddRepo(config *Config, url, name, defaultBranch string) {
-	repo := RepoConfig{
-		URL:           url,
-		Name:          name,
-		DefaultBranch: defaultBranch,
-	}
-
 	// Check if repo already exists
-	for i, r := range config.Repos {
-		if r.Name == name {
-			// Update existing entry
-			config.Repos[i] = repo
-			return
+	if r := GetRepo(config, name); r != nil {
+		// Update existing entry, keeping its other settings. An entry that
+		// gives its URL as remotes.origin keeps it there.
+		if _, ok := r.Remotes[DefaultRemote]; ok && r.URL == "" {
+			r.Remotes[DefaultRemote] = url
+		} else {
+			r.URL = url
 		}
+		r.DefaultBranch = defaultBranch
+		return
 	}
 
 	// Add new entry
-	config.Repos = append(config.Repos, repo)
+	config.Repos = append(config.Repos, RepoConfig{
+		URL:           url,
+		Name:          name,
+		DefaultBranch: defaultBranch,
+	})
 }
 
 // RemoveRepo removes a repository from the configuration
 func RemoveRepo(config *Config, name string) bool {
 	for i, r := range config.Repos {
-		if r.Name == name {
+		if repoKey(r) == name {
 			config.Repos = append(config.Repos[:i], config.Repos[i+1:]...)
 			return true
 		}
@@ -59,19 +83,15 @@ func RemoveRepo(config *Config, name string) bool {
 
 // HasRepo checks if a repository exists in the configuration
 func HasRepo(config *Config, name string) bool {
-	for _, r := range config.Repos {
-		if r.Name == name {
-			return true
-		}
-	}
-	return false
+	return GetRepo(config, name) != nil
 }
 
-// GetRepo retrieves a repository from the configuration
+// GetRepo retrieves a repository from the configuration by name, explicit or
+// inferred from the URL. Changes through the returned pointer update config.
 func GetRepo(config *Config, name string) *RepoConfig {
-	for _, r := range config.Repos {
-		if r.Name == name {
-			return &r
+	for i := range config.Repos {
+		if repoKey(config.Repos[i]) == name {
+			return &config.Repos[i]
 		}
 	}
 	return nil
@@ -187,3 +187,18 @@ func TestDefaultTemplate_ValidYAML(t *testing.T) {
 	assert.Contains(t, template, "name: test-ws")
 	assert.Contains(t, template, "auto_create_worktree: true")
 }
+
+func TestAddRepo_UpdateKeepsGroupsAndTags(t *testing.T) {
+	cfg := &Config{
+		Workspace: WorkspaceConfig{Name: "test"},
+		Repos: []RepoConfig{
+			{URL: "https://github.com/test/repo1.git", Name: "repo1", Groups: []string{"backend"}, Tags: []string{"go"}},
+		},
+	}
+
+	AddRepo(cfg, "https://github.com/test/repo1.git", "repo1", "main")
+
+	assert.Equal(t, []string{"backend"}, cfg.Repos[0].Groups)
+	assert.Equal(t, []string{"go"}, cfg.Repos[0].Tags)
+	assert.Equal(t, "main", cfg.Repos[0].DefaultBranch)
+}
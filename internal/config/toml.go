@@ -1,16 +1,28 @@
 package config
 
 import (
-	"os"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/errors"
+	"gopkg.in/yaml.v3"
 )
 
 // LoadTOML loads config from a TOML file
 func LoadTOML(path string) (*Config, error) {
 	var config Config
-	if _, err := toml.DecodeFile(path, &config); err != nil {
+	data, err := dryrun.ReadFile(path)
+	if err == nil {
+		_, err = toml.Decode(string(data), &config)
+	}
+	if err != nil {
 		return nil, errors.Wrap(
 			errors.ErrCodeInvalidConfig,
 			"Failed to parse TOML config",
@@ -24,26 +36,185 @@ func LoadTOML(path string) (*Config, error) {
 
 // SaveTOML saves config to a TOML file
 func SaveTOML(path string, config *Config) error {
-	f, err := os.Create(path)
+	var buf bytes.Buffer
+	node, err := encodable(config)
+	if err == nil {
+		err = writeTOMLTable(&buf, nil, node)
+	}
 	if err != nil {
 		return errors.Wrap(
-			errors.ErrCodePermissionDenied,
-			"Failed to create config file",
-			"Check file permissions",
+			errors.ErrCodeUnknown,
+			"Failed to encode config to TOML",
+			"This is an internal error",
 			err,
 		)
 	}
-	defer f.Close()
 
-	encoder := toml.NewEncoder(f)
-	if err := encoder.Encode(config); err != nil {
+	if err := atomicfile.WriteWithBackup(path, buf.Bytes(), 0644); err != nil {
 		return errors.Wrap(
-			errors.ErrCodeUnknown,
-			"Failed to encode config to TOML",
-			"This is an internal error",
+			errors.ErrCodePermissionDenied,
+			"Failed to create config file",
+			"Check file permissions",
 			err,
 		)
 	}
 
 	return nil
 }
+
+// writeTOMLTable writes the keys of a YAML mapping node as the TOML table at
+// path: its values first and then the tables nested in it, as TOML requires,
+// each in the order of the node
+func writeTOMLTable(buf *bytes.Buffer, path []string, node *yaml.Node) error {
+	indent := strings.Repeat("  ", len(path))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if isTOMLTable(value) {
+			continue
+		}
+		text, err := tomlValue(value)
+		if err != nil {
+			return err
+		}
+		if text != "" {
+			fmt.Fprintf(buf, "%s%s = %s\n", indent, tomlKey(key), text)
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if !isTOMLTable(value) {
+			continue
+		}
+		table := append(slices.Clone(path), key)
+		keys := make([]string, len(table))
+		for j, k := range table {
+			keys[j] = tomlKey(k)
+		}
+		name := strings.Join(keys, ".")
+
+		tables := value.Content
+		header := "%s[[%s]]\n"
+		if value.Kind == yaml.MappingNode {
+			tables = []*yaml.Node{value}
+			header = "%s[%s]\n"
+		}
+		for _, t := range tables {
+			if len(path) == 0 {
+				buf.WriteByte('\n')
+			}
+			fmt.Fprintf(buf, header, indent, name)
+			if err := writeTOMLTable(buf, table, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isTOMLTable reports whether a node is written as a TOML table or array of
+// tables rather than as a value
+func isTOMLTable(node *yaml.Node) bool {
+	if node.Kind == yaml.MappingNode {
+		return true
+	}
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return false
+	}
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlValue returns a scalar or a list of scalars as a TOML value, or "" for
+// a null, which TOML cannot express
+func tomlValue(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.SequenceNode {
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			text, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return tomlString(v), nil
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("cannot write %s as a TOML value", node.Tag)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// tomlKey returns key as written in TOML, quoted unless it is a bare key
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+// tomlString quotes s as a TOML basic string. JSON's escapes are all valid in
+// TOML.
+func tomlString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// tomlHasComments reports whether TOML source contains a comment. A # inside
+// a string, including a multi-line one, does not count.
+func tomlHasComments(data []byte) bool {
+	s := string(data)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '#':
+			return true
+		case strings.HasPrefix(s[i:], `"""`), strings.HasPrefix(s[i:], `'''`):
+			end := strings.Index(s[i+3:], s[i:i+3])
+			if end < 0 {
+				return false
+			}
+			i += 3 + end + 2
+		case s[i] == '"' || s[i] == '\'':
+			quote := s[i]
+			for i++; i < len(s) && s[i] != quote && s[i] != '\n'; i++ {
+				if quote == '"' && s[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return false
+}
+
+// commentedTOML is the error for rewriting a TOML config that has comments,
+// which the TOML encoder cannot keep
+func commentedTOML(path string) error {
+	return errors.New(
+		errors.ErrCodeInvalidOperation,
+		fmt.Sprintf("Cannot change %s without losing its comments", filepath.Base(path)),
+		"Edit the file by hand, remove its comments, or run 'fa config convert --to yaml' first",
+	)
+}
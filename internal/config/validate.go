@@ -2,13 +2,38 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/foundagent/foundagent/internal/errors"
 	"github.com/foundagent/foundagent/internal/git"
 )
 
-// Validate validates the configuration
+// labelPattern matches a valid group or tag label. It must match the pattern
+// tag on RepoConfig.Groups and RepoConfig.Tags, which feeds the JSON Schema.
+const labelPattern = `^[^\s,]+$`
+
+var labelRegexp = regexp.MustCompile(labelPattern)
+
+// filterPattern matches the partial clone filters foundagent accepts. It must
+// match the pattern tag on RepoConfig.Filter.
+const filterPattern = `^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+)$`
+
+var filterRegexp = regexp.MustCompile(filterPattern)
+
+// remotePattern matches a remote name foundagent accepts. It must match the
+// pattern tag on RepoConfig.Remotes.
+const remotePattern = `^[A-Za-z0-9][A-Za-z0-9._-]*$`
+
+var remoteRegexp = regexp.MustCompile(remotePattern)
+
+// Validate validates the configuration. Errors name the offending field with
+// the same paths as the JSON Schema and 'fa config', e.g. repos[0].url.
 func Validate(config *Config) error {
 	if config == nil {
 		return errors.New(
@@ -20,23 +45,65 @@ func Validate(config *Config) error {
 
 	// Validate workspace name
 	if strings.TrimSpace(config.Workspace.Name) == "" {
-		return errors.New(
-			errors.ErrCodeInvalidConfig,
-			"Workspace name cannot be empty",
+		return invalidField(
+			"workspace.name",
+			"workspace name cannot be empty",
 			"Set workspace.name in your config file",
 		)
 	}
 
+	if err := validateOverlays("workspace.overlays", config.Workspace.Overlays); err != nil {
+		return err
+	}
+
+	if config.Settings.Jobs < 0 {
+		return invalidField(
+			"settings.jobs",
+			fmt.Sprintf("jobs cannot be negative: %d", config.Settings.Jobs),
+			"Use a positive number of repos, or omit jobs for the default",
+		)
+	}
+
+	if err := validateTimeouts(config.Settings.Timeouts); err != nil {
+		return err
+	}
+
+	if config.Settings.Retries < -1 {
+		return invalidField(
+			"settings.retries",
+			fmt.Sprintf("retries cannot be less than -1: %d", config.Settings.Retries),
+			"Use a positive number of retries, -1 to never retry, or omit retries for the default",
+		)
+	}
+
 	// Validate repos
 	repoNames := make(map[string]bool)
 	repoURLs := make(map[string]string) // url -> first name that used it
 
 	for i, repo := range config.Repos {
+		path := fmt.Sprintf("repos[%d]", i)
+
+		// Validate remotes first, since remotes.origin can stand in for url
+		if err := validateRemotes(path, repo); err != nil {
+			return err
+		}
+		if repo.URL == "" && repo.CloneURL() != "" {
+			repo.URL = repo.CloneURL()
+			config.Repos[i].URL = repo.URL
+		}
+
 		// Validate URL format
+		if repo.URL == "" {
+			return invalidField(
+				path+".url",
+				"url is required",
+				"Add the repository's git URL, or remove the entry",
+			)
+		}
 		if err := git.ValidateURL(repo.URL); err != nil {
-			return errors.New(
-				errors.ErrCodeInvalidConfig,
-				fmt.Sprintf("Invalid URL in repos[%d]: %s", i, repo.URL),
+			return invalidField(
+				path+".url",
+				fmt.Sprintf("invalid URL: %s", repo.URL),
 				"Ensure URL is in format git@host:owner/repo.git or https://host/owner/repo.git",
 			)
 		}
@@ -46,9 +113,9 @@ func Validate(config *Config) error {
 		if name == "" {
 			inferredName, err := git.InferName(repo.URL)
 			if err != nil {
-				return errors.New(
-					errors.ErrCodeInvalidConfig,
-					fmt.Sprintf("Could not infer name for repos[%d]: %s", i, repo.URL),
+				return invalidField(
+					path+".name",
+					fmt.Sprintf("could not infer name from %s", repo.URL),
 					"Provide an explicit 'name' field for this repo",
 				)
 			}
@@ -57,11 +124,44 @@ func Validate(config *Config) error {
 			config.Repos[i].Name = name
 		}
 
+		// Validate group and tag labels
+		if err := validateLabels(path+".groups", repo.Groups); err != nil {
+			return err
+		}
+		if err := validateLabels(path+".tags", repo.Tags); err != nil {
+			return err
+		}
+
+		// Validate sparse checkout directories
+		if err := validateSparseDirs(path+".sparse", repo.Sparse); err != nil {
+			return err
+		}
+
+		if err := validateOverlays(path+".overlays", repo.Overlays); err != nil {
+			return err
+		}
+
+		// Validate clone options
+		if repo.CloneDepth < 0 {
+			return invalidField(
+				path+".clone_depth",
+				fmt.Sprintf("clone depth cannot be negative: %d", repo.CloneDepth),
+				"Use a positive number of commits, or omit clone_depth for full history",
+			)
+		}
+		if repo.Filter != "" && !ValidFilter(repo.Filter) {
+			return invalidField(
+				path+".filter",
+				fmt.Sprintf("unsupported clone filter %q", repo.Filter),
+				"Use blob:none, blob:limit=<size> or tree:<depth>",
+			)
+		}
+
 		// Check for duplicate names
 		if repoNames[name] {
-			return errors.New(
-				errors.ErrCodeInvalidConfig,
-				fmt.Sprintf("Duplicate repository name: %s", name),
+			return invalidField(
+				path+".name",
+				fmt.Sprintf("duplicate repository name: %s", name),
 				"Each repository must have a unique name",
 			)
 		}
@@ -76,5 +176,205 @@ func Validate(config *Config) error {
 		}
 	}
 
+	return validateEnums("", reflect.ValueOf(config).Elem())
+}
+
+// validateLabels checks that group or tag labels are non-empty single words
+func validateLabels(path string, labels []string) error {
+	for i, label := range labels {
+		if !labelRegexp.MatchString(label) {
+			return invalidField(
+				fmt.Sprintf("%s[%d]", path, i),
+				fmt.Sprintf("invalid label %q", label),
+				"Labels must be non-empty and cannot contain spaces or commas",
+			)
+		}
+	}
+	return nil
+}
+
+// validateRemotes checks a repo's remote names and URLs, and that its push
+// remote is one of them
+func validateRemotes(path string, repo RepoConfig) error {
+	for _, name := range slices.Sorted(maps.Keys(repo.Remotes)) {
+		url := repo.Remotes[name]
+		if !ValidRemoteName(name) {
+			return invalidField(
+				path+".remotes",
+				fmt.Sprintf("invalid remote name %q", name),
+				"Remote names start with a letter or digit and contain only letters, digits, '.', '_' and '-'",
+			)
+		}
+		if err := git.ValidateURL(url); err != nil {
+			return invalidField(
+				path+".remotes."+name,
+				fmt.Sprintf("invalid URL: %s", url),
+				"Ensure URL is in format git@host:owner/repo.git or https://host/owner/repo.git",
+			)
+		}
+	}
+
+	if origin, ok := repo.Remotes[DefaultRemote]; ok && repo.URL != "" && origin != repo.URL {
+		return invalidField(
+			path+".remotes."+DefaultRemote,
+			fmt.Sprintf("remotes.origin (%s) does not match url (%s)", origin, repo.URL),
+			"Set only one of them; url is the origin remote",
+		)
+	}
+
+	if repo.PushRemote != "" && repo.PushRemote != DefaultRemote {
+		if _, ok := repo.Remotes[repo.PushRemote]; !ok {
+			return invalidField(
+				path+".push_remote",
+				fmt.Sprintf("unknown remote %q", repo.PushRemote),
+				"Use origin or a remote listed under remotes",
+			)
+		}
+	}
+	return nil
+}
+
+// ValidRemoteName reports whether name can be used as a git remote name
+func ValidRemoteName(name string) bool {
+	return remoteRegexp.MatchString(name)
+}
+
+// ValidFilter reports whether filter is a supported partial clone filter
+func ValidFilter(filter string) bool {
+	return filterRegexp.MatchString(filter)
+}
+
+// ValidSparseDir reports whether dir can be used for cone-mode sparse
+// checkout: a directory relative to the repository root, without wildcards
+func ValidSparseDir(dir string) bool {
+	clean := filepath.ToSlash(filepath.Clean(dir))
+	return strings.TrimSpace(dir) != "" && !filepath.IsAbs(dir) && !strings.HasPrefix(dir, "/") &&
+		clean != "." && clean != ".." && !strings.HasPrefix(clean, "../") &&
+		!strings.ContainsAny(dir, "*?[!")
+}
+
+// validateSparseDirs checks every sparse checkout entry of a repo
+func validateSparseDirs(path string, dirs []string) error {
+	for i, dir := range dirs {
+		if !ValidSparseDir(dir) {
+			return invalidField(
+				fmt.Sprintf("%s[%d]", path, i),
+				fmt.Sprintf("invalid sparse directory %q", dir),
+				"Use directory paths relative to the repository root, without wildcards",
+			)
+		}
+	}
+	return nil
+}
+
+// ValidOverlayPath reports whether path can name an overlay: a relative path
+// that stays inside both the overlay directory and the worktree, and does not
+// reach into .git
+func ValidOverlayPath(path string) bool {
+	clean := filepath.ToSlash(filepath.Clean(path))
+	return strings.TrimSpace(path) != "" && !filepath.IsAbs(path) && !strings.HasPrefix(path, "/") &&
+		clean != "." && clean != ".." && !strings.HasPrefix(clean, "../") &&
+		clean != ".git" && !strings.HasPrefix(clean, ".git/")
+}
+
+// validateOverlays checks every copy and symlink overlay entry
+func validateOverlays(path string, overlays Overlays) error {
+	fields := []struct {
+		name  string
+		paths []string
+	}{{"copy", overlays.Copy}, {"symlink", overlays.Symlink}}
+
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		for i, p := range field.paths {
+			if !ValidOverlayPath(p) {
+				return invalidField(
+					fmt.Sprintf("%s.%s[%d]", path, field.name, i),
+					fmt.Sprintf("invalid overlay path %q", p),
+					"Use paths relative to the overlay directory, such as .env or .vscode/settings.json",
+				)
+			}
+			clean := filepath.Clean(p)
+			if seen[clean] {
+				return invalidField(
+					fmt.Sprintf("%s.%s[%d]", path, field.name, i),
+					fmt.Sprintf("overlay path %q is listed more than once", p),
+					"List each path under either copy or symlink, once",
+				)
+			}
+			seen[clean] = true
+		}
+	}
+	return nil
+}
+
+// validateEnums checks every field with an enum tag against its allowed values
+func validateEnums(path string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldPath := joinKey(path, yamlKey(field))
+
+			if enum := field.Tag.Get("enum"); enum != "" && field.Type.Kind() == reflect.String {
+				value := v.Field(i).String()
+				allowed := strings.Split(enum, ",")
+				if value != "" && !slices.Contains(allowed, value) {
+					return invalidField(
+						fieldPath,
+						fmt.Sprintf("invalid value %q", value),
+						fmt.Sprintf("Use one of: %s", strings.Join(allowed, ", ")),
+					)
+				}
+				continue
+			}
+
+			if err := validateEnums(fieldPath, v.Field(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Struct {
+			for i := 0; i < v.Len(); i++ {
+				if err := validateEnums(fmt.Sprintf("%s[%d]", path, i), v.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// invalidField returns a config error for the field at path
+func invalidField(path, problem, remediation string) error {
+	return errors.New(
+		errors.ErrCodeInvalidConfig,
+		fmt.Sprintf("Invalid config at %s: %s", path, problem),
+		remediation,
+	)
+}
+
+// validateTimeouts checks that each configured timeout is a positive duration
+func validateTimeouts(timeouts TimeoutsConfig) error {
+	fields := []struct{ key, value string }{
+		{"clone", timeouts.Clone},
+		{"fetch", timeouts.Fetch},
+		{"push", timeouts.Push},
+		{"local", timeouts.Local},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(f.value); err != nil || d <= 0 {
+			return invalidField(
+				"settings.timeouts."+f.key,
+				fmt.Sprintf("invalid timeout: %s", f.value),
+				"Use a positive duration such as 90s or 10m",
+			)
+		}
+	}
 	return nil
 }
@@ -1,16 +1,18 @@
 package config
 
 import (
-	"os"
+	"bytes"
 	"path/filepath"
 
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/errors"
 	"gopkg.in/yaml.v3"
 )
 
 // LoadYAML loads config from a YAML file
 func LoadYAML(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	data, err := dryrun.ReadFile(path)
 	if err != nil {
 		return nil, errors.Wrap(
 			errors.ErrCodeConfigNotFound,
@@ -33,35 +35,44 @@ func LoadYAML(path string) (*Config, error) {
 	return &config, nil
 }
 
-// SaveYAML saves config to a YAML file, preserving comments if possible
+// SaveYAML saves config to a YAML file. When the file already exists the new
+// values are merged into its document so comments and key order survive.
 func SaveYAML(path string, config *Config) error {
-	// First, try to load existing file to preserve comments
-	existingData, err := os.ReadFile(path)
-	if err == nil {
-		// Parse existing with yaml.v3 Node to preserve comments
-		var root yaml.Node
-		if err := yaml.Unmarshal(existingData, &root); err == nil {
-			// Update the values while preserving structure
-			newData, err := yaml.Marshal(config)
-			if err == nil {
-				// For now, just write new data - full comment preservation is complex
-				// TODO: Implement proper comment-preserving merge
-				if err := os.WriteFile(path, newData, 0644); err != nil {
-					return errors.Wrap(
-						errors.ErrCodePermissionDenied,
-						"Failed to write config file",
-						"Check file permissions",
-						err,
-					)
-				}
-				return nil
-			}
+	doc, err := encodable(config)
+	if err != nil {
+		return errors.Wrap(
+			errors.ErrCodeUnknown,
+			"Failed to marshal config to YAML",
+			"This is an internal error",
+			err,
+		)
+	}
+
+	flowScalarSequences(doc)
+
+	out := doc
+	if existingData, err := dryrun.ReadFile(path); err == nil {
+		var existing yaml.Node
+		if err := yaml.Unmarshal(existingData, &existing); err == nil &&
+			existing.Kind == yaml.DocumentNode && len(existing.Content) == 1 &&
+			existing.Content[0].Kind == yaml.MappingNode {
+			mergeYAMLNode(existing.Content[0], doc)
+			out = &existing
 		}
 	}
 
-	// New file or couldn't preserve - just marshal and write
-	data, err := yaml.Marshal(config)
-	if err != nil {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(out); err != nil {
+		return errors.Wrap(
+			errors.ErrCodeUnknown,
+			"Failed to marshal config to YAML",
+			"This is an internal error",
+			err,
+		)
+	}
+	if err := encoder.Close(); err != nil {
 		return errors.Wrap(
 			errors.ErrCodeUnknown,
 			"Failed to marshal config to YAML",
@@ -71,7 +82,7 @@ func SaveYAML(path string, config *Config) error {
 	}
 
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := dryrun.MkdirAll(dir, 0755); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to create config directory",
@@ -80,7 +91,7 @@ func SaveYAML(path string, config *Config) error {
 		)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(path, spaceTopLevelSections(buf.Bytes()), 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write config file",
@@ -91,3 +102,167 @@ func SaveYAML(path string, config *Config) error {
 
 	return nil
 }
+
+// mergeYAMLNode updates dst in place to hold the values of src while keeping
+// dst's comments, key order and styles wherever the two still line up
+func mergeYAMLNode(dst, src *yaml.Node) {
+	if dst.Kind != src.Kind {
+		head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+		*dst = *src
+		dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+		return
+	}
+
+	switch dst.Kind {
+	case yaml.MappingNode:
+		mergeYAMLMapping(dst, src)
+	case yaml.SequenceNode:
+		mergeYAMLSequence(dst, src)
+	case yaml.ScalarNode:
+		if dst.Value != src.Value || dst.Tag != src.Tag {
+			dst.Value = src.Value
+			dst.Tag = src.Tag
+			dst.Style = src.Style
+		}
+	}
+}
+
+// mergeYAMLMapping keeps existing keys in place, drops keys src no longer has
+// and inserts new keys after the key that precedes them in src
+func mergeYAMLMapping(dst, src *yaml.Node) {
+	existing := make(map[string]int)
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		existing[dst.Content[i].Value] = i
+	}
+	wanted := make(map[string]bool)
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		wanted[src.Content[i].Value] = true
+	}
+
+	merged := make([]*yaml.Node, 0, len(src.Content))
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		if wanted[dst.Content[i].Value] {
+			merged = append(merged, dst.Content[i], dst.Content[i+1])
+		}
+	}
+
+	position := func(key string) int {
+		for i := 0; i < len(merged); i += 2 {
+			if merged[i].Value == key {
+				return i
+			}
+		}
+		return -1
+	}
+
+	previous := ""
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		if j, ok := existing[key.Value]; ok {
+			mergeYAMLNode(dst.Content[j+1], value)
+		} else {
+			at := 0
+			if previous != "" {
+				at = position(previous) + 2
+			}
+			merged = append(merged[:at], append([]*yaml.Node{key, value}, merged[at:]...)...)
+		}
+		previous = key.Value
+	}
+
+	dst.Content = merged
+}
+
+// mergeYAMLSequence matches mapping items by their name or url so comments
+// follow the entry they describe, and scalar items by position
+func mergeYAMLSequence(dst, src *yaml.Node) {
+	if len(dst.Content) == 0 && len(src.Content) > 0 {
+		if dst.Style == yaml.FlowStyle && src.Content[0].Kind != yaml.ScalarNode {
+			dst.Style = src.Style
+		}
+	}
+
+	byIdentity := make(map[string]*yaml.Node)
+	for _, item := range dst.Content {
+		if id := yamlItemIdentity(item); id != "" {
+			byIdentity[id] = item
+		}
+	}
+
+	merged := make([]*yaml.Node, 0, len(src.Content))
+	for i, item := range src.Content {
+		var target *yaml.Node
+		if id := yamlItemIdentity(item); id != "" {
+			target = byIdentity[id]
+		} else if i < len(dst.Content) && item.Kind == yaml.ScalarNode {
+			target = dst.Content[i]
+		}
+
+		if target == nil {
+			merged = append(merged, item)
+			continue
+		}
+		mergeYAMLNode(target, item)
+		merged = append(merged, target)
+	}
+
+	dst.Content = merged
+}
+
+// yamlItemIdentity returns the name (or url) of a mapping item in a sequence
+func yamlItemIdentity(node *yaml.Node) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	url := ""
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "name":
+			return "name:" + node.Content[i+1].Value
+		case "url":
+			url = "url:" + node.Content[i+1].Value
+		}
+	}
+	return url
+}
+
+// spaceTopLevelSections restores the blank line between top-level sections,
+// which the YAML encoder does not keep. A section starts at a top-level
+// comment block or, when there is none, at its key.
+func spaceTopLevelSections(data []byte) []byte {
+	lines := bytes.Split(data, []byte("\n"))
+	out := make([][]byte, 0, len(lines))
+	for i, line := range lines {
+		if i > 0 && len(line) > 0 && !isIndented(line) {
+			prev := lines[i-1]
+			if len(bytes.TrimSpace(prev)) > 0 && !bytes.HasPrefix(prev, []byte("#")) {
+				out = append(out, nil)
+			}
+		}
+		out = append(out, line)
+	}
+	return bytes.Join(out, []byte("\n"))
+}
+
+func isIndented(line []byte) bool {
+	return line[0] == ' ' || line[0] == '\t' || line[0] == '-'
+}
+
+// flowScalarSequences renders lists of scalars inline, e.g. groups: [backend]
+func flowScalarSequences(node *yaml.Node) {
+	if node.Kind == yaml.SequenceNode && len(node.Content) > 0 {
+		scalars := true
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				scalars = false
+				break
+			}
+		}
+		if scalars {
+			node.Style = yaml.FlowStyle
+		}
+	}
+	for _, child := range node.Content {
+		flowScalarSequences(child)
+	}
+}
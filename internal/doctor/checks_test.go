@@ -189,6 +189,31 @@ func TestStateValidCheck_Run_InvalidState(t *testing.T) {
 	}
 }
 
+func TestSchemaVersionCheck_Run_Current(t *testing.T) {
+	ws := setupTestWorkspace(t)
+
+	check := SchemaVersionCheck{Workspace: ws}
+	result := check.Run()
+
+	if result.Status != StatusPass {
+		t.Errorf("Status = %v, want StatusPass for new workspace: %s", result.Status, result.Message)
+	}
+}
+
+func TestSchemaVersionCheck_Run_Unversioned(t *testing.T) {
+	ws := setupTestWorkspace(t)
+
+	// State written before versioning
+	os.WriteFile(ws.StatePath(), []byte("{}"), 0644)
+
+	check := SchemaVersionCheck{Workspace: ws}
+	result := check.Run()
+
+	if result.Status != StatusWarn {
+		t.Errorf("Status = %v, want StatusWarn for unversioned state", result.Status)
+	}
+}
+
 func TestRepositoriesCheck_Run_NoRepos(t *testing.T) {
 	ws := setupTestWorkspace(t)
 
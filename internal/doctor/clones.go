@@ -0,0 +1,107 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/workspace"
+)
+
+// CloneOptionsCheck reports shallow and partial clones, and repos whose clone
+// does not match the clone_depth or filter in the config
+type CloneOptionsCheck struct {
+	Workspace *workspace.Workspace
+}
+
+func (c CloneOptionsCheck) Name() string {
+	return "Shallow and partial clones"
+}
+
+func (c CloneOptionsCheck) Run() CheckResult {
+	state, err := c.Workspace.LoadState()
+	if err != nil {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusFail,
+			Message:     "Could not load state file",
+			Remediation: "Run 'fa doctor --fix' to regenerate state file",
+			Fixable:     true,
+		}
+	}
+
+	// Config problems are reported by the config check
+	repoConfigs := make(map[string]config.RepoConfig)
+	if cfg, err := config.Load(c.Workspace.Path); err == nil {
+		for _, repo := range cfg.Repos {
+			repoConfigs[repo.Name] = repo
+		}
+	}
+
+	names := make([]string, 0, len(state.Repositories))
+	for name := range state.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reduced := make([]string, 0)
+	mismatched := make([]string, 0)
+	for _, name := range names {
+		bareRepoPath := c.Workspace.BareRepoPath(name)
+		if _, err := os.Stat(bareRepoPath); err != nil {
+			// Missing clones are reported by the repository check
+			continue
+		}
+
+		shallow := git.IsShallow(bareRepoPath)
+		filter := git.PartialCloneFilter(bareRepoPath)
+
+		var kinds []string
+		if shallow {
+			kinds = append(kinds, "shallow")
+		}
+		if filter != "" {
+			kinds = append(kinds, "partial: "+filter)
+		}
+		if len(kinds) > 0 {
+			reduced = append(reduced, fmt.Sprintf("%s (%s)", name, strings.Join(kinds, ", ")))
+		}
+
+		repoConfig := repoConfigs[name]
+		if repoConfig.CloneDepth > 0 && !shallow {
+			mismatched = append(mismatched, fmt.Sprintf("%s has full history but sets clone_depth", name))
+		}
+		if repoConfig.Filter != "" && filter == "" {
+			mismatched = append(mismatched, fmt.Sprintf("%s is not a partial clone but sets filter", name))
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusWarn,
+			Message:     strings.Join(mismatched, "; "),
+			Remediation: "Re-clone with 'fa add <url> --force' to apply clone_depth and filter",
+			Fixable:     false,
+		}
+	}
+
+	if len(reduced) == 0 {
+		return CheckResult{
+			Name:    c.Name(),
+			Status:  StatusPass,
+			Message: "All repositories are full clones",
+			Fixable: false,
+		}
+	}
+
+	return CheckResult{
+		Name:    c.Name(),
+		Status:  StatusPass,
+		Message: strings.Join(reduced, ", "),
+		Fixable: false,
+	}
+}
@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCloneWorkspace creates a workspace and a source repo with two commits
+func setupCloneWorkspace(t *testing.T) (*workspace.Workspace, string) {
+	t.Helper()
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	src := filepath.Join(t.TempDir(), "src")
+	for _, args := range [][]string{
+		{"init", "-b", "main", src},
+		{"-C", src, "config", "user.email", "test@example.com"},
+		{"-C", src, "config", "user.name", "Test User"},
+		{"-C", src, "commit", "--allow-empty", "-m", "first"},
+		{"-C", src, "commit", "--allow-empty", "-m", "second"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return ws, src
+}
+
+// addClone bare-clones src into the workspace as name with extra clone args
+func addClone(t *testing.T, ws *workspace.Workspace, src, name string, args ...string) {
+	t.Helper()
+	cloneArgs := append([]string{"clone", "--bare", "--quiet"}, args...)
+	cloneArgs = append(cloneArgs, "file://"+src, ws.BareRepoPath(name))
+	out, err := exec.Command("git", cloneArgs...).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	require.NoError(t, ws.AddRepository(&workspace.Repository{
+		Name:          name,
+		URL:           "https://github.com/org/" + name + ".git",
+		DefaultBranch: "main",
+		BareRepoPath:  ws.BareRepoPath(name),
+	}))
+}
+
+func TestCloneOptionsCheck_FullClones(t *testing.T) {
+	ws, src := setupCloneWorkspace(t)
+	addClone(t, ws, src, "api")
+
+	result := CloneOptionsCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "All repositories are full clones", result.Message)
+}
+
+func TestCloneOptionsCheck_ReportsShallowAndPartial(t *testing.T) {
+	ws, src := setupCloneWorkspace(t)
+	addClone(t, ws, src, "api")
+	addClone(t, ws, src, "mono", "--depth=1", "--filter=blob:none")
+
+	result := CloneOptionsCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "mono (shallow, partial: blob:none)", result.Message)
+}
+
+func TestCloneOptionsCheck_ConfigMismatch(t *testing.T) {
+	ws, src := setupCloneWorkspace(t)
+	addClone(t, ws, src, "api")
+
+	cfg, err := config.LoadLocal(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = []config.RepoConfig{{URL: "https://github.com/org/api.git", Name: "api", CloneDepth: 1}}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	result := CloneOptionsCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Contains(t, result.Message, "api has full history but sets clone_depth")
+	assert.Contains(t, result.Remediation, "fa add <url> --force")
+}
+
+func TestCloneOptionsCheck_SkipsMissingClone(t *testing.T) {
+	ws, _ := setupCloneWorkspace(t)
+	require.NoError(t, ws.AddRepository(&workspace.Repository{Name: "gone", URL: "https://github.com/org/gone.git"}))
+	require.NoError(t, os.RemoveAll(ws.BareRepoPath("gone")))
+
+	result := CloneOptionsCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+}
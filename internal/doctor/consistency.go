@@ -25,7 +25,7 @@ func (c ConfigStateConsistencyCheck) Run() CheckResult {
 			Name:        c.Name(),
 			Status:      StatusFail,
 			Message:     "Could not load config file",
-			Remediation: "Check .foundagent.yaml syntax",
+			Remediation: "Check the config file syntax",
 			Fixable:     false,
 		}
 	}
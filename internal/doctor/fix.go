@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/workspace"
 )
 
@@ -52,7 +53,7 @@ func (f *Fixer) fixStateFile() CheckResult {
 			Name:        "State file valid",
 			Status:      StatusFail,
 			Message:     "Cannot regenerate state: config file invalid",
-			Remediation: "Check .foundagent.yaml syntax",
+			Remediation: "Check the config file syntax",
 			Fixable:     false,
 		}
 	}
@@ -112,7 +113,7 @@ func (f *Fixer) fixWorkspaceStructure() CheckResult {
 	// Note: Individual repo directories (repos/<repo-name>/.bare/ and repos/<repo-name>/worktrees/)
 	// are created when repos are added
 	reposPath := filepath.Join(f.Workspace.Path, workspace.ReposDir)
-	if err := os.MkdirAll(reposPath, 0755); err != nil {
+	if err := dryrun.MkdirAll(reposPath, 0755); err != nil {
 		return CheckResult{
 			Name:        "Workspace structure",
 			Status:      StatusFail,
@@ -171,7 +172,7 @@ func (f *Fixer) fixOrphanedRepos() CheckResult {
 		repoName := entry.Name()
 		if !knownRepos[repoName] {
 			path := filepath.Join(reposDir, repoName)
-			if err := os.RemoveAll(path); err == nil {
+			if err := dryrun.RemoveAll(path); err == nil {
 				removed++
 			}
 		}
@@ -223,7 +224,7 @@ func (f *Fixer) fixOrphanedWorktrees() CheckResult {
 			wtName := entry.Name()
 			if !knownRepoWorktrees[repoName][wtName] {
 				path := filepath.Join(worktreesDir, wtName)
-				if err := os.RemoveAll(path); err == nil {
+				if err := dryrun.RemoveAll(path); err == nil {
 					removed++
 				}
 			}
@@ -246,7 +247,7 @@ func (f *Fixer) fixConfigStateConsistency() CheckResult {
 			Name:        "Config/state consistency",
 			Status:      StatusFail,
 			Message:     "Cannot load config file",
-			Remediation: "Check .foundagent.yaml syntax",
+			Remediation: "Check the config file syntax",
 			Fixable:     false,
 		}
 	}
@@ -1,8 +1,13 @@
 package doctor
 
 import (
+	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
+
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/workspace"
 )
 
 // GitCheck checks if Git is installed
@@ -40,8 +45,7 @@ func (c GitVersionCheck) Name() string {
 }
 
 func (c GitVersionCheck) Run() CheckResult {
-	cmd := exec.Command("git", "--version")
-	output, err := cmd.Output()
+	version, err := git.Version()
 	if err != nil {
 		return CheckResult{
 			Name:        c.Name(),
@@ -52,8 +56,6 @@ func (c GitVersionCheck) Run() CheckResult {
 		}
 	}
 
-	version := strings.TrimSpace(string(output))
-
 	return CheckResult{
 		Name:    c.Name(),
 		Status:  StatusPass,
@@ -61,3 +63,63 @@ func (c GitVersionCheck) Run() CheckResult {
 		Fixable: false,
 	}
 }
+
+// GitLFSCheck checks that git lfs is installed when a repo uses Git LFS
+type GitLFSCheck struct {
+	Workspace *workspace.Workspace
+}
+
+func (c GitLFSCheck) Name() string {
+	return "Git LFS"
+}
+
+func (c GitLFSCheck) Run() CheckResult {
+	allWorktrees, err := c.Workspace.GetAllWorktrees()
+	if err != nil {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusFail,
+			Message:     "Could not list worktrees",
+			Remediation: "Run 'fa doctor --fix' to repair the workspace",
+			Fixable:     false,
+		}
+	}
+
+	// A repo uses LFS if any of its worktrees routes files through LFS
+	lfsRepos := make([]string, 0)
+	for repoName, branches := range allWorktrees {
+		for _, branch := range branches {
+			if git.UsesLFS(c.Workspace.WorktreePath(repoName, branch)) {
+				lfsRepos = append(lfsRepos, repoName)
+				break
+			}
+		}
+	}
+	sort.Strings(lfsRepos)
+
+	if len(lfsRepos) == 0 {
+		return CheckResult{
+			Name:    c.Name(),
+			Status:  StatusPass,
+			Message: "No repositories use Git LFS",
+			Fixable: false,
+		}
+	}
+
+	if !git.LFSInstalled() {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusWarn,
+			Message:     fmt.Sprintf("git lfs is not installed but needed by %s", strings.Join(lfsRepos, ", ")),
+			Remediation: "Install Git LFS from https://git-lfs.com, then run 'fa sync --pull' to replace pointer files",
+			Fixable:     false,
+		}
+	}
+
+	return CheckResult{
+		Name:    c.Name(),
+		Status:  StatusPass,
+		Message: fmt.Sprintf("git lfs is installed for %s", strings.Join(lfsRepos, ", ")),
+		Fixable: false,
+	}
+}
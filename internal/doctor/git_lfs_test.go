@@ -0,0 +1,68 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLFSWorkspace creates a workspace with an api worktree that uses LFS and
+// a web worktree that does not. git lfs is stubbed out when installed is true.
+func setupLFSWorkspace(t *testing.T, installed bool) *workspace.Workspace {
+	t.Helper()
+	ws, err := workspace.New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	for _, repo := range []string{"api", "web"} {
+		require.NoError(t, os.MkdirAll(ws.WorktreePath(repo, "main"), 0755))
+	}
+	attributes := filepath.Join(ws.WorktreePath("api", "main"), ".gitattributes")
+	require.NoError(t, os.WriteFile(attributes, []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644))
+
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+	bin := t.TempDir()
+	require.NoError(t, os.Symlink(gitPath, filepath.Join(bin, "git")))
+	if installed {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "git-lfs"), []byte("#!/bin/sh\nexit 0\n"), 0755))
+	}
+	t.Setenv("PATH", bin)
+
+	return ws
+}
+
+func TestGitLFSCheck_NotInstalled(t *testing.T) {
+	ws := setupLFSWorkspace(t, false)
+
+	result := GitLFSCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, "Git LFS", result.Name)
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, "git lfs is not installed but needed by api", result.Message)
+	assert.Contains(t, result.Remediation, "https://git-lfs.com")
+}
+
+func TestGitLFSCheck_Installed(t *testing.T) {
+	ws := setupLFSWorkspace(t, true)
+
+	result := GitLFSCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "git lfs is installed for api", result.Message)
+}
+
+func TestGitLFSCheck_NoLFSRepos(t *testing.T) {
+	ws := setupLFSWorkspace(t, false)
+	require.NoError(t, os.Remove(filepath.Join(ws.WorktreePath("api", "main"), ".gitattributes")))
+
+	result := GitLFSCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "No repositories use Git LFS", result.Message)
+}
@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -17,13 +18,13 @@ func (c WorkspaceStructureCheck) Name() string {
 }
 
 func (c WorkspaceStructureCheck) Run() CheckResult {
-	// Check .foundagent.yaml
+	// Check the config file, in any supported format
 	configPath := c.Workspace.ConfigPath()
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return CheckResult{
 			Name:        c.Name(),
 			Status:      StatusFail,
-			Message:     ".foundagent.yaml not found",
+			Message:     "Config file not found (.foundagent.yaml, .toml or .json)",
 			Remediation: "Run 'fa init' to initialize the workspace",
 			Fixable:     false,
 		}
@@ -92,7 +93,7 @@ func (c ConfigValidCheck) Run() CheckResult {
 			Name:        c.Name(),
 			Status:      StatusFail,
 			Message:     "Config file is invalid or corrupted",
-			Remediation: "Check .foundagent.yaml syntax or run 'fa init --force'",
+			Remediation: "Check the config file syntax or run 'fa init --force'",
 			Fixable:     false,
 		}
 	}
@@ -133,3 +134,42 @@ func (c StateValidCheck) Run() CheckResult {
 		Fixable: false,
 	}
 }
+
+// SchemaVersionCheck checks if the workspace needs 'fa migrate'
+type SchemaVersionCheck struct {
+	Workspace *workspace.Workspace
+}
+
+func (c SchemaVersionCheck) Name() string {
+	return "Schema version"
+}
+
+func (c SchemaVersionCheck) Run() CheckResult {
+	plan, err := c.Workspace.PlanMigration()
+	if err != nil {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusFail,
+			Message:     fmt.Sprintf("Cannot determine schema version: %v", err),
+			Remediation: "Upgrade foundagent or check the config and state files",
+			Fixable:     false,
+		}
+	}
+
+	if !plan.IsEmpty() {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusWarn,
+			Message:     fmt.Sprintf("Workspace needs migration (config v%d → v%d, state v%d → v%d)", plan.ConfigFrom, plan.ConfigTo, plan.StateFrom, plan.StateTo),
+			Remediation: "Run 'fa migrate' to upgrade the workspace",
+			Fixable:     false,
+		}
+	}
+
+	return CheckResult{
+		Name:    c.Name(),
+		Status:  StatusPass,
+		Message: fmt.Sprintf("Config v%d, state v%d", plan.ConfigTo, plan.StateTo),
+		Fixable: false,
+	}
+}
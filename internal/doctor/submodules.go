@@ -0,0 +1,100 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/workspace"
+)
+
+// SubmodulesCheck checks that worktrees of repos with submodules: recursive
+// have their submodules checked out at the recorded commits
+type SubmodulesCheck struct {
+	Workspace *workspace.Workspace
+}
+
+func (c SubmodulesCheck) Name() string {
+	return "Submodules"
+}
+
+func (c SubmodulesCheck) Run() CheckResult {
+	cfg, err := config.Load(c.Workspace.Path)
+	if err != nil {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusFail,
+			Message:     "Could not load config file",
+			Remediation: "Check the config file syntax",
+			Fixable:     false,
+		}
+	}
+
+	allWorktrees, err := c.Workspace.GetAllWorktrees()
+	if err != nil {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusFail,
+			Message:     "Could not list worktrees",
+			Remediation: "Run 'fa doctor --fix' to repair the workspace",
+			Fixable:     false,
+		}
+	}
+
+	checked := 0
+	outOfSync := make([]string, 0)
+	for _, repo := range cfg.Repos {
+		if !repo.UpdatesSubmodules() {
+			continue
+		}
+
+		branches := allWorktrees[repo.Name]
+		sort.Strings(branches)
+		for _, branch := range branches {
+			worktreePath := c.Workspace.WorktreePath(repo.Name, branch)
+			if !git.HasSubmodules(worktreePath) {
+				continue
+			}
+			checked++
+
+			statuses, err := git.SubmoduleStatuses(worktreePath)
+			if err != nil {
+				outOfSync = append(outOfSync, fmt.Sprintf("%s/%s (status unavailable)", repo.Name, branch))
+				continue
+			}
+			for _, status := range statuses {
+				if !status.InSync() {
+					outOfSync = append(outOfSync, fmt.Sprintf("%s/%s: %s %s", repo.Name, branch, status.Path, status.State))
+				}
+			}
+		}
+	}
+
+	if len(outOfSync) > 0 {
+		return CheckResult{
+			Name:        c.Name(),
+			Status:      StatusWarn,
+			Message:     fmt.Sprintf("%d submodule(s) out of sync: %s", len(outOfSync), strings.Join(outOfSync, ", ")),
+			Remediation: "Run 'git submodule update --init --recursive' in each worktree listed",
+			Fixable:     false,
+		}
+	}
+
+	if checked == 0 {
+		return CheckResult{
+			Name:    c.Name(),
+			Status:  StatusPass,
+			Message: "No worktrees with managed submodules",
+			Fixable: false,
+		}
+	}
+
+	return CheckResult{
+		Name:    c.Name(),
+		Status:  StatusPass,
+		Message: fmt.Sprintf("Submodules in sync in %d worktree(s)", checked),
+		Fixable: false,
+	}
+}
@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSubmoduleWorkspace creates a workspace with an app repo whose main
+// worktree has an uninitialised submodule at lib
+func setupSubmoduleWorkspace(t *testing.T, mode string) *workspace.Workspace {
+	t.Helper()
+
+	// Local submodule URLs need the file protocol
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	ws, lib := setupCloneWorkspace(t)
+	app := filepath.Join(t.TempDir(), "app")
+	for _, args := range [][]string{
+		{"init", "-b", "main", app},
+		{"-C", app, "submodule", "add", lib, "lib"},
+		{"-C", app, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "Add lib"},
+		{"clone", "--bare", "--quiet", app, ws.BareRepoPath("app")},
+		{"--git-dir=" + ws.BareRepoPath("app"), "worktree", "add", "--quiet", ws.WorktreePath("app", "main"), "main"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	require.NoError(t, ws.AddRepository(&workspace.Repository{Name: "app", URL: "https://github.com/org/app.git", DefaultBranch: "main"}))
+
+	cfg, err := config.LoadLocal(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = []config.RepoConfig{{URL: "https://github.com/org/app.git", Name: "app", Submodules: mode}}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	return ws
+}
+
+func TestSubmodulesCheck_OutOfSync(t *testing.T) {
+	ws := setupSubmoduleWorkspace(t, config.SubmodulesRecursive)
+
+	result := SubmodulesCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Contains(t, result.Message, "app/main: lib uninitialized")
+	assert.Contains(t, result.Remediation, "git submodule update --init --recursive")
+}
+
+func TestSubmodulesCheck_InSync(t *testing.T) {
+	ws := setupSubmoduleWorkspace(t, config.SubmodulesRecursive)
+	require.NoError(t, ws.SetupWorktree("app", ws.WorktreePath("app", "main")))
+
+	result := SubmodulesCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "Submodules in sync in 1 worktree(s)", result.Message)
+}
+
+func TestSubmodulesCheck_NotManaged(t *testing.T) {
+	ws := setupSubmoduleWorkspace(t, config.SubmodulesNone)
+
+	result := SubmodulesCheck{Workspace: ws}.Run()
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "No worktrees with managed submodules", result.Message)
+}
@@ -0,0 +1,86 @@
+package dryrun
+
+import "strings"
+
+// diffContext is how many unchanged lines are shown around each change
+const diffContext = 2
+
+// Diff compares two versions of a file line by line. Removed lines start
+// with "-", added lines with "+" and unchanged lines around them with " ";
+// "..." stands for unchanged lines left out.
+func Diff(old, new string) string {
+	a := splitLines(old)
+	b := splitLines(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, line{'+', b[j]})
+			j++
+		default:
+			lines = append(lines, line{'-', a[i]})
+			i++
+		}
+	}
+
+	// Keep the changed lines and the context around them
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	skipped := false
+	for k, l := range lines {
+		if !keep[k] {
+			skipped = true
+			continue
+		}
+		if skipped && sb.Len() > 0 {
+			sb.WriteString("...\n")
+		}
+		skipped = false
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
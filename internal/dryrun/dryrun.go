@@ -0,0 +1,150 @@
+// Package dryrun records the changes a command would make instead of making
+// them. While a dry run is on, the places that change the workspace, such as
+// atomicfile and the git executor, add a Step to the plan and leave the disk
+// alone. Files "written" during the dry run read back with their new content,
+// so later steps plan against the changes earlier ones would have made.
+package dryrun
+
+import (
+	"os"
+	"slices"
+	"sync"
+)
+
+// Step actions
+const (
+	ActionRun     = "run"     // Run a command, such as git
+	ActionMkdir   = "mkdir"   // Create a directory
+	ActionRemove  = "remove"  // Delete a file or directory tree
+	ActionCreate  = "create"  // Write a file that does not exist
+	ActionEdit    = "edit"    // Change a file
+	ActionCopy    = "copy"    // Copy Source to Path
+	ActionSymlink = "symlink" // Link Path to Source
+)
+
+// Step is one change in a plan
+type Step struct {
+	Action string   `json:"action"`
+	Argv   []string `json:"argv,omitempty"`   // Command of a run
+	Dir    string   `json:"dir,omitempty"`    // Directory a command runs in
+	Path   string   `json:"path,omitempty"`   // File or directory changed
+	Source string   `json:"source,omitempty"` // Source of a copy or symlink
+	Diff   string   `json:"diff,omitempty"`   // Lines a create or edit changes
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	steps   []Step
+	files   map[string][]byte // Content of the files written so far
+)
+
+// Start begins a dry run with an empty plan
+func Start() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	steps = nil
+	files = make(map[string][]byte)
+}
+
+// Stop ends the dry run and discards its plan
+func Stop() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = false
+	steps = nil
+	files = nil
+}
+
+// Enabled reports whether a dry run is on
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Steps returns the plan so far, in the order the changes would be made
+func Steps() []Step {
+	mu.Lock()
+	defer mu.Unlock()
+	return slices.Clone(steps)
+}
+
+func add(step Step) {
+	mu.Lock()
+	defer mu.Unlock()
+	steps = append(steps, step)
+}
+
+// Run records a command that would be run in dir
+func Run(argv []string, dir string) {
+	add(Step{Action: ActionRun, Argv: slices.Clone(argv), Dir: dir})
+}
+
+// MkdirAll is os.MkdirAll, except that a dry run records the directory if it
+// does not exist yet
+func MkdirAll(path string, perm os.FileMode) error {
+	if !Enabled() {
+		return os.MkdirAll(path, perm)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil
+	}
+	add(Step{Action: ActionMkdir, Path: path})
+	return nil
+}
+
+// RemoveAll is os.RemoveAll, except that a dry run records the path if
+// there is anything there to remove
+func RemoveAll(path string) error {
+	if !Enabled() {
+		return os.RemoveAll(path)
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return nil
+	}
+	add(Step{Action: ActionRemove, Path: path})
+	return nil
+}
+
+// Copy records that source would be copied to target
+func Copy(source, target string) {
+	add(Step{Action: ActionCopy, Path: target, Source: source})
+}
+
+// Symlink records that target would be linked to source
+func Symlink(source, target string) {
+	add(Step{Action: ActionSymlink, Path: target, Source: source})
+}
+
+// WriteFile records that the file at path would be written with data, and
+// makes ReadFile return data from now on. Writing what the file already
+// holds is not a change and records nothing.
+func WriteFile(path string, data []byte) {
+	old, err := ReadFile(path)
+	action := ActionEdit
+	if err != nil {
+		action = ActionCreate
+		old = nil
+	} else if string(old) == string(data) {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	files[path] = slices.Clone(data)
+	steps = append(steps, Step{Action: action, Path: path, Diff: Diff(string(old), string(data))})
+}
+
+// ReadFile is os.ReadFile, except that during a dry run a file written by
+// WriteFile reads back as written
+func ReadFile(path string) ([]byte, error) {
+	mu.Lock()
+	data, ok := files[path]
+	mu.Unlock()
+	if ok {
+		return slices.Clone(data), nil
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,92 @@
+package dryrun
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisabled(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "a", "b")
+
+	// Without a dry run the changes are made
+	require.NoError(t, MkdirAll(dir, 0755))
+	assert.DirExists(t, dir)
+	require.NoError(t, RemoveAll(dir))
+	assert.NoDirExists(t, dir)
+	assert.False(t, Enabled())
+	assert.Empty(t, Steps())
+}
+
+func TestMkdirAllAndRemoveAll(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing")
+	require.NoError(t, os.Mkdir(existing, 0755))
+	missing := filepath.Join(root, "missing")
+
+	Start()
+	defer Stop()
+	assert.True(t, Enabled())
+
+	require.NoError(t, MkdirAll(existing, 0755))
+	require.NoError(t, MkdirAll(missing, 0755))
+	require.NoError(t, RemoveAll(filepath.Join(root, "gone")))
+	require.NoError(t, RemoveAll(existing))
+	Run([]string{"git", "fetch"}, existing)
+
+	// Only the changes are planned, and none are made
+	assert.Equal(t, []Step{
+		{Action: ActionMkdir, Path: missing},
+		{Action: ActionRemove, Path: existing},
+		{Action: ActionRun, Argv: []string{"git", "fetch"}, Dir: existing},
+	}, Steps())
+	assert.DirExists(t, existing)
+	assert.NoDirExists(t, missing)
+}
+
+func TestWriteFileAndReadFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("a\nb\n"), 0644))
+	created := filepath.Join(root, "state.json")
+
+	Start()
+	defer Stop()
+
+	WriteFile(path, []byte("a\nb\n")) // Unchanged
+	WriteFile(path, []byte("a\nb\nc\n"))
+	WriteFile(created, []byte("{}\n"))
+
+	// Later reads see the planned content
+	data, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb\nc\n", string(data))
+	assert.NoFileExists(t, created)
+
+	WriteFile(path, []byte("b\nc\n"))
+
+	assert.Equal(t, []Step{
+		{Action: ActionEdit, Path: path, Diff: " a\n b\n+c\n"},
+		{Action: ActionCreate, Path: created, Diff: "+{}\n"},
+		{Action: ActionEdit, Path: path, Diff: "-a\n b\n c\n"},
+	}, Steps())
+
+	// Stopping discards the plan
+	Stop()
+	data, err = ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(data))
+	assert.Empty(t, Steps())
+}
+
+func TestDiff(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	new := "1\n2\n3\nfour\n5\n6\n7\n8\n9\nten\n"
+
+	assert.Equal(t, " 2\n 3\n-4\n+four\n 5\n 6\n...\n 8\n 9\n+ten\n", Diff(old, new))
+	assert.Equal(t, "", Diff(old, old))
+	assert.Equal(t, "-x\n", Diff("x\n", ""))
+}
@@ -15,11 +15,14 @@ const (
 	ErrCodeDiskFull          = "E102" // Disk full
 	ErrCodeFileNotFound      = "E103" // File not found
 	ErrCodeDirectoryNotEmpty = "E104" // Directory not empty
+	ErrCodeWorkspaceLocked   = "E105" // Workspace locked by another fa process
 
 	// Git errors (E2xx)
 	ErrCodeGitNotInstalled    = "E201" // Git not installed
 	ErrCodeGitOperationFailed = "E202" // Git operation failed
 	ErrCodeInvalidRepository  = "E203" // Invalid git repository
+	ErrCodeGitLFSNotInstalled = "E204" // Git LFS needed but not installed
+	ErrCodeGitTimeout         = "E205" // Git operation timed out
 
 	// Worktree errors (E3xx)
 	ErrCodeWorktreeExists   = "E301" // Worktree already exists
@@ -143,9 +143,12 @@ func TestErrorCodes(t *testing.T) {
 		ErrCodeDiskFull:             true,
 		ErrCodeFileNotFound:         true,
 		ErrCodeDirectoryNotEmpty:    true,
+		ErrCodeWorkspaceLocked:      true,
 		ErrCodeGitNotInstalled:      true,
 		ErrCodeGitOperationFailed:   true,
 		ErrCodeInvalidRepository:    true,
+		ErrCodeGitLFSNotInstalled:   true,
+		ErrCodeGitTimeout:           true,
 		ErrCodeWorktreeExists:       true,
 		ErrCodeWorktreeNotFound:     true,
 		ErrCodeBranchExists:         true,
@@ -157,8 +160,8 @@ func TestErrorCodes(t *testing.T) {
 	}
 
 	// Verify count matches expectations
-	if len(codes) != 21 {
-		t.Errorf("Expected 21 unique error codes, got %d", len(codes))
+	if len(codes) != 24 {
+		t.Errorf("Expected 24 unique error codes, got %d", len(codes))
 	}
 
 	// Verify specific code values
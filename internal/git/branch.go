@@ -1,7 +1,6 @@
 package git
 
 import (
-	"os/exec"
 	"strings"
 
 	"github.com/foundagent/foundagent/internal/errors"
@@ -9,14 +8,14 @@ import (
 
 // BranchExists checks if a branch exists in a repository
 func BranchExists(bareRepoPath, branchName string) (bool, error) {
-	cmd := exec.Command("git", "--git-dir="+bareRepoPath, "rev-parse", "--verify", "refs/heads/"+branchName)
+	cmd := command("--git-dir="+bareRepoPath, "rev-parse", "--verify", "refs/heads/"+branchName)
 	err := cmd.Run()
 	if err != nil {
 		// Exit code 128 means branch doesn't exist
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+		if exitCode(err) == 128 {
 			return false, nil
 		}
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check if branch exists",
 			"Check repository state with 'git status'",
@@ -26,12 +25,19 @@ func BranchExists(bareRepoPath, branchName string) (bool, error) {
 	return true, nil
 }
 
+// RemoteBranchExists reports whether a remote-tracking branch such as
+// upstream/main exists in a repository
+func RemoteBranchExists(bareRepoPath, remoteBranch string) bool {
+	cmd := command("--git-dir="+bareRepoPath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+remoteBranch)
+	return cmd.Run() == nil
+}
+
 // CreateBranch creates a new branch from a source branch in a bare repository
 func CreateBranch(bareRepoPath, newBranch, sourceBranch string) error {
-	cmd := exec.Command("git", "--git-dir="+bareRepoPath, "branch", newBranch, sourceBranch)
+	cmd := command("--git-dir="+bareRepoPath, "branch", newBranch, sourceBranch)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to create branch: "+strings.TrimSpace(string(output)),
 			"Check that source branch exists",
@@ -48,10 +54,10 @@ func DeleteBranch(bareRepoPath, branchName string, force bool) error {
 		deleteFlag = "-D"
 	}
 
-	cmd := exec.Command("git", "--git-dir="+bareRepoPath, "branch", deleteFlag, branchName)
+	cmd := command("--git-dir="+bareRepoPath, "branch", deleteFlag, branchName)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to delete branch: "+strings.TrimSpace(string(output)),
 			"Check that branch exists and is fully merged (or use --force)",
@@ -61,13 +67,28 @@ func DeleteBranch(bareRepoPath, branchName string, force bool) error {
 	return nil
 }
 
+// BranchSHA returns the full SHA of the commit a branch points at
+func BranchSHA(bareRepoPath, branchName string) (string, error) {
+	cmd := command("--git-dir="+bareRepoPath, "rev-parse", "--verify", "refs/heads/"+branchName)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", wrap(
+			errors.ErrCodeBranchNotFound,
+			"Failed to resolve branch "+branchName,
+			"Check that the branch exists with 'git branch'",
+			err,
+		)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // IsBranchMerged checks if a branch is fully merged into another branch
 func IsBranchMerged(bareRepoPath, branch, baseBranch string) (bool, error) {
 	// Use git branch --merged to check if branch is in the merged list
-	cmd := exec.Command("git", "--git-dir="+bareRepoPath, "branch", "--merged", baseBranch, "--format=%(refname:short)")
+	cmd := command("--git-dir="+bareRepoPath, "branch", "--merged", baseBranch, "--format=%(refname:short)")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check if branch is merged",
 			"Check repository state",
@@ -86,10 +107,10 @@ func IsBranchMerged(bareRepoPath, branch, baseBranch string) (bool, error) {
 
 // GetBranches lists all branches in a repository
 func GetBranches(bareRepoPath string) ([]string, error) {
-	cmd := exec.Command("git", "--git-dir="+bareRepoPath, "branch", "--list", "--format=%(refname:short)")
+	cmd := command("--git-dir="+bareRepoPath, "branch", "--list", "--format=%(refname:short)")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to list branches",
 			"Check repository state",
@@ -109,14 +130,14 @@ func GetBranches(bareRepoPath string) ([]string, error) {
 
 // IsDetachedHead checks if a worktree is in detached HEAD state
 func IsDetachedHead(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "symbolic-ref", "-q", "HEAD")
+	cmd := command("-C", worktreePath, "symbolic-ref", "-q", "HEAD")
 	err := cmd.Run()
 	if err != nil {
 		// Exit code 1 means detached HEAD
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if exitCode(err) == 1 {
 			return true, nil
 		}
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check HEAD state",
 			"Check repository state with 'git status'",
@@ -125,3 +146,9 @@ func IsDetachedHead(worktreePath string) (bool, error) {
 	}
 	return false, nil
 }
+
+// CommitExists checks if a commit is present in a repository
+func CommitExists(bareRepoPath, sha string) bool {
+	cmd := command("--git-dir="+bareRepoPath, "cat-file", "-e", sha+"^{commit}")
+	return cmd.Run() == nil
+}
@@ -2,21 +2,31 @@ package git
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 
 	"github.com/foundagent/foundagent/internal/errors"
 )
 
 // CloneOptions represents options for cloning a repository
 type CloneOptions struct {
-	URL        string
-	TargetPath string
-	Bare       bool
-	Progress   bool
+	URL          string
+	TargetPath   string
+	Bare         bool
+	Progress     bool
+	Depth        int    // Truncate history to this many commits (0 = full history)
+	Filter       string // Partial clone filter, e.g. blob:none
+	SingleBranch bool   // Clone only the remote's default branch
+
+	OnRetry func(Retry) // Called before each retry after a transient network failure
+
+	// Output receives git's progress and messages, with progress reported
+	// even though it is not a terminal (nil = the terminal)
+	Output io.Writer
 }
 
-// Clone performs a git clone operation
+// Clone performs a git clone operation, retrying transient network failures.
+// git removes a failed clone's target directory, so each attempt starts over.
 func Clone(opts CloneOptions) error {
 	args := []string{"clone"}
 
@@ -24,31 +34,48 @@ func Clone(opts CloneOptions) error {
 		args = append(args, "--bare")
 	}
 
-	if !opts.Progress {
+	if opts.Output != nil {
+		args = append(args, "--progress")
+	} else if !opts.Progress {
 		args = append(args, "--quiet")
 	}
 
+	if opts.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	}
+
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+
 	args = append(args, opts.URL, opts.TargetPath)
 
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if opts.Output != nil {
+		cmd.Stdout = opts.Output
+		cmd.Stderr = opts.Output
+	}
+	cmd.Retryable = true
+	cmd.OnRetry = opts.OnRetry
 
 	if err := cmd.Run(); err != nil {
 		// Check if it's an auth error
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode := exitErr.ExitCode()
-			if exitCode == 128 {
-				return errors.Wrap(
-					errors.ErrCodeGitOperationFailed,
-					fmt.Sprintf("Failed to clone repository: %s", opts.URL),
-					"Check that the repository exists and you have access. For private repos, ensure your SSH key is configured or use HTTPS with credentials",
-					err,
-				)
-			}
+		if exitCode(err) == 128 {
+			return wrap(
+				errors.ErrCodeGitOperationFailed,
+				fmt.Sprintf("Failed to clone repository: %s", opts.URL),
+				"Check that the repository exists and you have access. For private repos, ensure your SSH key is configured or use HTTPS with credentials",
+				err,
+			)
 		}
 
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			fmt.Sprintf("Git clone failed: %s", opts.URL),
 			"Verify the repository URL and your network connection",
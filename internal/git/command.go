@@ -0,0 +1,318 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/foundagent/foundagent/gitexec"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/trace"
+)
+
+// Operations with their own timeout. Fetch covers every command that reads
+// from a remote, such as pull, ls-remote, submodule update and git lfs.
+const (
+	OpClone = "clone"
+	OpFetch = "fetch"
+	OpPush  = "push"
+	OpLocal = "local"
+)
+
+// Timeouts limits how long each operation may run
+type Timeouts struct {
+	Clone time.Duration
+	Fetch time.Duration
+	Push  time.Duration
+	Local time.Duration
+}
+
+// DefaultTimeouts are the timeouts used unless configured otherwise
+var DefaultTimeouts = Timeouts{
+	Clone: 30 * time.Minute,
+	Fetch: 10 * time.Minute,
+	Push:  10 * time.Minute,
+	Local: 5 * time.Minute,
+}
+
+var (
+	// baseContext is the context every git command runs under
+	baseContext = context.Background()
+
+	// timeouts are the timeouts of git commands started from now on
+	timeouts = DefaultTimeouts
+
+	// nonInteractive makes git fail instead of prompting for credentials
+	// or host keys
+	nonInteractive bool
+)
+
+// SetContext sets the context that git commands started from now on run
+// under. Cancelling it, such as on Ctrl-C, interrupts the git processes that
+// are still running and makes new ones fail to start.
+func SetContext(ctx context.Context) {
+	baseContext = ctx
+}
+
+// SetTimeouts sets the timeouts of git commands started from now on. Zero
+// fields keep their default.
+func SetTimeouts(t Timeouts) {
+	timeouts = DefaultTimeouts
+	if t.Clone > 0 {
+		timeouts.Clone = t.Clone
+	}
+	if t.Fetch > 0 {
+		timeouts.Fetch = t.Fetch
+	}
+	if t.Push > 0 {
+		timeouts.Push = t.Push
+	}
+	if t.Local > 0 {
+		timeouts.Local = t.Local
+	}
+}
+
+// SetNonInteractive makes git commands fail rather than prompt for a
+// password, token or SSH host key, which nobody may be there to answer
+func SetNonInteractive(on bool) {
+	nonInteractive = on
+}
+
+// Version returns the output of 'git --version'
+func Version() (string, error) {
+	output, err := command("--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Cmd is a git command. Every git command foundagent runs goes through Cmd,
+// which applies the timeout of its operation, the non-interactive
+// environment, cancellation, and retries of network failures.
+type Cmd struct {
+	Args   []string // Arguments after "git"
+	Dir    string
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Retryable commands are run again, up to the configured number of
+	// retries, when git reports a transient network failure. Only commands
+	// that are safe to repeat should set it.
+	Retryable bool
+
+	// OnRetry, if set, is called before each retry
+	OnRetry func(Retry)
+}
+
+// command returns a git command with the given arguments
+func command(args ...string) *Cmd {
+	return &Cmd{Args: args}
+}
+
+// Run runs the command and waits for it to finish, like exec.Cmd.Run
+func (c *Cmd) Run() error {
+	return c.run(func() (io.Writer, io.Writer) {
+		return c.Stdout, c.Stderr
+	})
+}
+
+// Output runs the command and returns its standard output, like
+// exec.Cmd.Output
+func (c *Cmd) Output() ([]byte, error) {
+	var stdout bytes.Buffer
+	err := c.run(func() (io.Writer, io.Writer) {
+		stdout.Reset()
+		return &stdout, c.Stderr
+	})
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its standard output and
+// standard error, like exec.Cmd.CombinedOutput
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	var output bytes.Buffer
+	err := c.run(func() (io.Writer, io.Writer) {
+		output.Reset()
+		return &output, &output
+	})
+	return output.Bytes(), err
+}
+
+// run runs the command with the standard output and error that streams
+// returns for each attempt, and retries a retryable command after transient
+// failures with exponential backoff. Timeouts and cancellation are never
+// retried.
+func (c *Cmd) run(streams func() (stdout, stderr io.Writer)) error {
+	for attempt := 1; ; attempt++ {
+		stderr, err := c.runOnce(streams)
+		if err == nil || !c.Retryable || attempt > retries || baseContext.Err() != nil {
+			return err
+		}
+		if exitCode(err) < 0 {
+			return err
+		}
+		reason := transientFailure(stderr)
+		if reason == "" {
+			return err
+		}
+
+		delay := retryDelay(attempt)
+		if c.OnRetry != nil {
+			c.OnRetry(Retry{Attempt: attempt, Delay: delay, DelayMs: delay.Milliseconds(), Reason: reason})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-baseContext.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// runOnce runs git through the executor under the timeout of the command's
+// operation, and returns its stderr if the command is retryable or traced.
+// A command that runs out of time fails with ErrCodeGitTimeout.
+func (c *Cmd) runOnce(streams func() (stdout, stderr io.Writer)) ([]byte, error) {
+	op := operation(c.Args)
+	timeout := timeoutFor(op)
+
+	ctx, cancel := context.WithTimeout(baseContext, timeout)
+	defer cancel()
+
+	inv := Invocation{Args: c.Args, Dir: c.Dir}
+	inv.Stdout, inv.Stderr = streams()
+	if nonInteractive {
+		inv.Env = nonInteractiveEnv()
+	}
+
+	// Keep a copy of stderr to tell transient failures apart and for the
+	// trace
+	var stderr bytes.Buffer
+	if c.Retryable || trace.Enabled() {
+		// Output and errors written to one writer go through separate pipes
+		// so stderr can be told apart, and take turns writing
+		if inv.Stdout != nil && sameWriter(inv.Stdout, inv.Stderr) {
+			shared := &lockedWriter{w: inv.Stdout}
+			inv.Stdout, inv.Stderr = shared, shared
+		}
+		if inv.Stderr == nil {
+			inv.Stderr = &stderr
+		} else {
+			inv.Stderr = io.MultiWriter(inv.Stderr, &stderr)
+		}
+	}
+
+	start := time.Now()
+	err := gitexec.Current().Run(ctx, inv)
+	trace.Exec(append([]string{"git"}, c.Args...), c.Dir, start, stderr.Bytes(), err)
+	if err != nil && ctx.Err() == context.DeadlineExceeded && baseContext.Err() == nil {
+		return stderr.Bytes(), timedOut(op, subcommand(c.Args), timeout, err)
+	}
+	return stderr.Bytes(), err
+}
+
+// sameWriter reports whether a and b are the same writer, and is false for
+// writers that cannot be compared, like exec.Cmd's check for the same
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() {
+		_ = recover()
+	}()
+	return a == b
+}
+
+// lockedWriter lets the stdout and stderr of a command write to one writer
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// nonInteractiveEnv returns the variables that turn off git's terminal
+// prompts, the credential manager's dialogs and ssh's prompts. An ssh
+// command set by the user is kept.
+func nonInteractiveEnv() []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never"}
+	if os.Getenv("GIT_SSH_COMMAND") == "" && os.Getenv("GIT_SSH") == "" {
+		env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	return env
+}
+
+// subcommand returns the git subcommand in args, skipping global options
+func subcommand(args []string) string {
+	if i := subcommandIndex(args); i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// operation returns the operation whose timeout applies to a git command
+func operation(args []string) string {
+	sub := subcommand(args)
+	switch sub {
+	case "clone":
+		return OpClone
+	case "push":
+		return OpPush
+	case "fetch", "pull", "ls-remote", "submodule", "lfs":
+		return OpFetch
+	case "remote":
+		// 'git remote show' and 'update' contact the remote
+		rest := args[slices.Index(args, sub)+1:]
+		if slices.Contains(rest, "show") || slices.Contains(rest, "update") {
+			return OpFetch
+		}
+	}
+	return OpLocal
+}
+
+func timeoutFor(op string) time.Duration {
+	switch op {
+	case OpClone:
+		return timeouts.Clone
+	case OpFetch:
+		return timeouts.Fetch
+	case OpPush:
+		return timeouts.Push
+	default:
+		return timeouts.Local
+	}
+}
+
+func timedOut(op, sub string, timeout time.Duration, err error) *errors.Error {
+	remediation := fmt.Sprintf("Raise settings.timeouts.%s if it needs longer", op)
+	if op != OpLocal {
+		remediation = fmt.Sprintf("Check your network connection and credentials; git may be waiting for a password or host key confirmation. Use --non-interactive to fail fast instead, or raise settings.timeouts.%s if it needs longer", op)
+	}
+	return errors.Wrap(
+		errors.ErrCodeGitTimeout,
+		fmt.Sprintf("git %s timed out after %s", sub, timeout),
+		remediation,
+		err,
+	)
+}
+
+// wrap is errors.Wrap, except that a git command that timed out keeps its
+// timeout code and remediation rather than being reported as a generic
+// failure
+func wrap(code, message, remediation string, cause error) *errors.Error {
+	if timeout, ok := cause.(*errors.Error); ok && timeout.Code == errors.ErrCodeGitTimeout {
+		code = timeout.Code
+		remediation = timeout.Remediation
+	}
+	return errors.Wrap(code, message, remediation, cause)
+}
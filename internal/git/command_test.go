@@ -0,0 +1,166 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"clone", "--bare", "url", "path"}, OpClone},
+		{[]string{"--git-dir=/repo", "fetch", "origin"}, OpFetch},
+		{[]string{"-C", "/wt", "pull", "--ff-only"}, OpFetch},
+		{[]string{"-C", "/wt", "submodule", "update", "--init"}, OpFetch},
+		{[]string{"-C", "/wt", "lfs", "pull"}, OpFetch},
+		{[]string{"remote", "show", "origin"}, OpFetch},
+		{[]string{"-C", "/wt", "push", "-u", "origin", "main"}, OpPush},
+		{[]string{"-C", "/wt", "remote", "get-url", "origin"}, OpLocal},
+		{[]string{"-C", "/push", "status", "--porcelain"}, OpLocal},
+		{[]string{"-c", "core.pager=cat", "log"}, OpLocal},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, operation(tt.args), "%v", tt.args)
+	}
+}
+
+func TestSetTimeouts(t *testing.T) {
+	defer SetTimeouts(Timeouts{})
+
+	SetTimeouts(Timeouts{Fetch: time.Minute})
+
+	assert.Equal(t, time.Minute, timeoutFor(OpFetch))
+	assert.Equal(t, DefaultTimeouts.Clone, timeoutFor(OpClone))
+	assert.Equal(t, DefaultTimeouts.Local, timeoutFor(OpLocal))
+}
+
+func TestCmd_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the ssh command")
+	}
+
+	// An ssh command that never answers, like one waiting at a prompt
+	ssh := filepath.Join(t.TempDir(), "ssh")
+	require.NoError(t, os.WriteFile(ssh, []byte("#!/bin/sh\nexec sleep 30\n"), 0755))
+	t.Setenv("GIT_SSH_COMMAND", ssh)
+
+	SetTimeouts(Timeouts{Fetch: 200 * time.Millisecond})
+	defer SetTimeouts(Timeouts{})
+
+	start := time.Now()
+	_, err := command("ls-remote", "git@example.com:org/repo.git").Output()
+
+	assert.Less(t, time.Since(start), 10*time.Second)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeGitTimeout, faErr.Code)
+	assert.Contains(t, faErr.Message, "git ls-remote timed out")
+
+	// Callers that wrap the error keep the timeout code
+	wrapped := wrap(errors.ErrCodeGitOperationFailed, "Failed to list remote", "Check the URL", err)
+	assert.Equal(t, errors.ErrCodeGitTimeout, wrapped.Code)
+	assert.Equal(t, faErr.Remediation, wrapped.Remediation)
+}
+
+func TestNonInteractiveEnv(t *testing.T) {
+	t.Setenv("GIT_SSH_COMMAND", "")
+	t.Setenv("GIT_SSH", "")
+
+	env := nonInteractiveEnv()
+	assert.Contains(t, env, "GIT_TERMINAL_PROMPT=0")
+	assert.Contains(t, env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+
+	// An ssh command set by the user is kept
+	t.Setenv("GIT_SSH_COMMAND", "ssh -i key")
+	env = nonInteractiveEnv()
+	assert.False(t, slices.Contains(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes"))
+}
+
+func TestVersion(t *testing.T) {
+	version, err := Version()
+	require.NoError(t, err)
+	assert.Contains(t, version, "git version")
+}
+
+// writerExecutor writes to the invocation's stdout and stderr, if set, and
+// fails with code
+type writerExecutor struct {
+	code int
+	inv  Invocation
+}
+
+func (e *writerExecutor) Run(ctx context.Context, inv Invocation) error {
+	e.inv = inv
+	_, _ = io.WriteString(inv.Stdout, "out\n")
+	if inv.Stderr != nil {
+		_, _ = io.WriteString(inv.Stderr, "fatal: remote error\n")
+	}
+	if e.code != 0 {
+		return &ExitError{Code: e.code}
+	}
+	return nil
+}
+
+func TestCmd_Executor(t *testing.T) {
+	exec := &writerExecutor{code: 2}
+	SetExecutor(exec)
+	defer SetExecutor(nil)
+
+	cmd := command("-C", "/wt", "push")
+	cmd.Retryable = true
+	output, err := cmd.CombinedOutput()
+
+	assert.Equal(t, 2, exitCode(err))
+	assert.Equal(t, "out\nfatal: remote error\n", string(output))
+	// Stderr is kept apart from the combined output for retries
+	assert.False(t, sameWriter(exec.inv.Stdout, exec.inv.Stderr))
+
+	exec.code = 0
+	output, err = command("status").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "out\n", string(output))
+	assert.Equal(t, []string{"status"}, exec.inv.Args)
+}
+
+func TestExitCode(t *testing.T) {
+	err := command("rev-parse", "--verify", "no-such-ref").Run()
+	assert.Equal(t, 128, exitCode(err))
+	assert.Equal(t, 1, exitCode(&ExitError{Code: 1}))
+	assert.Equal(t, -1, exitCode(context.Canceled))
+	assert.Equal(t, -1, exitCode(nil))
+}
+
+func TestCmd_TraceCombinedOutput(t *testing.T) {
+	var records bytes.Buffer
+	trace.AddWriter(&records)
+	defer trace.Close()
+	SetExecutor(&writerExecutor{code: 1})
+	defer SetExecutor(nil)
+
+	output, err := command("status").CombinedOutput()
+	require.Error(t, err)
+	assert.Equal(t, "out\nfatal: remote error\n", string(output))
+
+	// The trace holds stderr alone, not the output combined with it
+	var rec trace.Record
+	require.NoError(t, json.Unmarshal(records.Bytes(), &rec))
+	assert.Equal(t, []string{"git", "status"}, rec.Argv)
+	assert.Equal(t, 1, rec.ExitCode)
+	assert.Equal(t, "fatal: remote error", rec.Stderr)
+}
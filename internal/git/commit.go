@@ -2,7 +2,6 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 
@@ -25,14 +24,14 @@ type CommitStats struct {
 
 // HasStagedChanges checks if a worktree has staged changes
 func HasStagedChanges(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff", "--cached", "--quiet")
+	cmd := command("-C", worktreePath, "diff", "--cached", "--quiet")
 	err := cmd.Run()
 	if err != nil {
 		// Exit code 1 means there are staged changes
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if exitCode(err) == 1 {
 			return true, nil
 		}
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check for staged changes",
 			"Verify the worktree path is valid",
@@ -69,7 +68,7 @@ func Commit(worktreePath string, opts CommitOptions) (string, error) {
 		args = append(args, "-m", opts.Message)
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		outputStr := string(output)
@@ -87,7 +86,7 @@ func Commit(worktreePath string, opts CommitOptions) (string, error) {
 		// Check for pre-commit hook failure
 		if strings.Contains(outputStr, "pre-commit hook") ||
 			strings.Contains(outputStr, "hook failed") {
-			return "", errors.Wrap(
+			return "", wrap(
 				errors.ErrCodeCommitFailed,
 				"Pre-commit hook failed",
 				"Fix the issues reported by the pre-commit hook",
@@ -95,7 +94,7 @@ func Commit(worktreePath string, opts CommitOptions) (string, error) {
 			)
 		}
 
-		return "", errors.Wrap(
+		return "", wrap(
 			errors.ErrCodeCommitFailed,
 			"Commit failed: "+strings.TrimSpace(outputStr),
 			"Check git status for details",
@@ -114,10 +113,10 @@ func Commit(worktreePath string, opts CommitOptions) (string, error) {
 
 // GetStagedFiles returns the list of staged files in a worktree
 func GetStagedFiles(worktreePath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff", "--cached", "--name-only")
+	cmd := command("-C", worktreePath, "diff", "--cached", "--name-only")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get staged files",
 			"Verify the worktree path is valid",
@@ -135,10 +134,10 @@ func GetStagedFiles(worktreePath string) ([]string, error) {
 
 // GetStagedFilesWithStatus returns staged files with their status (M, A, D, etc.)
 func GetStagedFilesWithStatus(worktreePath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff", "--cached", "--name-status")
+	cmd := command("-C", worktreePath, "diff", "--cached", "--name-status")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get staged files with status",
 			"Verify the worktree path is valid",
@@ -156,10 +155,10 @@ func GetStagedFilesWithStatus(worktreePath string) ([]string, error) {
 
 // GetCommitStats returns statistics for a specific commit
 func GetCommitStats(worktreePath, sha string) (*CommitStats, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff-tree", "--no-commit-id", "--numstat", "-r", sha)
+	cmd := command("-C", worktreePath, "diff-tree", "--no-commit-id", "--numstat", "-r", sha)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get commit stats",
 			"Verify the commit SHA is valid",
@@ -193,10 +192,26 @@ func GetCommitStats(worktreePath, sha string) (*CommitStats, error) {
 
 // GetHeadSHA returns the short SHA of HEAD
 func GetHeadSHA(worktreePath string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--short", "HEAD")
+	cmd := command("-C", worktreePath, "rev-parse", "--short", "HEAD")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", errors.Wrap(
+		return "", wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to get HEAD SHA",
+			"Verify the repository has commits",
+			err,
+		)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetFullHeadSHA gets the full SHA of HEAD, suitable for pinning
+func GetFullHeadSHA(worktreePath string) (string, error) {
+	cmd := command("-C", worktreePath, "rev-parse", "HEAD")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get HEAD SHA",
 			"Verify the repository has commits",
@@ -209,10 +224,10 @@ func GetHeadSHA(worktreePath string) (string, error) {
 
 // StageAllTracked stages all tracked file modifications (git add -u)
 func StageAllTracked(worktreePath string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "add", "-u")
+	cmd := command("-C", worktreePath, "add", "-u")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to stage tracked files: "+strings.TrimSpace(string(output)),
 			"Check git status for details",
@@ -224,14 +239,14 @@ func StageAllTracked(worktreePath string) error {
 
 // HasTrackedChanges checks if there are modifications to tracked files
 func HasTrackedChanges(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff", "--quiet")
+	cmd := command("-C", worktreePath, "diff", "--quiet")
 	err := cmd.Run()
 	if err != nil {
 		// Exit code 1 means there are changes
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if exitCode(err) == 1 {
 			return true, nil
 		}
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check for tracked changes",
 			"Verify the worktree path is valid",
@@ -243,10 +258,10 @@ func HasTrackedChanges(worktreePath string) (bool, error) {
 
 // GetCommitMessage returns the commit message for a given SHA
 func GetCommitMessage(worktreePath, sha string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "log", "-1", "--format=%s", sha)
+	cmd := command("-C", worktreePath, "log", "-1", "--format=%s", sha)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", errors.Wrap(
+		return "", wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get commit message",
 			"Verify the commit SHA is valid",
@@ -259,10 +274,10 @@ func GetCommitMessage(worktreePath, sha string) (string, error) {
 
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch(worktreePath string) (string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := command("-C", worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", errors.Wrap(
+		return "", wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get current branch",
 			"Verify the worktree path is valid",
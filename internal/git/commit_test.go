@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -361,3 +362,20 @@ func TestCommitAmend(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Updated message", newMsg)
 }
+
+func TestGetFullHeadSHA(t *testing.T) {
+	dir := setupTestRepo(t)
+
+	short, err := GetHeadSHA(dir)
+	require.NoError(t, err)
+	full, err := GetFullHeadSHA(dir)
+	require.NoError(t, err)
+
+	assert.Len(t, full, 40)
+	assert.True(t, strings.HasPrefix(full, short))
+	assert.True(t, CommitExists(filepath.Join(dir, ".git"), full))
+	assert.False(t, CommitExists(filepath.Join(dir, ".git"), strings.Repeat("0", 40)))
+
+	_, err = GetFullHeadSHA(t.TempDir())
+	assert.Error(t, err)
+}
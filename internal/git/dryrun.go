@@ -0,0 +1,112 @@
+package git
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// DryRun is an executor for dry runs. It runs the commands that only read,
+// so a command can still inspect repositories and plan, and hands every
+// other command to Record instead of running it. Commands it cannot tell
+// are read-only are treated as changes.
+type DryRun struct {
+	Next   Executor             // Runs the read-only commands
+	Record func(inv Invocation) // Called with each command not run
+}
+
+// Run runs a read-only command and records any other
+func (d DryRun) Run(ctx context.Context, inv Invocation) error {
+	if ReadOnly(inv.Args) {
+		return d.Next.Run(ctx, inv)
+	}
+	if d.Record != nil {
+		d.Record(inv)
+	}
+	return nil
+}
+
+// readOnlySubcommands never change a repository
+var readOnlySubcommands = []string{
+	"", // git --version
+	"cat-file", "diff", "diff-tree", "for-each-ref", "log", "ls-files",
+	"ls-remote", "merge-base", "rev-list", "rev-parse", "show", "show-ref",
+	"status",
+}
+
+// ReadOnly reports whether the git command with args only reads
+// repositories, so it is safe to run in a dry run
+func ReadOnly(args []string) bool {
+	i := subcommandIndex(args)
+	sub := ""
+	if i < len(args) {
+		sub = args[i]
+	}
+	if slices.Contains(readOnlySubcommands, sub) {
+		return true
+	}
+
+	rest := args[min(i+1, len(args)):]
+	positional := positionalArgs(rest)
+	action := ""
+	if len(positional) > 0 {
+		action = positional[0]
+	}
+
+	switch sub {
+	case "symbolic-ref":
+		// With a second argument it sets the reference
+		return len(positional) <= 1 && !slices.Contains(rest, "-d") && !slices.Contains(rest, "--delete")
+	case "config":
+		return slices.ContainsFunc(rest, func(arg string) bool {
+			return arg == "--list" || arg == "-l" || strings.HasPrefix(arg, "--get")
+		})
+	case "branch":
+		for _, arg := range rest {
+			switch arg {
+			case "-d", "-D", "--delete", "-m", "-M", "--move", "-c", "-C", "--copy", "-u", "--set-upstream-to", "--unset-upstream":
+				return false
+			}
+		}
+		return len(positional) == 0 || slices.ContainsFunc(rest, func(arg string) bool {
+			return arg == "--list" || arg == "-l" || strings.HasPrefix(arg, "--merged") || strings.HasPrefix(arg, "--no-merged") || strings.HasPrefix(arg, "--contains")
+		})
+	case "worktree", "sparse-checkout":
+		return action == "list"
+	case "stash":
+		return action == "list" || action == "show"
+	case "remote":
+		return action == "" || action == "get-url" || action == "show"
+	case "submodule":
+		return action == "status"
+	case "lfs":
+		return action == "version" || action == "ls-files" || action == "env"
+	}
+	return false
+}
+
+// subcommandIndex returns the index of the git subcommand in args, or
+// len(args) if there is none
+func subcommandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-C" || arg == "-c":
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return i
+		}
+	}
+	return len(args)
+}
+
+// positionalArgs returns the arguments that are not options
+func positionalArgs(args []string) []string {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	return positional
+}
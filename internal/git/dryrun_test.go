@@ -0,0 +1,76 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		args string
+		want bool
+	}{
+		{"--version", true},
+		{"-C /wt status --porcelain", true},
+		{"--git-dir=/b rev-parse --verify refs/heads/x", true},
+		{"ls-remote --symref https://example.com/r.git HEAD", true},
+		{"symbolic-ref refs/remotes/origin/HEAD", true},
+		{"symbolic-ref HEAD refs/heads/main", false},
+		{"-C /r config --get remote.origin.url", true},
+		{"-C /r config --get-all remote.origin.fetch", true},
+		{"-C /r config --add remote.origin.fetch +refs/*", false},
+		{"--git-dir=/b branch --list --format=%(refname:short)", true},
+		{"--git-dir=/b branch --merged main --format=%(refname:short)", true},
+		{"--git-dir=/b branch feature main", false},
+		{"--git-dir=/b branch -D feature", false},
+		{"--git-dir=/b worktree list --porcelain", true},
+		{"--git-dir=/b worktree add -b x /wt main", false},
+		{"--git-dir=/b worktree remove /wt", false},
+		{"-C /wt stash list", true},
+		{"-C /wt stash push -m msg", false},
+		{"-C /wt stash pop", false},
+		{"remote", true},
+		{"-C /r remote get-url origin", true},
+		{"-C /r remote add origin url", false},
+		{"-C /wt sparse-checkout list", true},
+		{"-C /wt sparse-checkout set --cone a", false},
+		{"-C /wt submodule status --recursive", true},
+		{"-C /wt submodule update --init", false},
+		{"lfs version", true},
+		{"-C /wt lfs fetch", false},
+		{"clone --bare url /b", false},
+		{"fetch --prune", false},
+		{"-C /wt pull --ff-only", false},
+		{"-C /wt push", false},
+		{"-C /wt commit -m msg", false},
+		{"unknown-command", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ReadOnly(strings.Fields(tt.args)), tt.args)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	next := &writerExecutor{}
+	var recorded []Invocation
+	SetExecutor(DryRun{Next: next, Record: func(inv Invocation) { recorded = append(recorded, inv) }})
+	defer SetExecutor(nil)
+
+	// Read-only commands run
+	output, err := command("-C", "/wt", "status").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "out\n", string(output))
+
+	// Others are recorded and succeed without running
+	next.code = 1
+	next.inv = Invocation{}
+	require.NoError(t, PushBranch("/wt", PushOptions{}))
+	assert.Nil(t, next.inv.Args)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "/wt", recorded[0].Args[1])
+	assert.Equal(t, "push", subcommand(recorded[0].Args))
+}
@@ -0,0 +1,30 @@
+package git
+
+import (
+	"github.com/foundagent/foundagent/gitexec"
+)
+
+// The executor types are defined in the public package gitexec, so programs
+// and test harnesses outside this module can swap out how git is run. These
+// aliases keep the short names this package uses.
+type (
+	Executor   = gitexec.Executor
+	Invocation = gitexec.Invocation
+	ExitError  = gitexec.ExitError
+	Subprocess = gitexec.Subprocess
+)
+
+// SetExecutor sets the executor that git commands started from now on run
+// through. nil restores the default Subprocess.
+func SetExecutor(e Executor) {
+	gitexec.Set(e)
+}
+
+// exitCode returns the code a git command that ran and failed exited with,
+// or -1 if err is not such a failure
+func exitCode(err error) int {
+	if exitErr, ok := err.(interface{ ExitCode() int }); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
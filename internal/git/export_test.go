@@ -0,0 +1,9 @@
+package git
+
+import "time"
+
+// SetRetryBaseDelay sets the delay before the first retry, so tests outside
+// the package need not wait for real backoff
+func SetRetryBaseDelay(d time.Duration) {
+	retryBaseDelay = d
+}
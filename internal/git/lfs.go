@@ -0,0 +1,65 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// UsesLFS reports whether a worktree's top-level .gitattributes routes any
+// files through the Git LFS filter
+func UsesLFS(worktreePath string) bool {
+	file, err := os.Open(filepath.Join(worktreePath, ".gitattributes"))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, attr := range strings.Fields(line) {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LFSInstalled reports whether the git lfs command is available
+func LFSInstalled() bool {
+	return command("lfs", "version").Run() == nil
+}
+
+// LFSPull downloads the LFS objects a worktree's checkout needs and replaces
+// pointer files with their contents
+func LFSPull(worktreePath string) error {
+	if !LFSInstalled() {
+		return errors.New(
+			errors.ErrCodeGitLFSNotInstalled,
+			"Repository uses Git LFS but git lfs is not installed; large files are checked out as pointers",
+			"Install Git LFS (https://git-lfs.com), then run 'git lfs pull' in the worktree",
+		)
+	}
+
+	for _, step := range []string{"fetch", "checkout"} {
+		cmd := command("-C", worktreePath, "lfs", step)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return wrap(
+				errors.ErrCodeGitOperationFailed,
+				"Failed to "+step+" Git LFS files: "+strings.TrimSpace(string(output)),
+				"Check access to the LFS server, then run 'git lfs pull' in the worktree",
+				err,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLFS puts git on a fresh PATH, with a git-lfs stub that logs its
+// arguments when installed is true. It returns the log file.
+func fakeLFS(t *testing.T, installed bool) string {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+
+	bin := t.TempDir()
+	require.NoError(t, os.Symlink(gitPath, filepath.Join(bin, "git")))
+
+	logFile := filepath.Join(t.TempDir(), "lfs.log")
+	if installed {
+		script := "#!/bin/sh\necho \"$@\" >> " + logFile + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "git-lfs"), []byte(script), 0755))
+	}
+
+	t.Setenv("PATH", bin)
+	return logFile
+}
+
+func TestUsesLFS(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes string
+		want       bool
+	}{
+		{"lfs filter", "*.psd filter=lfs diff=lfs merge=lfs -text\n", true},
+		{"among other rules", "*.go text eol=lf\nassets/** filter=lfs diff=lfs merge=lfs -text\n", true},
+		{"commented out", "# *.psd filter=lfs diff=lfs merge=lfs -text\n", false},
+		{"other filter", "*.secret filter=git-crypt diff=git-crypt\n", false},
+		{"no attributes", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.attributes != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(tt.attributes), 0644))
+			}
+			assert.Equal(t, tt.want, UsesLFS(dir))
+		})
+	}
+}
+
+func TestLFSPull(t *testing.T) {
+	logFile := fakeLFS(t, true)
+	assert.True(t, LFSInstalled())
+
+	worktree := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", worktree).Run())
+
+	require.NoError(t, LFSPull(worktree))
+
+	log, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+	require.GreaterOrEqual(t, len(lines), 2)
+	assert.Equal(t, []string{"fetch", "checkout"}, lines[len(lines)-2:])
+}
+
+func TestLFSPull_NotInstalled(t *testing.T) {
+	fakeLFS(t, false)
+	assert.False(t, LFSInstalled())
+
+	err := LFSPull(t.TempDir())
+
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeGitLFSNotInstalled, faErr.Code)
+}
@@ -0,0 +1,99 @@
+package git_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/foundagent/foundagent/gitexec/gittest"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Failures of commands that reach a remote, answered by a scripted fake so
+// no network, credentials or real repositories are needed
+
+func requireCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, code, faErr.Code, faErr.Message)
+}
+
+func TestPushBranch_NonFastForward(t *testing.T) {
+	fake := gittest.New()
+	fake.On("push").NonFastForward()
+	t.Cleanup(fake.Install())
+
+	err := git.PushBranch("/ws/repos/api/worktrees/main", git.PushOptions{})
+	requireCode(t, err, errors.ErrCodePushFailed)
+	assert.Contains(t, err.Error(), "remote has new commits")
+
+	// A rejected push is not retried
+	assert.Equal(t, 1, fake.Count("push"))
+}
+
+func TestPushBranch_AuthFailure(t *testing.T) {
+	fake := gittest.New()
+	fake.On("push").AuthFailure()
+	t.Cleanup(fake.Install())
+
+	err := git.PushToRemote("/ws/repos/api/worktrees/main", "fork", false)
+	requireCode(t, err, errors.ErrCodeAuthenticationFailed)
+	assert.Equal(t, 1, fake.Count("push"))
+	assert.Equal(t, "git -C /ws/repos/api/worktrees/main push --set-upstream fork HEAD", fake.Calls()[0].String())
+}
+
+func TestPull_Diverged(t *testing.T) {
+	fake := gittest.New()
+	fake.On("pull").Stderr("hint: Diverging branches can't be fast-forwarded, you need to either merge or rebase.\n" +
+		"fatal: Not possible to fast-forward, aborting.\n").Exit(128)
+	t.Cleanup(fake.Install())
+
+	err := git.Pull("/ws/repos/api/worktrees/main")
+	requireCode(t, err, errors.ErrCodeGitOperationFailed)
+	assert.Contains(t, err.Error(), "branches have diverged")
+}
+
+func TestPull_AuthFailure(t *testing.T) {
+	fake := gittest.New()
+	fake.On("pull").AuthFailure()
+	t.Cleanup(fake.Install())
+
+	err := git.Pull("/ws/repos/api/worktrees/main")
+	requireCode(t, err, errors.ErrCodeAuthenticationFailed)
+}
+
+func TestFetch_RetriesTransientFailure(t *testing.T) {
+	fake := gittest.New()
+	fake.On("fetch").Stderr("kex_exchange_identification: read: Connection reset by peer\n" +
+		"fatal: Could not read from remote repository.\n").Exit(128)
+	t.Cleanup(fake.Install())
+
+	git.SetRetryBaseDelay(time.Millisecond)
+	defer git.SetRetryBaseDelay(time.Second)
+
+	var attempts []git.Retry
+	err := git.FetchWithOptions("/ws/repos/api/.bare", git.FetchOptions{OnRetry: func(r git.Retry) { attempts = append(attempts, r) }})
+
+	require.Error(t, err)
+	assert.Equal(t, git.DefaultRetries+1, fake.Count("fetch"))
+	require.Len(t, attempts, git.DefaultRetries)
+	assert.Equal(t, 1, attempts[0].Attempt)
+	assert.Equal(t, 2, attempts[1].Attempt)
+	assert.Equal(t, "kex_exchange_identification: read: Connection reset by peer", attempts[0].Reason)
+}
+
+func TestFetch_DoesNotRetryPermanentFailure(t *testing.T) {
+	fake := gittest.New()
+	fake.On("fetch").AuthFailure()
+	t.Cleanup(fake.Install())
+
+	var attempts []git.Retry
+	err := git.FetchWithOptions("/ws/repos/api/.bare", git.FetchOptions{OnRetry: func(r git.Retry) { attempts = append(attempts, r) }})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, fake.Count("fetch"))
+	assert.Empty(t, attempts)
+}
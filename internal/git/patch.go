@@ -0,0 +1,39 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// Diff returns the changes to a worktree's tracked files since HEAD, staged
+// or not, as a patch that Apply can put back. Binary files are included.
+func Diff(worktreePath string) ([]byte, error) {
+	cmd := command("-C", worktreePath, "diff", "--binary", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to diff worktree changes",
+			"Verify the worktree path is valid",
+			err,
+		)
+	}
+	return output, nil
+}
+
+// Apply applies a patch file made by Diff to a worktree's files. The changes
+// are left unstaged.
+func Apply(worktreePath, patchPath string) error {
+	cmd := command("-C", worktreePath, "apply", patchPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to apply patch: "+strings.TrimSpace(string(output)),
+			"Apply it by hand with 'git apply "+patchPath+"'",
+			err,
+		)
+	}
+	return nil
+}
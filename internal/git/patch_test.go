@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAndApply(t *testing.T) {
+	repoPath := setupTestWorktree(t)
+
+	// A clean worktree has nothing to diff
+	patch, err := Diff(repoPath)
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+
+	// Unstaged and staged changes are both in the patch
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Changed"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "staged.txt"), []byte("staged\n"), 0644))
+	require.NoError(t, exec.Command("git", "-C", repoPath, "add", "staged.txt").Run())
+
+	patch, err = Diff(repoPath)
+	require.NoError(t, err)
+	patchPath := filepath.Join(t.TempDir(), "changes.patch")
+	require.NoError(t, os.WriteFile(patchPath, patch, 0644))
+
+	// Applied to a clean checkout, the patch puts the changes back
+	require.NoError(t, exec.Command("git", "-C", repoPath, "reset", "--hard", "--quiet").Run())
+	require.NoError(t, Apply(repoPath, patchPath))
+
+	data, err := os.ReadFile(filepath.Join(repoPath, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Changed", string(data))
+	assert.FileExists(t, filepath.Join(repoPath, "staged.txt"))
+
+	// Applying it again conflicts with the changes already there
+	assert.Error(t, Apply(repoPath, patchPath))
+}
+
+func TestBranchSHA(t *testing.T) {
+	bareRepo := setupTestBareRepo(t)
+
+	sha, err := BranchSHA(bareRepo, "main")
+	require.NoError(t, err)
+	assert.Len(t, sha, 40)
+	assert.True(t, CommitExists(bareRepo, sha))
+
+	_, err = BranchSHA(bareRepo, "missing")
+	assert.Error(t, err)
+}
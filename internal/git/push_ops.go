@@ -1,7 +1,7 @@
 package git
 
 import (
-	"os/exec"
+	"io"
 	"strconv"
 	"strings"
 
@@ -20,7 +20,7 @@ func HasUnpushedCommits(worktreePath string) (bool, error) {
 // GetUnpushedCount returns the number of commits ahead of upstream
 func GetUnpushedCount(worktreePath string) (int, error) {
 	// First check if there's an upstream
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}")
+	cmd := command("-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}")
 	_, err := cmd.Output()
 	if err != nil {
 		// No upstream configured - check if remote tracking branch exists
@@ -30,13 +30,13 @@ func GetUnpushedCount(worktreePath string) (int, error) {
 		}
 
 		// Check if origin/<branch> exists
-		cmd = exec.Command("git", "-C", worktreePath, "rev-parse", "--verify", "origin/"+branch)
+		cmd = command("-C", worktreePath, "rev-parse", "--verify", "origin/"+branch)
 		if cmd.Run() != nil {
 			return 0, nil // No remote tracking branch
 		}
 
 		// Count commits ahead of origin/<branch>
-		cmd = exec.Command("git", "-C", worktreePath, "rev-list", "--count", "origin/"+branch+"..HEAD")
+		cmd = command("-C", worktreePath, "rev-list", "--count", "origin/"+branch+"..HEAD")
 		output, err := cmd.Output()
 		if err != nil {
 			return 0, nil
@@ -47,10 +47,10 @@ func GetUnpushedCount(worktreePath string) (int, error) {
 	}
 
 	// Has upstream - count commits ahead
-	cmd = exec.Command("git", "-C", worktreePath, "rev-list", "--count", "@{upstream}..HEAD")
+	cmd = command("-C", worktreePath, "rev-list", "--count", "@{upstream}..HEAD")
 	output, err := cmd.Output()
 	if err != nil {
-		return 0, errors.Wrap(
+		return 0, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to count unpushed commits",
 			"Verify branch has upstream tracking",
@@ -65,7 +65,7 @@ func GetUnpushedCount(worktreePath string) (int, error) {
 // GetUnpushedCommits returns the list of unpushed commits (SHA + message)
 func GetUnpushedCommits(worktreePath string) ([]string, error) {
 	// First check if there's an upstream
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}")
+	cmd := command("-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}")
 	_, err := cmd.Output()
 
 	var refRange string
@@ -77,7 +77,7 @@ func GetUnpushedCommits(worktreePath string) ([]string, error) {
 		}
 
 		// Check if origin/<branch> exists
-		cmd = exec.Command("git", "-C", worktreePath, "rev-parse", "--verify", "origin/"+branch)
+		cmd = command("-C", worktreePath, "rev-parse", "--verify", "origin/"+branch)
 		if cmd.Run() != nil {
 			return []string{}, nil
 		}
@@ -87,10 +87,10 @@ func GetUnpushedCommits(worktreePath string) ([]string, error) {
 		refRange = "@{upstream}..HEAD"
 	}
 
-	cmd = exec.Command("git", "-C", worktreePath, "log", "--oneline", refRange)
+	cmd = command("-C", worktreePath, "log", "--oneline", refRange)
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get unpushed commits",
 			"Verify branch has upstream tracking",
@@ -106,58 +106,121 @@ func GetUnpushedCommits(worktreePath string) ([]string, error) {
 	return strings.Split(outputStr, "\n"), nil
 }
 
+// PushOptions represents options for pushing a worktree's current branch
+type PushOptions struct {
+	Remote   string      // Push to the branch of the same name on this remote and track it (empty = the upstream)
+	Force    bool        // Overwrite the remote branch
+	OnRetry  func(Retry) // Called before each retry after a transient network failure
+	Progress io.Writer   // Receives git's progress output (nil = no progress)
+}
+
 // PushWithOptions pushes with additional options
 func PushWithOptions(worktreePath string, force bool) error {
-	args := []string{"-C", worktreePath, "push"}
+	return PushBranch(worktreePath, PushOptions{Force: force})
+}
+
+// PushToRemote pushes the current branch to the branch of the same name on
+// remote and makes that its upstream, so later pushes and pulls use it too
+func PushToRemote(worktreePath, remote string, force bool) error {
+	return PushBranch(worktreePath, PushOptions{Remote: remote, Force: force})
+}
 
-	if force {
+// PushBranch pushes a worktree's current branch, retrying transient network
+// failures
+func PushBranch(worktreePath string, opts PushOptions) error {
+	args := []string{"-C", worktreePath, "push"}
+	if opts.Progress != nil {
+		args = append(args, "--progress")
+	}
+	if opts.Remote != "" {
+		args = append(args, "--set-upstream")
+	}
+	if opts.Force {
 		args = append(args, "--force")
 	}
+	if opts.Remote != "" {
+		args = append(args, opts.Remote, "HEAD")
+	}
 
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
+	cmd := command(args...)
+	cmd.Retryable = true
+	cmd.OnRetry = opts.OnRetry
+	output, err := combinedOutput(cmd, opts.Progress)
 	if err != nil {
-		outputStr := string(output)
-
-		// Check for remote has new commits scenario
-		if strings.Contains(outputStr, "rejected") ||
-			strings.Contains(outputStr, "non-fast-forward") {
-			return errors.New(
-				errors.ErrCodePushFailed,
-				"Push rejected - remote has new commits",
-				"Run 'fa sync --pull' first to update your branch",
-			)
-		}
-
-		// Check for auth errors
-		if strings.Contains(outputStr, "Authentication failed") ||
-			strings.Contains(outputStr, "Permission denied") {
-			return errors.New(
-				errors.ErrCodeAuthenticationFailed,
-				"Git authentication failed",
-				"Check SSH keys or Git credentials",
-			)
-		}
+		return pushError(string(output), err)
+	}
 
-		// Check for no upstream
-		if strings.Contains(outputStr, "no upstream branch") ||
-			strings.Contains(outputStr, "has no upstream") {
-			return errors.New(
-				errors.ErrCodeNoUpstream,
-				"No upstream branch configured",
-				"Set upstream with: git push -u origin <branch>",
-			)
-		}
+	return nil
+}
 
-		return errors.Wrap(
+// pushError classifies a failed push by git's output
+func pushError(outputStr string, err error) error {
+	// Check for remote has new commits scenario
+	if strings.Contains(outputStr, "rejected") ||
+		strings.Contains(outputStr, "non-fast-forward") {
+		return errors.New(
 			errors.ErrCodePushFailed,
-			"Failed to push: "+strings.TrimSpace(outputStr),
-			"Check network connection and remote URL",
+			"Push rejected - remote has new commits",
+			"Run 'fa sync --pull' first to update your branch",
+		)
+	}
+
+	// Check for auth errors
+	if strings.Contains(outputStr, "Authentication failed") ||
+		strings.Contains(outputStr, "Permission denied") {
+		return errors.New(
+			errors.ErrCodeAuthenticationFailed,
+			"Git authentication failed",
+			"Check SSH keys or Git credentials",
+		)
+	}
+
+	// Check for no upstream
+	if strings.Contains(outputStr, "no upstream branch") ||
+		strings.Contains(outputStr, "has no upstream") {
+		return errors.New(
+			errors.ErrCodeNoUpstream,
+			"No upstream branch configured",
+			"Set upstream with: git push -u origin <branch>",
+		)
+	}
+
+	return wrap(
+		errors.ErrCodePushFailed,
+		"Failed to push: "+strings.TrimSpace(outputStr),
+		"Check network connection and remote URL",
+		err,
+	)
+}
+
+// GetUnpushedCountTo returns the number of commits not yet on the current
+// branch's namesake on remote. A branch never pushed there counts the commits
+// ahead of its upstream, e.g. the upstream/main it was created from.
+func GetUnpushedCountTo(worktreePath, remote string) (int, error) {
+	branch, err := GetCurrentBranch(worktreePath)
+	if err != nil || branch == "" {
+		return 0, nil // Detached HEAD or error
+	}
+
+	remoteBranch := remote + "/" + branch
+	cmd := command("-C", worktreePath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+remoteBranch)
+	if cmd.Run() != nil {
+		return GetUnpushedCount(worktreePath)
+	}
+
+	cmd = command("-C", worktreePath, "rev-list", "--count", remoteBranch+"..HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to count unpushed commits",
+			"Check the branch with 'git status'",
 			err,
 		)
 	}
 
-	return nil
+	count, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+	return count, nil
 }
 
 // GetPushRefspec returns the refspec that would be pushed (e.g., "main -> origin/main")
@@ -172,11 +235,11 @@ func GetPushRefspec(worktreePath string) (string, error) {
 
 // HasUpstreamConfigured checks if the current branch has an upstream configured
 func HasUpstreamConfigured(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}")
+	cmd := command("-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}")
 	err := cmd.Run()
 	if err != nil {
 		// Check if it's just "no upstream" vs actual error
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+		if exitCode(err) == 128 {
 			return false, nil
 		}
 		return false, nil // Treat errors as "no upstream"
@@ -269,3 +269,39 @@ func TestGetUnpushedCountNoRemote(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 0, count)
 }
+
+func TestPushToRemote_Fork(t *testing.T) {
+	bareRepo, _ := setupForkTestRepo(t)
+	require.NoError(t, FetchWithOptions(bareRepo, FetchOptions{}))
+	require.NoError(t, FetchWithOptions(bareRepo, FetchOptions{Remote: "upstream"}))
+
+	// Branch off the canonical repo and commit once
+	worktree := filepath.Join(t.TempDir(), "feature")
+	require.NoError(t, WorktreeAddNew(bareRepo, worktree, "feature", "upstream/main"))
+	for _, args := range [][]string{
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"commit", "--allow-empty", "-m", "Feature"},
+	} {
+		require.NoError(t, exec.Command("git", append([]string{"-C", worktree}, args...)...).Run())
+	}
+
+	// Never pushed to the fork: counts the commits ahead of upstream/main
+	count, err := GetUnpushedCountTo(worktree, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, PushToRemote(worktree, "origin", false))
+	assert.True(t, RemoteBranchExists(bareRepo, "origin/feature"))
+
+	out, err := exec.Command("git", "-C", worktree, "rev-parse", "--abbrev-ref", "@{upstream}").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "origin/feature", string(out[:len(out)-1]))
+
+	count, err = GetUnpushedCountTo(worktree, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	err = PushToRemote(worktree, "missing", false)
+	require.Error(t, err)
+}
@@ -1,8 +1,10 @@
 package git
 
 import (
+	"bytes"
 	"fmt"
-	"os/exec"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/foundagent/foundagent/internal/errors"
@@ -11,13 +13,13 @@ import (
 // GetDefaultBranch retrieves the default branch from a bare repository
 func GetDefaultBranch(bareRepoPath string) (string, error) {
 	// Try to get the symbolic ref for HEAD
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+	cmd := command("symbolic-ref", "refs/remotes/origin/HEAD")
 	cmd.Dir = bareRepoPath
 
 	output, err := cmd.Output()
 	if err != nil {
 		// If symbolic-ref fails, try to get the default branch from remote
-		cmd = exec.Command("git", "remote", "show", "origin")
+		cmd = command("remote", "show", "origin")
 		cmd.Dir = bareRepoPath
 
 		output, err = cmd.Output()
@@ -56,14 +58,48 @@ func GetDefaultBranch(bareRepoPath string) (string, error) {
 	return "main", nil
 }
 
-// ListRemoteBranches lists all remote branches
+// RemoteDefaultBranch asks the remote at url for its default branch, for
+// when there is no clone to ask GetDefaultBranch. Like it, it falls back to
+// "main" when the remote does not say.
+func RemoteDefaultBranch(url string) (string, error) {
+	cmd := command("ls-remote", "--symref", url, "HEAD")
+	cmd.Retryable = true
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrap(
+			errors.ErrCodeGitOperationFailed,
+			fmt.Sprintf("Failed to read the default branch of %s", url),
+			"Check the URL and your access to the repository",
+			err,
+		)
+	}
+
+	// Parse "ref: refs/heads/main	HEAD"
+	for _, line := range strings.Split(string(output), "\n") {
+		if ref, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			if branch, _, _ := strings.Cut(ref, "\t"); branch != "" {
+				return branch, nil
+			}
+		}
+	}
+	return "main", nil
+}
+
+// ListRemoteBranches lists the branches of every remote, without the remote
+// name prefix. A branch on several remotes is listed once.
 func ListRemoteBranches(bareRepoPath string) ([]string, error) {
-	cmd := exec.Command("git", "branch", "-r")
+	remotes, err := ListRemotes(bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := command("for-each-ref", "--format=%(refname)", "refs/remotes/")
 	cmd.Dir = bareRepoPath
 
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to list remote branches",
 			"Ensure the repository is valid",
@@ -71,32 +107,147 @@ func ListRemoteBranches(bareRepoPath string) ([]string, error) {
 		)
 	}
 
+	// Remote names may contain slashes, so match the longest remote first
+	sort.Slice(remotes, func(i, j int) bool { return len(remotes[i]) > len(remotes[j]) })
+
 	var branches []string
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.Contains(line, "->") {
-			continue
-		}
-		// Remove "origin/" prefix
-		if strings.HasPrefix(line, "origin/") {
-			branches = append(branches, line[7:])
+	seen := make(map[string]bool)
+	for _, ref := range strings.Split(string(output), "\n") {
+		ref = strings.TrimSpace(ref)
+		for _, remote := range remotes {
+			branch, ok := strings.CutPrefix(ref, "refs/remotes/"+remote+"/")
+			if !ok {
+				continue
+			}
+			if branch != "HEAD" && !seen[branch] {
+				seen[branch] = true
+				branches = append(branches, branch)
+			}
+			break
 		}
 	}
 
 	return branches, nil
 }
 
+// ListRemotes returns the names of a repository's remotes
+func ListRemotes(repoPath string) ([]string, error) {
+	cmd := command("remote")
+	cmd.Dir = repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to list remotes",
+			"Ensure the repository is valid",
+			err,
+		)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// SetRemote adds a remote, or points an existing one at url. Added remotes
+// fetch every branch into refs/remotes/<name>/.
+func SetRemote(repoPath, name, url string) error {
+	cmd := command("-C", repoPath, "remote", "get-url", name)
+	current, err := cmd.Output()
+
+	var args []string
+	switch {
+	case err != nil:
+		args = []string{"-C", repoPath, "remote", "add", name, url}
+	case strings.TrimSpace(string(current)) != url:
+		args = []string{"-C", repoPath, "remote", "set-url", name, url}
+	default:
+		return nil
+	}
+
+	cmd = command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			fmt.Sprintf("Failed to set remote %s: %s", name, strings.TrimSpace(string(output))),
+			"Check the remote name and URL in the config",
+			err,
+		)
+	}
+	return nil
+}
+
+// TrackRemoteBranches makes fetches from a remote update refs/remotes/<name>/.
+// Bare clones fetch origin without such a refspec, so branches cannot be
+// created from origin/<branch> until this is set.
+func TrackRemoteBranches(repoPath, remote string) error {
+	cmd := command("-C", repoPath, "config", "--get-all", "remote."+remote+".fetch")
+	if output, err := cmd.Output(); err == nil && strings.TrimSpace(string(output)) != "" {
+		return nil
+	}
+
+	refspec := fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remote)
+	cmd = command("-C", repoPath, "config", "--add", "remote."+remote+".fetch", refspec)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			fmt.Sprintf("Failed to configure fetching from %s: %s", remote, strings.TrimSpace(string(output))),
+			"Check the repository's git config",
+			err,
+		)
+	}
+	return nil
+}
+
+// FetchOptions represents options for fetching from a remote
+type FetchOptions struct {
+	Remote string // Remote to fetch from (empty = origin)
+	Depth  int    // Keep history truncated to this many commits (0 = no limit)
+	Filter string // Partial clone filter, e.g. blob:none
+	Branch string // Fetch only this branch (empty = the remote's default refs)
+
+	OnRetry  func(Retry) // Called before each retry after a transient network failure
+	Progress io.Writer   // Receives git's progress output (nil = no progress)
+}
+
 // Fetch fetches from origin remote
 func Fetch(repoPath string) error {
-	cmd := exec.Command("git", "fetch", "origin")
+	return FetchWithOptions(repoPath, FetchOptions{})
+}
+
+// FetchWithOptions fetches from a remote, keeping a shallow, partial or
+// single-branch clone in the shape it was cloned with. Transient network
+// failures are retried.
+func FetchWithOptions(repoPath string, opts FetchOptions) error {
+	remote := opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	args := []string{"fetch"}
+	if opts.Progress != nil {
+		args = append(args, "--progress")
+	}
+	if opts.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	}
+	args = append(args, remote)
+	if opts.Branch != "" {
+		args = append(args, opts.Branch)
+	}
+
+	cmd := command(args...)
 	cmd.Dir = repoPath
+	cmd.Retryable = true
+	cmd.OnRetry = opts.OnRetry
 
-	output, err := cmd.CombinedOutput()
+	output, err := combinedOutput(cmd, opts.Progress)
 	if err != nil {
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeNetworkError,
-			"Failed to fetch from remote",
+			fmt.Sprintf("Failed to fetch from %s", remote),
 			"Check network connection and remote URL",
 			err,
 		)
@@ -116,10 +267,41 @@ func Fetch(repoPath string) error {
 	return nil
 }
 
+// combinedOutput runs cmd and returns its standard output and standard
+// error, also copying standard error to progress if it is set
+func combinedOutput(cmd *Cmd, progress io.Writer) ([]byte, error) {
+	if progress == nil {
+		return cmd.CombinedOutput()
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = io.MultiWriter(&output, progress)
+	err := cmd.Run()
+	return output.Bytes(), err
+}
+
+// IsShallow reports whether a repository has truncated history
+func IsShallow(repoPath string) bool {
+	cmd := command("-C", repoPath, "rev-parse", "--is-shallow-repository")
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// PartialCloneFilter returns the filter a partial clone was made with, or ""
+// if the repository is not a partial clone
+func PartialCloneFilter(repoPath string) string {
+	cmd := command("-C", repoPath, "config", "--get", "remote.origin.partialclonefilter")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // Pull performs a fast-forward pull on a worktree
 func Pull(worktreePath string) error {
 	// Use --ff-only to ensure fast-forward only
-	cmd := exec.Command("git", "-C", worktreePath, "pull", "--ff-only")
+	cmd := command("-C", worktreePath, "pull", "--ff-only")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		outputStr := string(output)
@@ -144,7 +326,7 @@ func Pull(worktreePath string) error {
 			)
 		}
 
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to pull",
 			"Ensure worktree is clean and branch tracking is set up",
@@ -157,7 +339,8 @@ func Pull(worktreePath string) error {
 
 // Push pushes local commits to remote
 func Push(worktreePath string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "push")
+	cmd := command("-C", worktreePath, "push")
+	cmd.Retryable = true
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		outputStr := string(output)
@@ -182,7 +365,7 @@ func Push(worktreePath string) error {
 			)
 		}
 
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeNetworkError,
 			"Failed to push to remote",
 			"Check network connection and remote URL",
@@ -196,7 +379,7 @@ func Push(worktreePath string) error {
 // GetAheadBehindCount returns the number of commits ahead and behind remote
 func GetAheadBehindCount(worktreePath, branch string) (ahead int, behind int, err error) {
 	// Get tracking branch
-	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", branch+"@{upstream}")
+	cmd := command("-C", worktreePath, "rev-parse", "--abbrev-ref", branch+"@{upstream}")
 	output, cmdErr := cmd.Output()
 	if cmdErr != nil {
 		// No tracking branch set up
@@ -206,10 +389,10 @@ func GetAheadBehindCount(worktreePath, branch string) (ahead int, behind int, er
 	upstream := strings.TrimSpace(string(output))
 
 	// Get ahead/behind counts
-	cmd = exec.Command("git", "-C", worktreePath, "rev-list", "--left-right", "--count", branch+"..."+upstream)
+	cmd = command("-C", worktreePath, "rev-list", "--left-right", "--count", branch+"..."+upstream)
 	output, cmdErr = cmd.Output()
 	if cmdErr != nil {
-		return 0, 0, errors.Wrap(
+		return 0, 0, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get ahead/behind count",
 			"Ensure branch has upstream tracking",
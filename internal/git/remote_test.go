@@ -4,8 +4,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -73,6 +75,17 @@ func TestGetDefaultBranch(t *testing.T) {
 	}
 }
 
+func TestRemoteDefaultBranch(t *testing.T) {
+	_, remoteRepo := setupRemoteTestRepo(t)
+
+	branch, err := RemoteDefaultBranch(remoteRepo)
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+
+	_, err = RemoteDefaultBranch(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
 func TestListRemoteBranches(t *testing.T) {
 	workRepo, _ := setupRemoteTestRepo(t)
 
@@ -131,6 +144,68 @@ func TestFetch_InvalidRepo(t *testing.T) {
 	}
 }
 
+// commitAndPush adds a commit to workRepo and pushes it to origin
+func commitAndPush(t *testing.T, workRepo, file string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(workRepo, file), []byte(file), 0644))
+	require.NoError(t, exec.Command("git", "-C", workRepo, "add", ".").Run())
+	require.NoError(t, exec.Command("git", "-C", workRepo, "commit", "-m", "Add "+file).Run())
+	require.NoError(t, exec.Command("git", "-C", workRepo, "push", "origin", "HEAD").Run())
+}
+
+func TestShallowAndPartialClone(t *testing.T) {
+	workRepo, remoteRepo := setupRemoteTestRepo(t)
+	commitAndPush(t, workRepo, "second.txt")
+	require.NoError(t, exec.Command("git", "-C", workRepo, "push", "origin", "main:other").Run())
+
+	// Depth and filter are ignored for plain paths, so clone over file://
+	bareRepo := filepath.Join(t.TempDir(), "shallow.git")
+	err := Clone(CloneOptions{
+		URL:          "file://" + remoteRepo,
+		TargetPath:   bareRepo,
+		Bare:         true,
+		Depth:        1,
+		Filter:       "blob:none",
+		SingleBranch: true,
+	})
+	require.NoError(t, err)
+
+	if !IsShallow(bareRepo) {
+		t.Error("IsShallow() = false for a --depth clone")
+	}
+	if got := PartialCloneFilter(bareRepo); got != "blob:none" {
+		t.Errorf("PartialCloneFilter() = %q, want blob:none", got)
+	}
+
+	branches, err := exec.Command("git", "-C", bareRepo, "branch", "--format=%(refname:short)").Output()
+	require.NoError(t, err)
+	if got := string(branches); got != "main\n" {
+		t.Errorf("single-branch clone has branches %q, want only main", got)
+	}
+
+	// A fetch with the same options keeps the clone shallow
+	commitAndPush(t, workRepo, "third.txt")
+	err = FetchWithOptions(bareRepo, FetchOptions{Depth: 1, Filter: "blob:none", Branch: "main"})
+	require.NoError(t, err)
+	if !IsShallow(bareRepo) {
+		t.Error("FetchWithOptions() deepened a shallow clone")
+	}
+}
+
+func TestIsShallow_FullClone(t *testing.T) {
+	_, remoteRepo := setupRemoteTestRepo(t)
+
+	bareRepo := filepath.Join(t.TempDir(), "full.git")
+	require.NoError(t, CloneBare("file://"+remoteRepo, bareRepo, false))
+
+	if IsShallow(bareRepo) {
+		t.Error("IsShallow() = true for a full clone")
+	}
+	if got := PartialCloneFilter(bareRepo); got != "" {
+		t.Errorf("PartialCloneFilter() = %q for a full clone", got)
+	}
+}
+
 func TestPull(t *testing.T) {
 	workRepo, remoteRepo := setupRemoteTestRepo(t)
 
@@ -382,3 +457,75 @@ func TestListRemoteBranches_InvalidRepo(t *testing.T) {
 		t.Error("ListRemoteBranches() should return error for invalid repo")
 	}
 }
+
+// setupForkTestRepo returns a bare clone of a fork whose canonical repo is
+// added as the upstream remote, and a work repo pushing to that canonical repo
+func setupForkTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+	workRepo, canonical := setupRemoteTestRepo(t)
+
+	fork := filepath.Join(t.TempDir(), "fork.git")
+	require.NoError(t, exec.Command("git", "clone", "--bare", "--quiet", canonical, fork).Run())
+	commitAndPush(t, workRepo, "upstream-only.txt")
+
+	bareRepo := filepath.Join(t.TempDir(), "clone.git")
+	require.NoError(t, CloneBare(fork, bareRepo, false))
+	require.NoError(t, SetRemote(bareRepo, "upstream", canonical))
+	require.NoError(t, TrackRemoteBranches(bareRepo, "origin"))
+
+	return bareRepo, workRepo
+}
+
+func TestSetRemote(t *testing.T) {
+	bareRepo, _ := setupForkTestRepo(t)
+
+	remotes, err := ListRemotes(bareRepo)
+	require.NoError(t, err)
+	require.Equal(t, []string{"origin", "upstream"}, remotes)
+
+	// Setting the same URL again is a no-op, a new URL replaces the old one
+	out, err := exec.Command("git", "-C", bareRepo, "remote", "get-url", "upstream").Output()
+	require.NoError(t, err)
+	require.NoError(t, SetRemote(bareRepo, "upstream", strings.TrimSpace(string(out))))
+	require.NoError(t, SetRemote(bareRepo, "upstream", "https://example.com/org/repo.git"))
+	out, err = exec.Command("git", "-C", bareRepo, "remote", "get-url", "upstream").Output()
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/org/repo.git", strings.TrimSpace(string(out)))
+
+	// The origin refspec is only added once
+	require.NoError(t, TrackRemoteBranches(bareRepo, "origin"))
+	out, err = exec.Command("git", "-C", bareRepo, "config", "--get-all", "remote.origin.fetch").Output()
+	require.NoError(t, err)
+	require.Equal(t, "+refs/heads/*:refs/remotes/origin/*", strings.TrimSpace(string(out)))
+}
+
+func TestFetchWithOptions_Remotes(t *testing.T) {
+	bareRepo, _ := setupForkTestRepo(t)
+
+	require.False(t, RemoteBranchExists(bareRepo, "upstream/main"))
+	require.NoError(t, FetchWithOptions(bareRepo, FetchOptions{}))
+	require.NoError(t, FetchWithOptions(bareRepo, FetchOptions{Remote: "upstream"}))
+	require.True(t, RemoteBranchExists(bareRepo, "origin/main"))
+	require.True(t, RemoteBranchExists(bareRepo, "upstream/main"))
+	require.False(t, RemoteBranchExists(bareRepo, "upstream/nope"))
+
+	// upstream is a commit ahead of the fork
+	count, err := exec.Command("git", "--git-dir="+bareRepo, "rev-list", "--count", "origin/main..upstream/main").Output()
+	require.NoError(t, err)
+	require.Equal(t, "1", strings.TrimSpace(string(count)))
+
+	err = FetchWithOptions(bareRepo, FetchOptions{Remote: "missing"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Failed to fetch from missing")
+}
+
+func TestListRemoteBranches_AllRemotes(t *testing.T) {
+	bareRepo, workRepo := setupForkTestRepo(t)
+	require.NoError(t, exec.Command("git", "-C", workRepo, "push", "origin", "main:release").Run())
+	require.NoError(t, FetchWithOptions(bareRepo, FetchOptions{}))
+	require.NoError(t, FetchWithOptions(bareRepo, FetchOptions{Remote: "upstream"}))
+
+	branches, err := ListRemoteBranches(bareRepo)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"main", "release"}, branches)
+}
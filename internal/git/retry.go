@@ -0,0 +1,125 @@
+package git
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultRetries is how many times a clone, fetch or push that failed for a
+// transient network reason is retried unless configured otherwise
+const DefaultRetries = 2
+
+// maxRetryDelay caps the backoff between attempts
+const maxRetryDelay = 30 * time.Second
+
+var (
+	// retries is how many times network commands started from now on are
+	// retried
+	retries = DefaultRetries
+
+	// retryBaseDelay is the delay before the first retry; it doubles with
+	// every retry after that
+	retryBaseDelay = time.Second
+)
+
+// Retry describes a retry of a git command after a transient failure
+type Retry struct {
+	Attempt int           `json:"attempt"` // 1 for the first retry
+	Delay   time.Duration `json:"-"`
+	DelayMs int64         `json:"delay_ms"`
+	Reason  string        `json:"reason"` // The line of git's output that made it retry
+}
+
+// String describes the retry for verbose output
+func (r Retry) String() string {
+	return fmt.Sprintf("retry %d after %s: %s", r.Attempt, r.Delay.Round(100*time.Millisecond), r.Reason)
+}
+
+// SetRetries sets how many times clones, fetches and pushes started from now
+// on are retried after a transient failure. Zero turns retrying off.
+func SetRetries(n int) {
+	retries = max(n, 0)
+}
+
+// permanentFailures are git messages for failures that retrying cannot fix,
+// even when a transient-looking message comes with them
+var permanentFailures = []string{
+	"authentication failed",
+	"permission denied",
+	"could not read username",
+	"repository not found",
+	"does not appear to be a git repository",
+	"[rejected]",
+	"non-fast-forward",
+}
+
+// transientFailures are git, curl and ssh messages for network failures that
+// often succeed when tried again
+var transientFailures = []string{
+	"connection reset",
+	"connection timed out",
+	"operation timed out",
+	"temporary failure in name resolution",
+	"the remote end hung up unexpectedly",
+	"early eof",
+	"unexpected disconnect",
+	"rpc failed",
+	"broken pipe",
+	"ssl_read",
+	"gnutls",
+	"tls connection was non-properly terminated",
+	"internal server error",
+	"bad gateway",
+	"service unavailable",
+	"gateway time-out",
+	"gateway timeout",
+	"too many requests",
+}
+
+// httpStatus matches the HTTP status codes git and curl report for server
+// errors and rate limiting, e.g. "The requested URL returned error: 503"
+var httpStatus = regexp.MustCompile(`(?i)(?:http|returned error:?)\s+(?:5\d\d|429)\b`)
+
+// transientFailure returns the line of git's output that shows a command
+// failed for a transient network reason, or "" if retrying would not help
+func transientFailure(output []byte) string {
+	text := strings.ToLower(string(output))
+	for _, msg := range permanentFailures {
+		if strings.Contains(text, msg) {
+			return ""
+		}
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		// Progress output overwrites itself with carriage returns
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		lower := strings.ToLower(line)
+		if httpStatus.MatchString(line) {
+			return strings.TrimSpace(line)
+		}
+		for _, msg := range transientFailures {
+			if strings.Contains(lower, msg) {
+				return strings.TrimSpace(line)
+			}
+		}
+	}
+	return ""
+}
+
+// retryDelay returns how long to wait before the given retry: exponential
+// backoff from retryBaseDelay, capped at maxRetryDelay, with jitter so repos
+// that failed together do not retry together
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxRetryDelay)
+	half := delay / 2
+	return half + rand.N(half+1)
+}
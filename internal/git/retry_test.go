@@ -0,0 +1,50 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransientFailure(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"error: RPC failed; HTTP 502 curl 22 The requested URL returned error: 502\nfatal: expected flush after ref listing\n",
+			"error: RPC failed; HTTP 502 curl 22 The requested URL returned error: 502"},
+		{"fatal: unable to access 'https://host/repo.git/': The requested URL returned error: 503\n",
+			"fatal: unable to access 'https://host/repo.git/': The requested URL returned error: 503"},
+		{"Receiving objects:  40% (4/10)\rkex_exchange_identification: read: Connection reset by peer\n",
+			"kex_exchange_identification: read: Connection reset by peer"},
+		{"fatal: the remote end hung up unexpectedly\n", "fatal: the remote end hung up unexpectedly"},
+		{"fatal: unable to access 'https://host/': Could not resolve host: host\n", ""},
+		{"git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.\n", ""},
+		{"remote: Repository not found.\nfatal: the remote end hung up unexpectedly\n", ""},
+		{" ! [rejected]        main -> main (non-fast-forward)\n", ""},
+		{"fatal: unable to access 'https://host/repo.git/': The requested URL returned error: 404\n", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, transientFailure([]byte(tt.output)), "%q", tt.output)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt, base := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 10: maxRetryDelay} {
+		delay := retryDelay(attempt)
+		assert.GreaterOrEqual(t, delay, base/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, base, "attempt %d", attempt)
+	}
+}
+
+func TestSetRetries(t *testing.T) {
+	defer SetRetries(DefaultRetries)
+
+	SetRetries(-1)
+	assert.Equal(t, 0, retries)
+
+	SetRetries(5)
+	assert.Equal(t, 5, retries)
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// SparseCheckoutSet restricts a worktree to the given directories in cone
+// mode and updates the working tree to match. An empty list leaves only the
+// files at the top of the repository.
+func SparseCheckoutSet(worktreePath string, paths []string) error {
+	args := append([]string{"-C", worktreePath, "sparse-checkout", "set", "--cone"}, paths...)
+
+	cmd := command(args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to set sparse checkout: "+strings.TrimSpace(string(output)),
+			"Check that the paths are directories in the repository and the worktree has no conflicting changes",
+			err,
+		)
+	}
+	return nil
+}
+
+// SparseCheckoutAdd adds directories to a sparse worktree
+func SparseCheckoutAdd(worktreePath string, paths []string) error {
+	args := append([]string{"-C", worktreePath, "sparse-checkout", "add"}, paths...)
+
+	cmd := command(args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to add sparse checkout paths: "+strings.TrimSpace(string(output)),
+			"Check that the paths are directories in the repository",
+			err,
+		)
+	}
+	return nil
+}
+
+// SparseCheckoutList returns the directories a sparse worktree checks out
+func SparseCheckoutList(worktreePath string) ([]string, error) {
+	cmd := command("-C", worktreePath, "sparse-checkout", "list")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to list sparse checkout paths",
+			"Check that the worktree uses sparse checkout",
+			err,
+		)
+	}
+
+	paths := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// IsSparse reports whether a worktree has sparse checkout enabled
+func IsSparse(worktreePath string) bool {
+	cmd := command("-C", worktreePath, "config", "--get", "--bool", "core.sparseCheckout")
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// checkoutSparse applies sparse paths to a worktree created with --no-checkout
+// and then checks out only those paths
+func checkoutSparse(worktreePath string, paths []string) error {
+	if err := SparseCheckoutSet(worktreePath, paths); err != nil {
+		return err
+	}
+
+	cmd := command("-C", worktreePath, "checkout")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to check out sparse worktree: "+strings.TrimSpace(string(output)),
+			"Check the worktree with 'git status'",
+			err,
+		)
+	}
+	return nil
+}
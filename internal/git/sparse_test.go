@@ -0,0 +1,148 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// setupSparseTestRepo creates a bare repo whose main branch has several
+// top-level directories
+func setupSparseTestRepo(t *testing.T) string {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	bareRepo := filepath.Join(tmpDir, "sparse-repo.git")
+	workDir := filepath.Join(tmpDir, "work")
+
+	if err := exec.Command("git", "init", "--bare", bareRepo).Run(); err != nil {
+		t.Fatalf("Failed to create bare repo: %v", err)
+	}
+
+	exec.Command("git", "init", workDir).Run()
+	exec.Command("git", "-C", workDir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", workDir, "config", "user.name", "Test User").Run()
+
+	for _, file := range []string{"README.md", "api/main.go", "web/index.html", "libs/common/util.go"} {
+		path := filepath.Join(workDir, file)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(file), 0644)
+	}
+
+	exec.Command("git", "-C", workDir, "add", ".").Run()
+	exec.Command("git", "-C", workDir, "commit", "-m", "Initial commit").Run()
+	exec.Command("git", "-C", workDir, "branch", "-M", "main").Run()
+	exec.Command("git", "-C", workDir, "remote", "add", "origin", bareRepo).Run()
+	if err := exec.Command("git", "-C", workDir, "push", "-u", "origin", "main").Run(); err != nil {
+		t.Fatalf("Failed to push: %v", err)
+	}
+
+	return bareRepo
+}
+
+func assertExists(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("%s should be checked out", path)
+		}
+	}
+}
+
+func assertMissing(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+			t.Errorf("%s should not be checked out", path)
+		}
+	}
+}
+
+func TestWorktreeAddNew_Sparse(t *testing.T) {
+	bareRepo := setupSparseTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(bareRepo), "wt-sparse")
+
+	if err := WorktreeAddNew(bareRepo, worktreePath, "feature", "main", "api"); err != nil {
+		t.Fatalf("WorktreeAddNew() error = %v", err)
+	}
+
+	assertExists(t, worktreePath, "README.md", "api/main.go")
+	assertMissing(t, worktreePath, "web", "libs")
+
+	if !IsSparse(worktreePath) {
+		t.Error("IsSparse() = false, want true")
+	}
+
+	paths, err := SparseCheckoutList(worktreePath)
+	if err != nil {
+		t.Fatalf("SparseCheckoutList() error = %v", err)
+	}
+	if !reflect.DeepEqual(paths, []string{"api"}) {
+		t.Errorf("SparseCheckoutList() = %v, want [api]", paths)
+	}
+
+	clean, err := HasUncommittedChanges(worktreePath)
+	if err != nil || clean {
+		t.Errorf("Sparse worktree should be clean, HasUncommittedChanges() = %v, %v", clean, err)
+	}
+}
+
+func TestWorktreeAdd_Sparse(t *testing.T) {
+	bareRepo := setupSparseTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(bareRepo), "wt-main")
+
+	err := WorktreeAdd(WorktreeAddOptions{
+		BareRepoPath: bareRepo,
+		WorktreePath: worktreePath,
+		Branch:       "main",
+		Sparse:       []string{"libs/common"},
+	})
+	if err != nil {
+		t.Fatalf("WorktreeAdd() error = %v", err)
+	}
+
+	assertExists(t, worktreePath, "README.md", "libs/common/util.go")
+	assertMissing(t, worktreePath, "api", "web")
+}
+
+func TestWorktreeAddNew_NotSparse(t *testing.T) {
+	bareRepo := setupSparseTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(bareRepo), "wt-full")
+
+	if err := WorktreeAddNew(bareRepo, worktreePath, "feature", "main"); err != nil {
+		t.Fatalf("WorktreeAddNew() error = %v", err)
+	}
+
+	assertExists(t, worktreePath, "api", "web", "libs")
+	if IsSparse(worktreePath) {
+		t.Error("IsSparse() = true for a full worktree")
+	}
+}
+
+func TestSparseCheckoutAddAndSet(t *testing.T) {
+	bareRepo := setupSparseTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(bareRepo), "wt-adjust")
+
+	if err := WorktreeAddDetached(bareRepo, worktreePath, "main", "api"); err != nil {
+		t.Fatalf("WorktreeAddDetached() error = %v", err)
+	}
+
+	if err := SparseCheckoutAdd(worktreePath, []string{"web"}); err != nil {
+		t.Fatalf("SparseCheckoutAdd() error = %v", err)
+	}
+	assertExists(t, worktreePath, "api", "web")
+
+	if err := SparseCheckoutSet(worktreePath, []string{"web"}); err != nil {
+		t.Fatalf("SparseCheckoutSet() error = %v", err)
+	}
+	assertExists(t, worktreePath, "web")
+	assertMissing(t, worktreePath, "api")
+
+	if err := SparseCheckoutSet(worktreePath, nil); err != nil {
+		t.Fatalf("SparseCheckoutSet() with no paths error = %v", err)
+	}
+	assertExists(t, worktreePath, "README.md")
+	assertMissing(t, worktreePath, "web")
+}
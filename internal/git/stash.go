@@ -1,7 +1,6 @@
 package git
 
 import (
-	"os/exec"
 	"strings"
 
 	"github.com/foundagent/foundagent/internal/errors"
@@ -9,10 +8,10 @@ import (
 
 // Stash saves uncommitted changes to the stash
 func Stash(worktreePath string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "stash", "push", "-m", "Foundagent auto-stash before sync")
+	cmd := command("-C", worktreePath, "stash", "push", "-m", "Foundagent auto-stash before sync")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to stash changes",
 			"Check that the worktree has valid uncommitted changes",
@@ -31,7 +30,7 @@ func Stash(worktreePath string) error {
 
 // StashPop restores the most recent stashed changes
 func StashPop(worktreePath string) error {
-	cmd := exec.Command("git", "-C", worktreePath, "stash", "pop")
+	cmd := command("-C", worktreePath, "stash", "pop")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		outputStr := string(output)
@@ -45,7 +44,7 @@ func StashPop(worktreePath string) error {
 			)
 		}
 
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to pop stash",
 			"Check stash list with 'git stash list'",
@@ -58,10 +57,10 @@ func StashPop(worktreePath string) error {
 
 // HasStash checks if there are any stashed changes
 func HasStash(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "stash", "list")
+	cmd := command("-C", worktreePath, "stash", "list")
 	output, err := cmd.Output()
 	if err != nil {
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check stash",
 			"Verify the worktree path is valid",
@@ -1,7 +1,6 @@
 package git
 
 import (
-	"os/exec"
 	"strings"
 
 	"github.com/foundagent/foundagent/internal/errors"
@@ -10,10 +9,10 @@ import (
 // HasUncommittedChanges checks if a worktree has uncommitted changes
 func HasUncommittedChanges(worktreePath string) (bool, error) {
 	// Check for staged and unstaged changes
-	cmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
+	cmd := command("-C", worktreePath, "status", "--porcelain")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check git status",
 			"Verify the worktree path is valid",
@@ -36,10 +35,10 @@ func IsClean(worktreePath string) (bool, error) {
 
 // HasUntrackedFiles checks if a worktree has untracked files
 func HasUntrackedFiles(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "ls-files", "--others", "--exclude-standard")
+	cmd := command("-C", worktreePath, "ls-files", "--others", "--exclude-standard")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check for untracked files",
 			"Verify the worktree path is valid",
@@ -52,10 +51,10 @@ func HasUntrackedFiles(worktreePath string) (bool, error) {
 
 // HasConflicts checks if a worktree has merge conflicts
 func HasConflicts(worktreePath string) (bool, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff", "--name-only", "--diff-filter=U")
+	cmd := command("-C", worktreePath, "diff", "--name-only", "--diff-filter=U")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return false, errors.Wrap(
+		return false, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to check for merge conflicts",
 			"Verify the worktree path is valid",
@@ -68,10 +67,10 @@ func HasConflicts(worktreePath string) (bool, error) {
 
 // GetModifiedFiles returns a list of modified files
 func GetModifiedFiles(worktreePath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "diff", "--name-only", "HEAD")
+	cmd := command("-C", worktreePath, "diff", "--name-only", "HEAD")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get modified files",
 			"Verify the worktree path is valid",
@@ -89,10 +88,10 @@ func GetModifiedFiles(worktreePath string) ([]string, error) {
 
 // GetUntrackedFiles returns a list of untracked files
 func GetUntrackedFiles(worktreePath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", worktreePath, "ls-files", "--others", "--exclude-standard")
+	cmd := command("-C", worktreePath, "ls-files", "--others", "--exclude-standard")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to get untracked files",
 			"Verify the worktree path is valid",
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// Submodule states reported by SubmoduleStatuses
+const (
+	SubmoduleInSync        = "in-sync"
+	SubmoduleUninitialized = "uninitialized"
+	SubmoduleDrifted       = "drifted"
+	SubmoduleConflict      = "conflict"
+)
+
+// SubmoduleStatus describes one submodule of a worktree
+type SubmoduleStatus struct {
+	Path   string `json:"path"`
+	Commit string `json:"commit"`
+	State  string `json:"state"`
+}
+
+// InSync reports whether the submodule is checked out at the commit its
+// parent records
+func (s SubmoduleStatus) InSync() bool {
+	return s.State == SubmoduleInSync
+}
+
+// HasSubmodules reports whether a worktree declares submodules
+func HasSubmodules(worktreePath string) bool {
+	_, err := os.Stat(filepath.Join(worktreePath, ".gitmodules"))
+	return err == nil
+}
+
+// SubmoduleUpdate initialises and checks out every submodule of a worktree,
+// recursively, at the commits the worktree records
+func SubmoduleUpdate(worktreePath string) error {
+	cmd := command("-C", worktreePath, "submodule", "update", "--init", "--recursive")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to update submodules: "+strings.TrimSpace(string(output)),
+			"Check access to the submodule remotes, then run 'fa sync --pull' to retry",
+			err,
+		)
+	}
+	return nil
+}
+
+// SubmoduleStatuses returns the state of every submodule of a worktree,
+// recursively
+func SubmoduleStatuses(worktreePath string) ([]SubmoduleStatus, error) {
+	cmd := command("-C", worktreePath, "submodule", "status", "--recursive")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to get submodule status",
+			"Check the worktree with 'git submodule status'",
+			err,
+		)
+	}
+
+	// Format: "<state><sha> <path>[ (<describe>)]"
+	var statuses []SubmoduleStatus
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+
+		fields := strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+
+		state := SubmoduleInSync
+		switch line[0] {
+		case '-':
+			state = SubmoduleUninitialized
+		case '+':
+			state = SubmoduleDrifted
+		case 'U':
+			state = SubmoduleConflict
+		}
+
+		statuses = append(statuses, SubmoduleStatus{
+			Path:   fields[1],
+			Commit: fields[0],
+			State:  state,
+		})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSubmoduleTestRepo creates a bare repo whose main branch has a submodule
+// at lib, and returns the bare repo and the submodule's source repo
+func setupSubmoduleTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	// Local submodule URLs need the file protocol
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	tmpDir := t.TempDir()
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.email=test@example.com", "-c", "user.name=Test User"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	lib := filepath.Join(tmpDir, "lib")
+	git(tmpDir, "init", "-b", "main", lib)
+	require.NoError(t, os.WriteFile(filepath.Join(lib, "lib.go"), []byte("package lib"), 0644))
+	git(lib, "add", ".")
+	git(lib, "commit", "-m", "lib")
+
+	app := filepath.Join(tmpDir, "app")
+	git(tmpDir, "init", "-b", "main", app)
+	git(app, "submodule", "add", lib, "lib")
+	git(app, "commit", "-m", "Add lib")
+
+	bareRepo := filepath.Join(tmpDir, "app.git")
+	git(tmpDir, "clone", "--bare", app, bareRepo)
+	return bareRepo, lib
+}
+
+func TestSubmoduleUpdate(t *testing.T) {
+	bareRepo, _ := setupSubmoduleTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(bareRepo), "wt")
+	require.NoError(t, WorktreeAdd(WorktreeAddOptions{BareRepoPath: bareRepo, WorktreePath: worktreePath, Branch: "main"}))
+
+	assert.True(t, HasSubmodules(worktreePath))
+	assert.NoFileExists(t, filepath.Join(worktreePath, "lib", "lib.go"))
+
+	statuses, err := SubmoduleStatuses(worktreePath)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "lib", statuses[0].Path)
+	assert.Equal(t, SubmoduleUninitialized, statuses[0].State)
+	assert.False(t, statuses[0].InSync())
+
+	require.NoError(t, SubmoduleUpdate(worktreePath))
+	assert.FileExists(t, filepath.Join(worktreePath, "lib", "lib.go"))
+
+	statuses, err = SubmoduleStatuses(worktreePath)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].InSync())
+}
+
+func TestSubmoduleStatuses_Drifted(t *testing.T) {
+	bareRepo, lib := setupSubmoduleTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(bareRepo), "wt")
+	require.NoError(t, WorktreeAdd(WorktreeAddOptions{BareRepoPath: bareRepo, WorktreePath: worktreePath, Branch: "main"}))
+	require.NoError(t, SubmoduleUpdate(worktreePath))
+
+	// Move the submodule past the recorded commit
+	require.NoError(t, os.WriteFile(filepath.Join(lib, "more.go"), []byte("package lib"), 0644))
+	for _, args := range [][]string{
+		{"-C", lib, "add", "."},
+		{"-C", lib, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "more"},
+		{"-C", filepath.Join(worktreePath, "lib"), "pull", "-q", "origin", "main"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	statuses, err := SubmoduleStatuses(worktreePath)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, SubmoduleDrifted, statuses[0].State)
+}
+
+func TestHasSubmodules_None(t *testing.T) {
+	assert.False(t, HasSubmodules(t.TempDir()))
+}
+
+func TestSubmoduleUpdate_MissingWorktree(t *testing.T) {
+	assert.Error(t, SubmoduleUpdate("/nonexistent/path"))
+}
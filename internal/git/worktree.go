@@ -3,7 +3,6 @@ package git
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/foundagent/foundagent/internal/errors"
@@ -15,48 +14,90 @@ type WorktreeAddOptions struct {
 	WorktreePath string
 	Branch       string
 	Track        bool
+	Sparse       []string // Cone-mode sparse checkout directories; empty checks out everything
 }
 
 // WorktreeAdd creates a new worktree from a bare repository
 func WorktreeAdd(opts WorktreeAddOptions) error {
 	args := []string{"--git-dir=" + opts.BareRepoPath, "worktree", "add"}
 
-	args = append(args, opts.WorktreePath, opts.Branch)
+	if len(opts.Sparse) > 0 {
+		args = append(args, "--no-checkout")
+	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	args = append(args, opts.WorktreePath, opts.Branch)
 
-	if err := cmd.Run(); err != nil {
-		return errors.Wrap(
+	// Keep git's chatter off the terminal, where worktrees created in
+	// parallel would interleave it, and report it if the command fails
+	cmd := command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
-			fmt.Sprintf("Failed to create worktree for branch %s", opts.Branch),
+			fmt.Sprintf("Failed to create worktree for branch %s: %s", opts.Branch, strings.TrimSpace(string(output))),
 			"Ensure the branch exists in the remote repository",
 			err,
 		)
 	}
 
-	return nil
+	return applySparse(opts.BareRepoPath, opts.WorktreePath, opts.Sparse)
 }
 
-// WorktreeAddNew creates a new worktree with a new branch from a source branch
-func WorktreeAddNew(bareRepoPath, worktreePath, newBranch, sourceBranch string) error {
+// WorktreeAddNew creates a new worktree with a new branch from a source branch.
+// When sparse directories are given only those are checked out.
+func WorktreeAddNew(bareRepoPath, worktreePath, newBranch, sourceBranch string, sparse ...string) error {
 	// Create worktree with new branch checked out from source branch
-	args := []string{"--git-dir=" + bareRepoPath, "worktree", "add", "-b", newBranch, worktreePath, sourceBranch}
-
-	cmd := exec.Command("git", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	args := []string{"--git-dir=" + bareRepoPath, "worktree", "add", "-b", newBranch}
+	if len(sparse) > 0 {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, worktreePath, sourceBranch)
 
-	if err := cmd.Run(); err != nil {
-		return errors.Wrap(
+	cmd := command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
-			fmt.Sprintf("Failed to create worktree with new branch %s from %s", newBranch, sourceBranch),
+			fmt.Sprintf("Failed to create worktree with new branch %s from %s: %s", newBranch, sourceBranch, strings.TrimSpace(string(output))),
 			"Ensure the source branch exists",
 			err,
 		)
 	}
 
+	return applySparse(bareRepoPath, worktreePath, sparse)
+}
+
+// WorktreeAddDetached creates a new worktree with a detached HEAD at a commit.
+// When sparse directories are given only those are checked out.
+func WorktreeAddDetached(bareRepoPath, worktreePath, commit string, sparse ...string) error {
+	args := []string{"--git-dir=" + bareRepoPath, "worktree", "add", "--detach"}
+	if len(sparse) > 0 {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, worktreePath, commit)
+
+	cmd := command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			fmt.Sprintf("Failed to create detached worktree at %s: %s", commit, strings.TrimSpace(string(output))),
+			"Ensure the commit exists in the repository",
+			err,
+		)
+	}
+
+	return applySparse(bareRepoPath, worktreePath, sparse)
+}
+
+// applySparse checks out a worktree created with --no-checkout using the given
+// sparse directories. If that fails the half-created worktree is removed.
+func applySparse(bareRepoPath, worktreePath string, sparse []string) error {
+	if len(sparse) == 0 {
+		return nil
+	}
+
+	if err := checkoutSparse(worktreePath, sparse); err != nil {
+		_ = WorktreeRemove(bareRepoPath, worktreePath, true)
+		return err
+	}
 	return nil
 }
 
@@ -70,12 +111,12 @@ func WorktreeRemove(bareRepoPath, worktreePath string, force bool) error {
 
 	args = append(args, worktreePath)
 
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		return errors.Wrap(
+		return wrap(
 			errors.ErrCodeGitOperationFailed,
 			fmt.Sprintf("Failed to remove worktree at %s", worktreePath),
 			"Check that worktree exists and has no uncommitted changes (use --force to override)",
@@ -88,12 +129,12 @@ func WorktreeRemove(bareRepoPath, worktreePath string, force bool) error {
 
 // WorktreeList lists all worktrees for a repository
 func WorktreeList(bareRepoPath string) ([]string, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd := command("worktree", "list", "--porcelain")
 	cmd.Dir = bareRepoPath
 
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, errors.Wrap(
+		return nil, wrap(
 			errors.ErrCodeGitOperationFailed,
 			"Failed to list worktrees",
 			"Ensure the repository is valid",
@@ -113,3 +154,22 @@ func WorktreeList(bareRepoPath string) ([]string, error) {
 
 	return worktrees, nil
 }
+
+// WorktreeRepair repairs the links between a repository and worktrees that
+// were moved without 'git worktree move'
+func WorktreeRepair(bareRepoPath string, worktreePaths ...string) error {
+	args := append([]string{"--git-dir=" + bareRepoPath, "worktree", "repair"}, worktreePaths...)
+
+	cmd := command(args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return wrap(
+			errors.ErrCodeGitOperationFailed,
+			"Failed to repair worktree links",
+			fmt.Sprintf("Run 'git worktree repair' manually: %s", strings.TrimSpace(string(output))),
+			err,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,476 @@
+// Package journal records what the commands that change a workspace did,
+// with what is needed to reverse it: the workspace's files as they were, the
+// commits of deleted branches, the uncommitted changes of removed worktrees
+// and the bare clones of removed repositories. 'fa undo' reads it.
+//
+// The journal is kept on a best-effort basis: failing to record something
+// never stops the command that is recording it.
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+const (
+	// KeepEntries is how many entries are kept in a journal directory
+	KeepEntries = 20
+
+	// EntryFileName is the name of the file describing an entry, in the
+	// entry's directory
+	EntryFileName = "entry.json"
+)
+
+// Kinds of action
+const (
+	// ActionRemoveWorktree is a worktree removed, with its uncommitted changes
+	ActionRemoveWorktree = "remove_worktree"
+
+	// ActionDeleteBranch is a branch deleted from a repository
+	ActionDeleteBranch = "delete_branch"
+
+	// ActionRemoveRepo is a repository whose bare clone was removed
+	ActionRemoveRepo = "remove_repo"
+
+	// ActionCreateWorktree is a worktree created, on a new branch unless
+	// KeepBranch is set
+	ActionCreateWorktree = "create_worktree"
+
+	// ActionCloneRepo is a repository cloned into the workspace
+	ActionCloneRepo = "clone_repo"
+)
+
+// File is a workspace file as it was before a command changed it
+type File struct {
+	Path    string `json:"path"`
+	Existed bool   `json:"existed"`
+	Content string `json:"content,omitempty"`
+	After   string `json:"after,omitempty"` // Hash of the file the command left; empty if it removed the file
+}
+
+// Action is something a command did to a repository. Patch, Untracked and
+// Trash are relative to the entry's directory.
+type Action struct {
+	Kind         string   `json:"kind"`
+	Repo         string   `json:"repo"`
+	BareRepoPath string   `json:"bare_repo_path"`
+	WorktreePath string   `json:"worktree_path,omitempty"`
+	Branch       string   `json:"branch,omitempty"` // Empty for a detached worktree
+	SHA          string   `json:"sha,omitempty"`    // The worktree's HEAD, or the branch's commit
+	Sparse       []string `json:"sparse,omitempty"`
+	Patch        string   `json:"patch,omitempty"`       // Uncommitted changes to tracked files
+	Untracked    string   `json:"untracked,omitempty"`   // Copies of untracked files
+	Trash        string   `json:"trash,omitempty"`       // Where the bare clone was, or is to be, moved
+	KeepBranch   bool     `json:"keep_branch,omitempty"` // The created worktree's branch existed before
+}
+
+// Entry is the record of one command
+type Entry struct {
+	ID      string     `json:"id"`
+	Time    time.Time  `json:"time"`
+	Argv    []string   `json:"argv"`
+	Files   []File     `json:"files,omitempty"`
+	Actions []Action   `json:"actions,omitempty"`
+	Undone  *time.Time `json:"undone,omitempty"`
+
+	// Dir is the entry's directory
+	Dir string `json:"-"`
+}
+
+var (
+	mu      sync.Mutex
+	current *Entry
+	saved   int // Names the files kept for each action
+)
+
+// Begin starts the entry of a command that is about to change the workspace,
+// keeping the contents of files. dir is the journal directory.
+func Begin(dir string, argv []string, files []string) {
+	now := time.Now()
+	// IDs sort in the order the commands started
+	id := fmt.Sprintf("%s-%d", now.UTC().Format("20060102T150405.000000Z"), os.Getpid())
+	entry := &Entry{ID: id, Time: now, Argv: argv, Dir: filepath.Join(dir, id)}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			entry.Files = append(entry.Files, File{Path: path, Existed: true, Content: string(data)})
+		case os.IsNotExist(err):
+			entry.Files = append(entry.Files, File{Path: path})
+		}
+		// A file that cannot be read cannot be restored either
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = entry
+	saved = 0
+}
+
+// Active reports whether a command's entry is being recorded
+func Active() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return current != nil
+}
+
+// Record adds an action to the entry. A nil action, from a Save function
+// that had nothing to save, is ignored.
+func Record(action *Action) {
+	mu.Lock()
+	defer mu.Unlock()
+	if current != nil && action != nil {
+		current.Actions = append(current.Actions, *action)
+	}
+}
+
+// SaveWorktree saves what is needed to put back a worktree that is about to
+// be removed: its HEAD, its branch, its sparse directories and copies of its
+// uncommitted changes, both tracked and untracked. Ignored files are not
+// kept. Record the action once the worktree is removed. It returns nil when
+// no entry is being recorded or the worktree cannot be read.
+func SaveWorktree(repo, bareRepoPath, worktreePath string) *Action {
+	name, dir := reserve(repo)
+	if name == "" {
+		return nil
+	}
+
+	sha, err := git.GetFullHeadSHA(worktreePath)
+	if err != nil {
+		return nil
+	}
+	branch, _ := git.GetCurrentBranch(worktreePath)
+	action := &Action{
+		Kind:         ActionRemoveWorktree,
+		Repo:         repo,
+		BareRepoPath: bareRepoPath,
+		WorktreePath: worktreePath,
+		Branch:       branch,
+		SHA:          sha,
+	}
+	if git.IsSparse(worktreePath) {
+		action.Sparse, _ = git.SparseCheckoutList(worktreePath)
+	}
+
+	patch, err := git.Diff(worktreePath)
+	if err != nil {
+		return nil
+	}
+	if len(patch) > 0 {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil
+		}
+		action.Patch = name + ".patch"
+		if err := os.WriteFile(filepath.Join(dir, action.Patch), patch, 0644); err != nil {
+			return nil
+		}
+	}
+
+	untracked, err := git.GetUntrackedFiles(worktreePath)
+	if err != nil {
+		return nil
+	}
+	if len(untracked) > 0 {
+		action.Untracked = name + "-untracked"
+		for _, file := range untracked {
+			// Files git cannot name plainly are left behind
+			_ = copyFile(filepath.Join(worktreePath, file), filepath.Join(dir, action.Untracked, file))
+		}
+	}
+
+	return action
+}
+
+// SaveBranch saves the commit of a branch that is about to be deleted.
+// Record the action once the branch is deleted. It returns nil when no entry
+// is being recorded or the branch cannot be found.
+func SaveBranch(repo, bareRepoPath, branch string) *Action {
+	if !Active() {
+		return nil
+	}
+	sha, err := git.BranchSHA(bareRepoPath, branch)
+	if err != nil {
+		return nil
+	}
+	return &Action{
+		Kind:         ActionDeleteBranch,
+		Repo:         repo,
+		BareRepoPath: bareRepoPath,
+		Branch:       branch,
+		SHA:          sha,
+	}
+}
+
+// WorktreeCreated records a worktree just created on a new branch, so undo
+// can remove the worktree and the branch while they are as they were created
+func WorktreeCreated(repo, bareRepoPath, worktreePath, branch string) {
+	if !Active() {
+		return
+	}
+	sha, err := git.BranchSHA(bareRepoPath, branch)
+	if err != nil {
+		return
+	}
+	Record(&Action{
+		Kind:         ActionCreateWorktree,
+		Repo:         repo,
+		BareRepoPath: bareRepoPath,
+		WorktreePath: worktreePath,
+		Branch:       branch,
+		SHA:          sha,
+	})
+}
+
+// WorktreeAdded records a worktree just added on a branch that already
+// existed, or detached if branch is empty, so undo can remove the worktree
+// while it is as it was created. The branch is kept.
+func WorktreeAdded(repo, bareRepoPath, worktreePath, branch string) {
+	if !Active() {
+		return
+	}
+	sha, err := git.GetFullHeadSHA(worktreePath)
+	if err != nil {
+		return
+	}
+	Record(&Action{
+		Kind:         ActionCreateWorktree,
+		Repo:         repo,
+		BareRepoPath: bareRepoPath,
+		WorktreePath: worktreePath,
+		Branch:       branch,
+		SHA:          sha,
+		KeepBranch:   true,
+	})
+}
+
+// RepoCloned records a repository just cloned, so undo can move the clone
+// into the entry once no worktree is left on it
+func RepoCloned(repo, bareRepoPath string) {
+	name, _ := reserve(repo)
+	if name == "" {
+		return
+	}
+	Record(&Action{
+		Kind:         ActionCloneRepo,
+		Repo:         repo,
+		BareRepoPath: bareRepoPath,
+		Trash:        name + ".bare",
+	})
+}
+
+// TrashRepo removes a repository's bare clone by moving it into the entry,
+// and records that. It reports false when no entry is being recorded or the
+// clone cannot be moved, and the caller is to delete the clone itself.
+func TrashRepo(repo, bareRepoPath string) bool {
+	name, dir := reserve(repo)
+	if name == "" {
+		return false
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false
+	}
+	trash := name + ".bare"
+	if err := os.Rename(bareRepoPath, filepath.Join(dir, trash)); err != nil {
+		return false
+	}
+
+	Record(&Action{
+		Kind:         ActionRemoveRepo,
+		Repo:         repo,
+		BareRepoPath: bareRepoPath,
+		Trash:        trash,
+	})
+	return true
+}
+
+// reserve returns a name, unique in the entry, for the files kept for an
+// action on repo, and the entry's directory. The name is empty when no entry
+// is being recorded.
+func reserve(repo string) (name, dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		return "", ""
+	}
+	saved++
+	return fmt.Sprintf("%d-%s", saved, repo), current.Dir
+}
+
+// Commit ends the entry. It is written if the command changed any of the
+// files or recorded an action, and all but the newest KeepEntries entries in
+// the journal directory are removed.
+func Commit() error {
+	mu.Lock()
+	entry := current
+	current = nil
+	mu.Unlock()
+	if entry == nil {
+		return nil
+	}
+
+	var changed []File
+	for _, f := range entry.Files {
+		after := hashFile(f.Path)
+		if f.Existed && after == hash([]byte(f.Content)) || !f.Existed && after == "" {
+			continue
+		}
+		f.After = after
+		changed = append(changed, f)
+	}
+	entry.Files = changed
+
+	if len(entry.Files) == 0 && len(entry.Actions) == 0 {
+		// Anything kept for actions that did not happen
+		return os.RemoveAll(entry.Dir)
+	}
+
+	if err := entry.Save(); err != nil {
+		return err
+	}
+
+	entries, err := entryDirs(filepath.Dir(entry.Dir))
+	if err != nil {
+		return nil
+	}
+	for len(entries) > KeepEntries {
+		_ = os.RemoveAll(entries[0])
+		entries = entries[1:]
+	}
+	return nil
+}
+
+// Save writes the entry's description into its directory
+func (e *Entry) Save() error {
+	if err := os.MkdirAll(e.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.Dir, EntryFileName), append(data, '\n'), 0644)
+}
+
+// List returns the entries in the journal directory dir, oldest first.
+// Directories without a readable description are left out.
+func List(dir string) ([]*Entry, error) {
+	dirs, err := entryDirs(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, d := range dirs {
+		data, err := os.ReadFile(filepath.Join(d, EntryFileName))
+		if err != nil {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entry.Dir = d
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// Latest returns the newest entry in the journal directory dir that has not
+// been undone, or nil if there is none
+func Latest(dir string) (*Entry, error) {
+	entries, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Undone == nil {
+			return entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// entryDirs returns the entries' directories in dir, oldest first
+func entryDirs(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, e := range dirEntries {
+		if e.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, e.Name()))
+		}
+	}
+	slices.Sort(dirs)
+	return dirs, nil
+}
+
+// hash returns the hash that identifies a file's content
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFile returns the hash of the file at path, or "" if there is no file
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return hash(data)
+}
+
+// copyFile copies a file, or recreates a symlink, keeping its mode and
+// creating the directories above dst
+func copyFile(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,280 @@
+package journal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRepo creates a bare clone with a worktree on the branch feature, one
+// commit ahead of main
+func setupRepo(t *testing.T) (bareRepoPath, worktreePath string) {
+	t.Helper()
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	bareRepoPath = filepath.Join(root, "repos", "api", ".bare")
+	worktreePath = filepath.Join(root, "repos", "api", "worktrees", "feature")
+
+	run := func(args ...string) {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("init", "-q", "-b", "main", src)
+	require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("# API\n"), 0644))
+	run("-C", src, "add", ".")
+	run("-C", src, "-c", "user.email=t@t.com", "-c", "user.name=T", "commit", "-q", "-m", "init")
+	run("clone", "-q", "--bare", src, bareRepoPath)
+	run("--git-dir="+bareRepoPath, "worktree", "add", "-q", "-b", "feature", worktreePath, "main")
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("feature\n"), 0644))
+	run("-C", worktreePath, "add", ".")
+	run("-C", worktreePath, "-c", "user.email=t@t.com", "-c", "user.name=T", "commit", "-q", "-m", "feature")
+	return bareRepoPath, worktreePath
+}
+
+func TestCommit_OnlyChanges(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	root := t.TempDir()
+	same := filepath.Join(root, "same.yaml")
+	edited := filepath.Join(root, "state.json")
+	created := filepath.Join(root, "new.json")
+	require.NoError(t, os.WriteFile(same, []byte("same\n"), 0644))
+	require.NoError(t, os.WriteFile(edited, []byte("before\n"), 0644))
+
+	// A command that changes nothing leaves no entry
+	Begin(dir, []string{"fa", "status"}, []string{same, edited, created})
+	assert.True(t, Active())
+	require.NoError(t, Commit())
+	assert.False(t, Active())
+	entries, err := List(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Only the files the command changed are kept
+	Begin(dir, []string{"fa", "config", "set"}, []string{same, edited, created})
+	require.NoError(t, os.WriteFile(edited, []byte("after\n"), 0644))
+	require.NoError(t, os.WriteFile(created, []byte("{}\n"), 0644))
+	require.NoError(t, Commit())
+
+	entries, err = List(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"fa", "config", "set"}, entries[0].Argv)
+	assert.Equal(t, []File{
+		{Path: edited, Existed: true, Content: "before\n", After: hash([]byte("after\n"))},
+		{Path: created, After: hash([]byte("{}\n"))},
+	}, entries[0].Files)
+
+	// Undo puts them back
+	outcomes, err := entries[0].Undo()
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	for _, o := range outcomes {
+		assert.Equal(t, "undone", o.Status, o.Message)
+	}
+	data, err := os.ReadFile(edited)
+	require.NoError(t, err)
+	assert.Equal(t, "before\n", string(data))
+	assert.NoFileExists(t, created)
+
+	latest, err := Latest(dir)
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+}
+
+func TestUndo_FileChangedSince(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("before\n"), 0644))
+
+	Begin(dir, []string{"fa", "wt", "remove", "x"}, []string{path})
+	require.NoError(t, os.WriteFile(path, []byte("after\n"), 0644))
+	require.NoError(t, Commit())
+	require.NoError(t, os.WriteFile(path, []byte("later\n"), 0644))
+
+	entry, err := Latest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	outcomes, err := entry.Undo()
+	require.NoError(t, err)
+
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, "skipped", outcomes[0].Status)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "later\n", string(data))
+}
+
+func TestUndo_RemovedWorktreeAndBranch(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	bareRepoPath, worktreePath := setupRepo(t)
+	sha, err := git.BranchSHA(bareRepoPath, "feature")
+	require.NoError(t, err)
+
+	// Uncommitted changes, tracked and not
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("# Changed\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(worktreePath, "notes"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "notes", "todo.txt"), []byte("todo\n"), 0644))
+
+	Begin(dir, []string{"fa", "wt", "remove", "feature", "--force", "--delete-branch"}, nil)
+	saved := SaveWorktree("api", bareRepoPath, worktreePath)
+	require.NotNil(t, saved)
+	require.NoError(t, git.WorktreeRemove(bareRepoPath, worktreePath, true))
+	Record(saved)
+	saved = SaveBranch("api", bareRepoPath, "feature")
+	require.NotNil(t, saved)
+	require.NoError(t, git.DeleteBranch(bareRepoPath, "feature", true))
+	Record(saved)
+	require.NoError(t, Commit())
+
+	entry, err := Latest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Len(t, entry.Actions, 2)
+	assert.Equal(t, ActionRemoveWorktree, entry.Actions[0].Kind)
+	assert.Equal(t, "feature", entry.Actions[0].Branch)
+	assert.Equal(t, sha, entry.Actions[0].SHA)
+	assert.Equal(t, Action{Kind: ActionDeleteBranch, Repo: "api", BareRepoPath: bareRepoPath, Branch: "feature", SHA: sha}, entry.Actions[1])
+
+	outcomes, err := entry.Undo()
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	assert.Equal(t, ActionDeleteBranch, outcomes[0].Kind)
+	for _, o := range outcomes {
+		assert.Equal(t, "undone", o.Status, o.Message)
+	}
+
+	// The worktree is back on its branch, with its changes
+	branch, err := git.GetCurrentBranch(worktreePath)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", branch)
+	assert.FileExists(t, filepath.Join(worktreePath, "feature.txt"))
+	data, err := os.ReadFile(filepath.Join(worktreePath, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Changed\n", string(data))
+	data, err = os.ReadFile(filepath.Join(worktreePath, "notes", "todo.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "todo\n", string(data))
+
+	// Undoing again skips what is already back
+	entry.Undone = nil
+	outcomes, err = entry.Undo()
+	require.NoError(t, err)
+	for _, o := range outcomes {
+		assert.Equal(t, "skipped", o.Status)
+	}
+}
+
+func TestUndo_CreatedWorktree(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	bareRepoPath, _ := setupRepo(t)
+	created := filepath.Join(filepath.Dir(bareRepoPath), "worktrees", "new")
+	kept := filepath.Join(filepath.Dir(bareRepoPath), "worktrees", "kept")
+
+	Begin(dir, []string{"fa", "wt", "create", "new"}, nil)
+	require.NoError(t, git.WorktreeAddNew(bareRepoPath, created, "new", "main"))
+	WorktreeCreated("api", bareRepoPath, created, "new")
+	require.NoError(t, git.WorktreeAddNew(bareRepoPath, kept, "kept", "main"))
+	WorktreeCreated("api", bareRepoPath, kept, "kept")
+	require.NoError(t, Commit())
+
+	// Work started in one of them keeps it
+	require.NoError(t, os.WriteFile(filepath.Join(kept, "work.txt"), []byte("work\n"), 0644))
+
+	entry, err := Latest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	outcomes, err := entry.Undo()
+	require.NoError(t, err)
+
+	require.Len(t, outcomes, 2)
+	assert.Equal(t, "skipped", outcomes[0].Status)
+	assert.Equal(t, "undone", outcomes[1].Status, outcomes[1].Message)
+	assert.DirExists(t, kept)
+	assert.NoDirExists(t, created)
+	exists, err := git.BranchExists(bareRepoPath, "new")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestUndo_ClonedRepo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	bareRepoPath, feature := setupRepo(t)
+	repoDir := filepath.Dir(bareRepoPath)
+	main := filepath.Join(repoDir, "worktrees", "main")
+
+	Begin(dir, []string{"fa", "add", "api"}, nil)
+	RepoCloned("api", bareRepoPath)
+	require.NoError(t, git.WorktreeAdd(git.WorktreeAddOptions{BareRepoPath: bareRepoPath, WorktreePath: main, Branch: "main"}))
+	WorktreeAdded("api", bareRepoPath, main, "main")
+	require.NoError(t, Commit())
+
+	entry, err := Latest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Len(t, entry.Actions, 2)
+	assert.Equal(t, ActionCloneRepo, entry.Actions[0].Kind)
+	assert.True(t, entry.Actions[1].KeepBranch)
+
+	// The worktree it added goes, but its branch and the clone stay while
+	// another worktree is on it
+	outcomes, err := entry.Undo()
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	assert.Equal(t, "undone", outcomes[0].Status, outcomes[0].Message)
+	assert.Equal(t, "skipped", outcomes[1].Status)
+	assert.NoDirExists(t, main)
+	assert.True(t, git.CommitExists(bareRepoPath, "main"))
+
+	require.NoError(t, git.WorktreeRemove(bareRepoPath, feature, true))
+	entry.Undone = nil
+	outcomes, err = entry.Undo()
+	require.NoError(t, err)
+	assert.Equal(t, "undone", outcomes[1].Status, outcomes[1].Message)
+	assert.NoDirExists(t, repoDir)
+	assert.True(t, git.CommitExists(filepath.Join(entry.Dir, entry.Actions[0].Trash), "main"))
+}
+
+func TestTrashRepo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	bareRepoPath, _ := setupRepo(t)
+
+	// Without an entry the caller deletes the clone
+	assert.False(t, TrashRepo("api", bareRepoPath))
+	assert.DirExists(t, bareRepoPath)
+
+	Begin(dir, []string{"fa", "remove", "api"}, nil)
+	assert.True(t, TrashRepo("api", bareRepoPath))
+	require.NoError(t, Commit())
+	assert.NoDirExists(t, bareRepoPath)
+
+	entry, err := Latest(dir)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	outcomes, err := entry.Undo()
+	require.NoError(t, err)
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, "undone", outcomes[0].Status, outcomes[0].Message)
+	assert.True(t, git.CommitExists(bareRepoPath, "main"))
+}
+
+func TestCommit_KeepsNewestEntries(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	for i := 0; i < KeepEntries+3; i++ {
+		Begin(dir, []string{"fa", "config", "set"}, []string{path})
+		require.NoError(t, os.WriteFile(path, []byte{byte('a' + i)}, 0644))
+		require.NoError(t, Commit())
+	}
+
+	entries, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, KeepEntries)
+	// The newest kept the last write
+	assert.Equal(t, hash([]byte{byte('a' + KeepEntries + 2)}), entries[len(entries)-1].Files[0].After)
+}
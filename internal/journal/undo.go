@@ -0,0 +1,254 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+// Outcome is how undoing one action, or putting back one file, went
+type Outcome struct {
+	Kind    string `json:"kind"` // An action kind, or "file"
+	Repo    string `json:"repo,omitempty"`
+	Target  string `json:"target"` // The worktree, branch, bare clone or file
+	Status  string `json:"status"` // undone, skipped, failed
+	Message string `json:"message,omitempty"`
+}
+
+// KindFile is the kind of the outcome of putting back a file
+const KindFile = "file"
+
+// Undo reverses what the entry's command did, as far as it still can: the
+// actions newest first, then the files. A file is only put back if nothing
+// has changed it since the command; a worktree, branch or clone only if
+// nothing has taken its place; a created worktree is only removed if it is
+// as it was created; and a clone only once no worktree is left on it. The
+// entry is then marked undone.
+func (e *Entry) Undo() ([]Outcome, error) {
+	var outcomes []Outcome
+	for i := len(e.Actions) - 1; i >= 0; i-- {
+		outcomes = append(outcomes, e.undoAction(e.Actions[i]))
+	}
+	for _, f := range e.Files {
+		outcomes = append(outcomes, undoFile(f))
+	}
+
+	now := time.Now()
+	e.Undone = &now
+	return outcomes, e.Save()
+}
+
+func (e *Entry) undoAction(a Action) Outcome {
+	outcome := Outcome{Kind: a.Kind, Repo: a.Repo}
+	var err error
+	switch a.Kind {
+	case ActionRemoveRepo:
+		outcome.Target = a.BareRepoPath
+		err = e.restoreRepo(a, &outcome)
+	case ActionDeleteBranch:
+		outcome.Target = a.Branch
+		err = restoreBranch(a, &outcome)
+	case ActionRemoveWorktree:
+		outcome.Target = a.WorktreePath
+		err = e.restoreWorktree(a, &outcome)
+	case ActionCreateWorktree:
+		outcome.Target = a.WorktreePath
+		err = removeCreatedWorktree(a, &outcome)
+	case ActionCloneRepo:
+		outcome.Target = a.BareRepoPath
+		err = e.trashClone(a, &outcome)
+	default:
+		err = fmt.Errorf("unknown action %q", a.Kind)
+	}
+
+	switch {
+	case err != nil:
+		outcome.Status = "failed"
+		outcome.Message = err.Error()
+	case outcome.Status == "":
+		outcome.Status = "undone"
+	}
+	return outcome
+}
+
+// restoreRepo moves a removed bare clone back
+func (e *Entry) restoreRepo(a Action, outcome *Outcome) error {
+	if _, err := os.Stat(a.BareRepoPath); err == nil {
+		outcome.Status = "skipped"
+		outcome.Message = "a repository is already there"
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(a.BareRepoPath), 0755); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(e.Dir, a.Trash), a.BareRepoPath)
+}
+
+// restoreBranch recreates a deleted branch at its commit
+func restoreBranch(a Action, outcome *Outcome) error {
+	if exists, _ := git.BranchExists(a.BareRepoPath, a.Branch); exists {
+		outcome.Status = "skipped"
+		outcome.Message = "the branch exists again"
+		return nil
+	}
+	if !git.CommitExists(a.BareRepoPath, a.SHA) {
+		return fmt.Errorf("commit %s is no longer in the repository", a.SHA)
+	}
+	return git.CreateBranch(a.BareRepoPath, a.Branch, a.SHA)
+}
+
+// restoreWorktree adds a removed worktree back on its branch, or detached at
+// its commit if the branch is gone, and puts back its uncommitted changes
+func (e *Entry) restoreWorktree(a Action, outcome *Outcome) error {
+	if _, err := os.Stat(a.WorktreePath); err == nil {
+		outcome.Status = "skipped"
+		outcome.Message = "a directory is already there"
+		return nil
+	}
+
+	branchExists := false
+	if a.Branch != "" {
+		branchExists, _ = git.BranchExists(a.BareRepoPath, a.Branch)
+	}
+	if branchExists {
+		if err := git.WorktreeAdd(git.WorktreeAddOptions{
+			BareRepoPath: a.BareRepoPath,
+			WorktreePath: a.WorktreePath,
+			Branch:       a.Branch,
+			Sparse:       a.Sparse,
+		}); err != nil {
+			return err
+		}
+	} else {
+		if err := git.WorktreeAddDetached(a.BareRepoPath, a.WorktreePath, a.SHA, a.Sparse...); err != nil {
+			return err
+		}
+		if a.Branch != "" {
+			outcome.Message = fmt.Sprintf("branch %s is gone, so HEAD is detached at %s", a.Branch, a.SHA)
+		}
+	}
+
+	if a.Patch != "" {
+		if err := git.Apply(a.WorktreePath, filepath.Join(e.Dir, a.Patch)); err != nil {
+			return err
+		}
+	}
+
+	if a.Untracked != "" {
+		untracked := filepath.Join(e.Dir, a.Untracked)
+		err := filepath.WalkDir(untracked, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(untracked, path)
+			if err != nil {
+				return err
+			}
+			return copyFile(path, filepath.Join(a.WorktreePath, rel))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore untracked files from %s: %w", untracked, err)
+		}
+	}
+
+	return nil
+}
+
+// removeCreatedWorktree removes a worktree the command created, unless it
+// has uncommitted changes, and its new branch, unless it has moved on
+func removeCreatedWorktree(a Action, outcome *Outcome) error {
+	if _, err := os.Stat(a.WorktreePath); os.IsNotExist(err) {
+		outcome.Status = "skipped"
+		outcome.Message = "it has been removed already"
+		return nil
+	}
+	hasChanges, _ := git.HasUncommittedChanges(a.WorktreePath)
+	hasUntracked, _ := git.HasUntrackedFiles(a.WorktreePath)
+	if hasChanges || hasUntracked {
+		outcome.Status = "skipped"
+		outcome.Message = "it has uncommitted changes, so it was left as it is"
+		return nil
+	}
+
+	if err := git.WorktreeRemove(a.BareRepoPath, a.WorktreePath, false); err != nil {
+		return err
+	}
+	if a.KeepBranch {
+		return nil
+	}
+	if sha, _ := git.BranchSHA(a.BareRepoPath, a.Branch); sha != a.SHA {
+		outcome.Message = fmt.Sprintf("branch %s has changed since, so it was kept", a.Branch)
+		return nil
+	}
+	return git.DeleteBranch(a.BareRepoPath, a.Branch, true)
+}
+
+// trashClone moves a bare clone the command made into the entry, unless
+// worktrees are still on it, and removes the repository's directories if
+// nothing else is left in them
+func (e *Entry) trashClone(a Action, outcome *Outcome) error {
+	bare, err := os.Stat(a.BareRepoPath)
+	if os.IsNotExist(err) {
+		outcome.Status = "skipped"
+		outcome.Message = "it has been removed already"
+		return nil
+	}
+	worktrees, err := git.WorktreeList(a.BareRepoPath)
+	if err != nil {
+		return err
+	}
+	for _, path := range worktrees {
+		// The list starts with the bare clone itself
+		if info, err := os.Stat(path); err == nil && !os.SameFile(info, bare) {
+			outcome.Status = "skipped"
+			outcome.Message = "worktrees are still on it, so it was kept"
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(e.Dir, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(a.BareRepoPath, filepath.Join(e.Dir, a.Trash)); err != nil {
+		return err
+	}
+
+	// Directories that are not empty stay
+	repoDir := filepath.Dir(a.BareRepoPath)
+	if dirEntries, err := os.ReadDir(repoDir); err == nil {
+		for _, d := range dirEntries {
+			if d.IsDir() {
+				_ = os.Remove(filepath.Join(repoDir, d.Name()))
+			}
+		}
+	}
+	_ = os.Remove(repoDir)
+	return nil
+}
+
+// undoFile puts back a file as it was before the command, unless it has
+// changed since
+func undoFile(f File) Outcome {
+	outcome := Outcome{Kind: KindFile, Target: f.Path, Status: "undone"}
+	if hashFile(f.Path) != f.After {
+		outcome.Status = "skipped"
+		outcome.Message = "changed since, so it was left as it is"
+		return outcome
+	}
+
+	var err error
+	if f.Existed {
+		err = atomicfile.Write(f.Path, []byte(f.Content), 0644)
+	} else {
+		err = os.Remove(f.Path)
+	}
+	if err != nil {
+		outcome.Status = "failed"
+		outcome.Message = err.Error()
+	}
+	return outcome
+}
@@ -0,0 +1,355 @@
+// Package progress shows the progress of work on several repos at once. On a
+// terminal each repo gets a line showing its current phase and percentage,
+// parsed from git's --progress output; elsewhere each repo gets a plain line
+// when it starts and when it finishes.
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// redrawInterval limits how often a terminal is redrawn while git
+	// reports progress
+	redrawInterval = 100 * time.Millisecond
+
+	// maxOutputLines is how many lines of a task's other output are kept to
+	// show if it fails
+	maxOutputLines = 20
+
+	// defaultWidth is the terminal width assumed when COLUMNS is not set
+	defaultWidth = 80
+)
+
+// Task states
+const (
+	stateRunning = iota
+	stateDone
+	stateFailed
+)
+
+// Renderer shows the progress of a set of tasks. A nil *Renderer shows
+// nothing, so callers can use one unconditionally.
+type Renderer struct {
+	mu    sync.Mutex
+	out   io.Writer
+	tty   bool
+	width int
+	tasks []*Task
+	drawn int       // Lines drawn by the last redraw
+	last  time.Time // Time of the last redraw
+}
+
+// Task is one repo's line in a Renderer. A nil *Task does nothing.
+type Task struct {
+	r       *Renderer
+	name    string
+	phase   string
+	percent int // -1 when the phase has no percentage
+	state   int
+	partial []byte   // Output after the last line break
+	output  []string // Output lines that are not progress
+}
+
+// New returns a renderer that writes to out, redrawing one line per task if
+// tty is set
+func New(out io.Writer, tty bool) *Renderer {
+	width := defaultWidth
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		width = columns
+	}
+	return &Renderer{out: out, tty: tty, width: width}
+}
+
+// Stdout returns a renderer for standard output, which redraws lines only if
+// standard output is a terminal that understands cursor movement
+func Stdout() *Renderer {
+	return New(os.Stdout, IsTerminal(os.Stdout))
+}
+
+// IsTerminal reports whether f is a terminal that understands ANSI cursor
+// movement. Windows consoles only do when a program asks them to, so they are
+// treated as plain output.
+func IsTerminal(f *os.File) bool {
+	if runtime.GOOS == "windows" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Start begins the task for name in the given phase, such as "cloning". A
+// task that already exists for name is restarted, keeping its line.
+func (r *Renderer) Start(name, phase string) *Task {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var task *Task
+	for _, t := range r.tasks {
+		if t.name == name {
+			task = t
+			break
+		}
+	}
+	if task == nil {
+		task = &Task{r: r, name: name}
+		r.tasks = append(r.tasks, task)
+	}
+	task.phase = phase
+	task.percent = -1
+	task.state = stateRunning
+	task.output = nil
+
+	if r.tty {
+		r.redraw()
+	} else {
+		fmt.Fprintf(r.out, "%s: %s...\n", name, phase)
+	}
+	return task
+}
+
+// Stop clears the task lines from a terminal, so the results printed next
+// replace them. Plain output is left as it is.
+func (r *Renderer) Stop() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tty {
+		r.clear()
+	}
+	r.tasks = nil
+}
+
+// Writer returns a writer for git's progress output, or nil for a nil task
+// so git is not asked for progress nobody sees
+func (t *Task) Writer() io.Writer {
+	if t == nil {
+		return nil
+	}
+	return t
+}
+
+// Write parses git's progress output. Progress lines update the task's phase
+// and percentage; other lines are kept to show if the task fails.
+func (t *Task) Write(p []byte) (int, error) {
+	if t == nil {
+		return len(p), nil
+	}
+	t.r.mu.Lock()
+	defer t.r.mu.Unlock()
+
+	t.partial = append(t.partial, p...)
+	for {
+		i := bytes.IndexAny(t.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		t.parse(string(t.partial[:i]))
+		t.partial = t.partial[i+1:]
+	}
+
+	if t.r.tty && time.Since(t.r.last) >= redrawInterval {
+		t.r.redraw()
+	}
+	return len(p), nil
+}
+
+// SetPhase moves the task on to its next phase, such as "creating worktree".
+// Plain output only shows when tasks start and finish.
+func (t *Task) SetPhase(phase string) {
+	if t == nil {
+		return
+	}
+	t.r.mu.Lock()
+	defer t.r.mu.Unlock()
+
+	t.phase = phase
+	t.percent = -1
+	if t.r.tty {
+		t.r.redraw()
+	}
+}
+
+// Log prints a message about the task, such as a retry, without disturbing
+// the task lines
+func (t *Task) Log(format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.r.mu.Lock()
+	defer t.r.mu.Unlock()
+
+	t.r.print(fmt.Sprintf("%s: %s", t.name, fmt.Sprintf(format, args...)))
+}
+
+// Done marks the task as finished successfully
+func (t *Task) Done() {
+	t.finish(stateDone)
+}
+
+// Fail marks the task as failed, and prints the output git gave besides its
+// progress, which usually says why
+func (t *Task) Fail() {
+	t.finish(stateFailed)
+}
+
+func (t *Task) finish(state int) {
+	if t == nil {
+		return
+	}
+	t.r.mu.Lock()
+	defer t.r.mu.Unlock()
+
+	if len(t.partial) > 0 {
+		t.parse(string(t.partial))
+		t.partial = nil
+	}
+	t.state = state
+	t.percent = -1
+
+	if state == stateFailed {
+		for _, line := range t.output {
+			t.r.print(fmt.Sprintf("%s: %s", t.name, line))
+		}
+	}
+	if t.r.tty {
+		t.r.redraw()
+	} else {
+		fmt.Fprintf(t.r.out, "%s: %s\n", t.name, t.status())
+	}
+}
+
+// progressLine matches git's progress lines, such as
+// "Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s" and
+// "Enumerating objects: 12, done."
+var progressLine = regexp.MustCompile(`^([A-Z][A-Za-z ]*):\s+(?:(\d+)%|\d+)`)
+
+// ParseProgress returns the phase and percentage of a line of git's
+// progress output, with a percentage of -1 if the phase has none. ok is false
+// for lines that are not progress.
+func ParseProgress(line string) (phase string, percent int, ok bool) {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "remote: ")
+	m := progressLine.FindStringSubmatch(line)
+	if m == nil {
+		return "", 0, false
+	}
+	percent = -1
+	if m[2] != "" {
+		percent, _ = strconv.Atoi(m[2])
+	}
+	return m[1], percent, true
+}
+
+// parse handles one line of git's output
+func (t *Task) parse(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	if phase, percent, ok := ParseProgress(line); ok {
+		t.phase = strings.ToLower(phase[:1]) + phase[1:]
+		t.percent = percent
+		return
+	}
+	// The task's line already says what is being cloned
+	if strings.HasPrefix(line, "Cloning into ") {
+		return
+	}
+	t.output = append(t.output, line)
+	if len(t.output) > maxOutputLines {
+		t.output = t.output[len(t.output)-maxOutputLines:]
+	}
+}
+
+// print writes a line above the task lines
+func (r *Renderer) print(line string) {
+	if !r.tty {
+		fmt.Fprintln(r.out, line)
+		return
+	}
+	r.clear()
+	fmt.Fprintln(r.out, line)
+	r.redraw()
+}
+
+// clear erases the task lines, leaving the cursor where the first one was
+func (r *Renderer) clear() {
+	if r.drawn > 0 {
+		fmt.Fprintf(r.out, "\x1b[%dA\r\x1b[J", r.drawn)
+	}
+	r.drawn = 0
+}
+
+// redraw draws every task's line in place of the previous ones
+func (r *Renderer) redraw() {
+	nameWidth := 0
+	for _, t := range r.tasks {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(t.name))
+	}
+
+	var buf bytes.Buffer
+	if r.drawn > 0 {
+		fmt.Fprintf(&buf, "\x1b[%dA\r\x1b[J", r.drawn)
+	}
+	for _, t := range r.tasks {
+		buf.WriteString(truncate(t.line(nameWidth), r.width-1))
+		buf.WriteByte('\n')
+	}
+	_, _ = r.out.Write(buf.Bytes())
+
+	r.drawn = len(r.tasks)
+	r.last = time.Now()
+}
+
+// line formats the task's line, with its name padded to width
+func (t *Task) line(width int) string {
+	symbol := " "
+	switch t.state {
+	case stateDone:
+		symbol = "✓"
+	case stateFailed:
+		symbol = "✗"
+	}
+	return fmt.Sprintf("%s %-*s  %s", symbol, width, t.name, t.status())
+}
+
+// status describes the task's phase and percentage, or how it finished
+func (t *Task) status() string {
+	switch {
+	case t.state == stateDone:
+		return "done"
+	case t.state == stateFailed:
+		return "failed"
+	case t.percent >= 0:
+		return fmt.Sprintf("%s %3d%%", t.phase, t.percent)
+	default:
+		return t.phase
+	}
+}
+
+// truncate shortens s to at most width runes, so no line wraps and throws off
+// the redraw
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width])
+}
@@ -0,0 +1,106 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		line    string
+		phase   string
+		percent int
+		ok      bool
+	}{
+		{"Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s", "Receiving objects", 45, true},
+		{"remote: Compressing objects: 100% (8/8), done.", "Compressing objects", 100, true},
+		{"remote: Enumerating objects: 12, done.", "Enumerating objects", -1, true},
+		{"Resolving deltas:   0% (0/3)", "Resolving deltas", 0, true},
+		{"Cloning into bare repository 'api/.bare'...", "", 0, false},
+		{"fatal: repository 'https://example.com/api.git/' not found", "", 0, false},
+		{"", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		phase, percent, ok := ParseProgress(tt.line)
+		assert.Equal(t, tt.ok, ok, tt.line)
+		if tt.ok {
+			assert.Equal(t, tt.phase, phase, tt.line)
+			assert.Equal(t, tt.percent, percent, tt.line)
+		}
+	}
+}
+
+func TestRenderer_Plain(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out, false)
+
+	api := r.Start("api", "cloning")
+	web := r.Start("web", "cloning")
+	_, _ = api.Writer().Write([]byte("Receiving objects:  50% (1/2)\rReceiving objects: 100% (2/2), done.\n"))
+	api.Done()
+	_, _ = web.Writer().Write([]byte("fatal: Could not read from remote repository.\n"))
+	web.Fail()
+	r.Stop()
+
+	assert.Equal(t, strings.Join([]string{
+		"api: cloning...",
+		"web: cloning...",
+		"api: done",
+		"web: fatal: Could not read from remote repository.",
+		"web: failed",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestRenderer_Terminal(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out, true)
+
+	api := r.Start("api", "cloning")
+	r.Start("frontend", "cloning")
+	r.last = time.Time{} // Redraw regardless of the interval
+	_, _ = api.Writer().Write([]byte("Receiving objects:  45% (450/1000)\r"))
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Contains(t, lines[len(lines)-3], "  api       receiving objects  45%")
+	assert.Contains(t, lines[len(lines)-2], "  frontend  cloning")
+
+	api.Done()
+	assert.Contains(t, out.String(), "✓ api       done")
+
+	out.Reset()
+	r.Stop()
+	assert.Equal(t, "\x1b[2A\r\x1b[J", out.String())
+}
+
+func TestTask_Log(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out, true)
+
+	task := r.Start("api", "fetching")
+	out.Reset()
+	task.Log("retry %d", 1)
+
+	// The task line is cleared, the message printed, and the line redrawn
+	assert.True(t, strings.HasPrefix(out.String(), "\x1b[1A\r\x1b[Japi: retry 1\n"))
+	assert.Contains(t, out.String(), "api  fetching")
+}
+
+func TestNilRenderer(t *testing.T) {
+	var r *Renderer
+
+	task := r.Start("api", "cloning")
+	assert.Nil(t, task)
+	assert.Nil(t, task.Writer())
+
+	task.SetPhase("checking out")
+	task.Log("ignored")
+	task.Done()
+	task.Fail()
+	r.Stop()
+}
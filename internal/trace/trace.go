@@ -0,0 +1,221 @@
+// Package trace records the subprocesses foundagent runs, such as git, as
+// JSON lines, so a misbehaving run can be examined while it runs or after it
+// has finished.
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TypeRun is the type of the record of the fa invocation itself, written
+	// first
+	TypeRun = "run"
+
+	// TypeExec is the type of the record of a subprocess
+	TypeExec = "exec"
+
+	// MaxStderr is how much of a subprocess's stderr a record keeps. The end
+	// is kept, since that is where errors are.
+	MaxStderr = 4096
+
+	// KeepRuns is how many run logs are kept in a log directory
+	KeepRuns = 20
+
+	// runLogExt is the extension of run log files
+	runLogExt = ".jsonl"
+)
+
+// Record describes the fa invocation or a subprocess it ran
+type Record struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Argv       []string  `json:"argv"`
+	Cwd        string    `json:"cwd"`
+	DurationMs float64   `json:"duration_ms,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	Stderr     string    `json:"stderr,omitempty"`
+	Error      string    `json:"error,omitempty"` // Set when the process could not run or was killed
+}
+
+// Duration returns how long the subprocess ran
+func (r Record) Duration() time.Duration {
+	return time.Duration(r.DurationMs * float64(time.Millisecond))
+}
+
+var (
+	mu      sync.Mutex
+	writers []io.Writer
+	closers []io.Closer
+)
+
+// Enabled reports whether records are being written anywhere
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(writers) > 0
+}
+
+// AddWriter writes records to w from now on
+func AddWriter(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	writers = append(writers, w)
+}
+
+// AddFile appends records to the file at path from now on
+func AddFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	writers = append(writers, f)
+	closers = append(closers, f)
+	return nil
+}
+
+// AddRunLog writes records to a new log file for this run in dir, and removes
+// all but the newest KeepRuns logs there
+func AddRunLog(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// Names sort in the order the runs started
+	name := fmt.Sprintf("%s-%d%s", time.Now().UTC().Format("20060102T150405.000000Z"), os.Getpid(), runLogExt)
+	if err := AddFile(filepath.Join(dir, name)); err != nil {
+		return err
+	}
+
+	logs, err := RunLogs(dir)
+	if err != nil {
+		return nil
+	}
+	for len(logs) > KeepRuns {
+		_ = os.Remove(logs[0])
+		logs = logs[1:]
+	}
+	return nil
+}
+
+// Close stops writing records and closes the files written to
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, c := range closers {
+		_ = c.Close()
+	}
+	writers = nil
+	closers = nil
+}
+
+// Write writes a record to every writer
+func Write(rec Record) {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(writers) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	for _, w := range writers {
+		_, _ = w.Write(data)
+	}
+}
+
+// Run writes the record of the fa invocation
+func Run(argv []string) {
+	cwd, _ := os.Getwd()
+	Write(Record{Type: TypeRun, Time: time.Now(), Argv: argv, Cwd: cwd})
+}
+
+// Exec writes the record of a subprocess that started at start and finished
+// with err. cwd empty means the current directory.
+func Exec(argv []string, cwd string, start time.Time, stderr []byte, err error) {
+	if !Enabled() {
+		return
+	}
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+
+	rec := Record{
+		Type:       TypeExec,
+		Time:       start,
+		Argv:       argv,
+		Cwd:        cwd,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+		Stderr:     Truncate(stderr),
+	}
+	if err != nil {
+		rec.ExitCode = -1
+		// *exec.ExitError, or another error for a process that exited
+		if exitErr, ok := err.(interface{ ExitCode() int }); ok && exitErr.ExitCode() >= 0 {
+			rec.ExitCode = exitErr.ExitCode()
+		} else {
+			rec.Error = err.Error()
+		}
+	}
+	Write(rec)
+}
+
+// Truncate returns the end of stderr, at most MaxStderr bytes of it
+func Truncate(stderr []byte) string {
+	s := strings.TrimSpace(string(stderr))
+	if len(s) <= MaxStderr {
+		return s
+	}
+	return "..." + strings.ToValidUTF8(s[len(s)-MaxStderr:], "")
+}
+
+// RunLogs returns the run logs in dir, oldest first
+func RunLogs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), runLogExt) {
+			logs = append(logs, filepath.Join(dir, e.Name()))
+		}
+	}
+	slices.Sort(logs)
+	return logs, nil
+}
+
+// Read reads the records of a log. Lines that are not records, such as one
+// cut short by a crash, are skipped.
+func Read(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err == nil {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
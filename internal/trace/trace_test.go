@@ -0,0 +1,102 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExec(t *testing.T) {
+	defer Close()
+
+	var buf bytes.Buffer
+	AddWriter(&buf)
+
+	failed := exec.Command("git", "rev-parse", "--verify", "no-such-ref")
+	failed.Dir = t.TempDir()
+	stderr, runErr := failed.CombinedOutput()
+
+	start := time.Now()
+	Exec([]string{"git", "status"}, "/repo", start, nil, nil)
+	Exec(failed.Args, failed.Dir, start, stderr, runErr)
+
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec Record
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		records = append(records, rec)
+	}
+	require.Len(t, records, 2)
+
+	assert.Equal(t, TypeExec, records[0].Type)
+	assert.Equal(t, []string{"git", "status"}, records[0].Argv)
+	assert.Equal(t, "/repo", records[0].Cwd)
+	assert.Equal(t, 0, records[0].ExitCode)
+
+	assert.Equal(t, 128, records[1].ExitCode)
+	assert.Contains(t, records[1].Stderr, "not a git repository")
+	assert.Empty(t, records[1].Error)
+}
+
+func TestExec_Disabled(t *testing.T) {
+	assert.False(t, Enabled())
+	// Nothing to write to; must not panic
+	Exec([]string{"git", "status"}, "", time.Now(), nil, nil)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "fatal: oops", Truncate([]byte("fatal: oops\n")))
+
+	long := strings.Repeat("x", MaxStderr) + "fatal: the end"
+	truncated := Truncate([]byte(long))
+	assert.True(t, strings.HasPrefix(truncated, "..."))
+	assert.True(t, strings.HasSuffix(truncated, "fatal: the end"))
+	assert.Len(t, truncated, MaxStderr+3)
+}
+
+func TestAddRunLog(t *testing.T) {
+	defer Close()
+	dir := filepath.Join(t.TempDir(), "logs")
+
+	// Older runs beyond KeepRuns are removed
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for i := 0; i < KeepRuns+5; i++ {
+		name := filepath.Join(dir, "20200101T000000.0000"+string(rune('A'+i))+"Z-1.jsonl")
+		require.NoError(t, os.WriteFile(name, nil, 0644))
+	}
+
+	require.NoError(t, AddRunLog(dir))
+	Run([]string{"fa", "sync"})
+	Exec([]string{"git", "fetch"}, "/repo", time.Now(), []byte("fatal: unable to access"), &exec.ExitError{})
+	Close()
+
+	logs, err := RunLogs(dir)
+	require.NoError(t, err)
+	assert.Len(t, logs, KeepRuns)
+
+	records, err := Read(logs[len(logs)-1])
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, TypeRun, records[0].Type)
+	assert.Equal(t, []string{"fa", "sync"}, records[0].Argv)
+	assert.Equal(t, "fatal: unable to access", records[1].Stderr)
+}
+
+func TestRead_SkipsPartialLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.jsonl")
+	content := `{"type":"run","argv":["fa","status"]}` + "\n" + `{"type":"exec","argv":["git"`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	records, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, TypeRun, records[0].Type)
+}
@@ -1,9 +1,11 @@
 package workspace
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/git"
 )
 
@@ -38,16 +40,16 @@ type CommitSummary struct {
 // CommitOptions represents options for cross-repo commit
 type CommitOptions struct {
 	Message       string
-	All           bool     // Stage all tracked modifications (-a)
-	Amend         bool     // Amend previous commit
-	DryRun        bool     // Preview without executing
-	Repos         []string // Limit to specific repos (nil = all)
-	Verbose       bool     // Show detailed output
-	AllowDetached bool     // Allow commits in detached HEAD
+	All           bool            // Stage all tracked modifications (-a)
+	Amend         bool            // Amend previous commit
+	DryRun        bool            // Preview without executing
+	Selection     config.Selector // Limit to selected repos (empty = all)
+	Verbose       bool            // Show detailed output
+	AllowDetached bool            // Allow commits in detached HEAD
 }
 
 // CommitAllRepos commits across all repos with staged changes
-func (w *Workspace) CommitAllRepos(opts CommitOptions) ([]CommitResult, error) {
+func (w *Workspace) CommitAllRepos(ctx context.Context, opts CommitOptions) ([]CommitResult, error) {
 	state, err := w.LoadState()
 	if err != nil {
 		return nil, err
@@ -58,7 +60,7 @@ func (w *Workspace) CommitAllRepos(opts CommitOptions) ([]CommitResult, error) {
 	}
 
 	currentBranch := getCurrentBranch(state)
-	repoNames, err := w.filterRepoNames(state, opts.Repos)
+	repoNames, err := w.SelectRepoNames(state, opts.Selection)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +69,7 @@ func (w *Workspace) CommitAllRepos(opts CommitOptions) ([]CommitResult, error) {
 	repoStates := w.prepareCommitStates(repoNames, currentBranch, opts)
 
 	// Execute commits in parallel
-	parallelResults := ExecuteParallel(repoNames, func(repoName string) error {
+	parallelResults := ExecuteParallel(ctx, w.Jobs(), repoNames, func(ctx context.Context, repoName string) error {
 		return w.executeCommit(repoStates[repoName], opts)
 	})
 
@@ -82,33 +84,6 @@ func getCurrentBranch(state *State) string {
 	return "main"
 }
 
-func (w *Workspace) filterRepoNames(state *State, requestedRepos []string) ([]string, error) {
-	var repoNames []string
-	for name := range state.Repositories {
-		if len(requestedRepos) > 0 && !containsString(requestedRepos, name) {
-			continue
-		}
-		repoNames = append(repoNames, name)
-	}
-
-	// Validate requested repos exist
-	for _, r := range requestedRepos {
-		if _, exists := state.Repositories[r]; !exists {
-			return nil, fmt.Errorf("repository '%s' not found in workspace", r)
-		}
-	}
-	return repoNames, nil
-}
-
-func containsString(slice []string, s string) bool {
-	for _, item := range slice {
-		if item == s {
-			return true
-		}
-	}
-	return false
-}
-
 type commitRepoState struct {
 	worktreePath string
 	hasStaged    bool
@@ -269,5 +244,5 @@ func FormatCommitResults(results []CommitResult) string {
 // GetDryRunPreview returns preview info for dry-run mode
 func (w *Workspace) GetDryRunPreview(opts CommitOptions) ([]CommitResult, error) {
 	opts.DryRun = true
-	return w.CommitAllRepos(opts)
+	return w.CommitAllRepos(context.Background(), opts)
 }
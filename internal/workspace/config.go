@@ -3,6 +3,7 @@ package workspace
 import (
 	"os"
 
+	"github.com/foundagent/foundagent/internal/atomicfile"
 	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/errors"
 )
@@ -20,7 +21,7 @@ func (w *Workspace) createConfig() error {
 
 	// Write template to file
 	configPath := w.ConfigPath()
-	if err := os.WriteFile(configPath, []byte(template), 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(configPath, []byte(template), 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write configuration file",
@@ -68,3 +69,19 @@ func (w *Workspace) SaveConfig(config *Config) error {
 		"Use config.Save() instead",
 	)
 }
+
+// repoConfigs returns the merged config entry of every repo by name. A config
+// that fails to load yields an empty map, so callers fall back to defaults.
+func (w *Workspace) repoConfigs() map[string]config.RepoConfig {
+	repos := make(map[string]config.RepoConfig)
+
+	cfg, err := config.Load(w.Path)
+	if err != nil {
+		return repos
+	}
+
+	for _, repo := range cfg.Repos {
+		repos[repo.Name] = repo
+	}
+	return repos
+}
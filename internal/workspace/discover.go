@@ -4,10 +4,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/errors"
 )
 
-// Discover finds the workspace root by searching for .foundagent.yaml
+// Discover finds the workspace root by searching upwards for a config file
+// in any supported format
 func Discover(startPath string) (*Workspace, error) {
 	if startPath == "" {
 		var err error
@@ -36,8 +38,7 @@ func Discover(startPath string) (*Workspace, error) {
 	// Walk up the directory tree
 	current := startPath
 	for {
-		configPath := filepath.Join(current, ConfigFileName)
-		if _, err := os.Stat(configPath); err == nil {
+		if config.HasConfig(current) {
 			// Found workspace root - load config to get name
 			ws := &Workspace{
 				Path: current,
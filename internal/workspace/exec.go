@@ -0,0 +1,281 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/trace"
+)
+
+// Exec status constants
+const (
+	ExecStatusSucceeded = "succeeded"
+	ExecStatusFailed    = "failed"
+	ExecStatusSkipped   = "skipped"
+	ExecStatusCancelled = "cancelled"
+)
+
+// ExecResult represents the result of running a command in a single worktree
+type ExecResult struct {
+	RepoName     string        `json:"name"`
+	Path         string        `json:"path"`
+	Status       string        `json:"status"`
+	ExitCode     int           `json:"exit_code"`
+	Duration     time.Duration `json:"-"`
+	DurationMs   int64         `json:"duration_ms"`
+	Output       string        `json:"output,omitempty"`
+	Error        error         `json:"-"`
+	ErrorMessage string        `json:"error,omitempty"`
+}
+
+// ExecSummary aggregates exec results across all repos
+type ExecSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled"`
+}
+
+// ExecOptions represents options for running a command across worktrees
+type ExecOptions struct {
+	Branch    string          // Branch whose worktrees to run in (empty = current)
+	Command   []string        // Command and arguments
+	Selection config.Selector // Limit to selected repos (empty = all)
+	FailFast  bool            // Cancel remaining repos after the first failure
+	Stream    io.Writer       // If set, stream output prefixed with the repo name
+}
+
+// ExecAll runs a command in every selected repo's worktree for a branch in parallel
+func (w *Workspace) ExecAll(ctx context.Context, opts ExecOptions) ([]ExecResult, error) {
+	if len(opts.Command) == 0 {
+		return nil, fmt.Errorf("no command given")
+	}
+
+	state, err := w.LoadState()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.Repositories) == 0 {
+		return []ExecResult{}, nil
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = getCurrentBranch(state)
+	}
+
+	repoNames, err := w.SelectRepoNames(state, opts.Selection)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pre-allocate one result per repo so goroutines never touch the map
+	results := make(map[string]*ExecResult, len(repoNames))
+	for _, name := range repoNames {
+		results[name] = &ExecResult{RepoName: name, Path: w.WorktreePath(name, branch)}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var streamMu sync.Mutex
+
+	parallelResults := ExecuteParallel(ctx, w.Jobs(), repoNames, func(ctx context.Context, repoName string) error {
+		result := results[repoName]
+
+		if _, err := os.Stat(result.Path); err != nil {
+			result.Status = ExecStatusSkipped
+			result.ExitCode = -1
+			return fmt.Errorf("no worktree for branch %s", branch)
+		}
+
+		if ctx.Err() != nil {
+			result.Status = ExecStatusCancelled
+			result.ExitCode = -1
+			return context.Cause(ctx)
+		}
+
+		err := runInWorktree(ctx, w, repoName, branch, result, opts, &streamMu)
+		if err != nil && opts.FailFast && result.Status == ExecStatusFailed {
+			cancel(fmt.Errorf("cancelled by --fail-fast"))
+		}
+		return err
+	})
+
+	ordered := make([]ExecResult, len(parallelResults))
+	for i, pr := range parallelResults {
+		r := results[pr.RepoName]
+		if r.Status == "" {
+			// Never started, because the run was cancelled first
+			r.Status = ExecStatusCancelled
+			r.ExitCode = -1
+		}
+		if pr.Error != nil {
+			r.Error = pr.Error
+			r.ErrorMessage = pr.Error.Error()
+		}
+		ordered[i] = *r
+	}
+
+	return ordered, nil
+}
+
+// runInWorktree runs the command in a single worktree and fills in the result
+func runInWorktree(ctx context.Context, w *Workspace, repoName, branch string, result *ExecResult, opts ExecOptions, streamMu *sync.Mutex) error {
+	cmd := exec.CommandContext(ctx, opts.Command[0], opts.Command[1:]...)
+	cmd.Dir = result.Path
+	cmd.Env = append(os.Environ(),
+		"FA_WORKSPACE="+w.Path,
+		"FA_REPO="+repoName,
+		"FA_BRANCH="+branch,
+	)
+
+	var buf bytes.Buffer
+	var out io.Writer = &buf
+	var prefixed *prefixWriter
+	if opts.Stream != nil {
+		prefixed = &prefixWriter{prefix: "[" + repoName + "] ", dst: opts.Stream, mu: streamMu}
+		out = io.MultiWriter(&buf, prefixed)
+	}
+	// Output and errors go through separate pipes so stderr alone can be
+	// traced, and take turns writing to the combined output
+	var stderr bytes.Buffer
+	combined := &lockedWriter{w: out}
+	cmd.Stdout = combined
+	cmd.Stderr = io.MultiWriter(combined, &stderr)
+
+	start := time.Now()
+	err := cmd.Run()
+	trace.Exec(cmd.Args, cmd.Dir, start, stderr.Bytes(), err)
+	result.Duration = time.Since(start)
+	result.DurationMs = result.Duration.Milliseconds()
+	result.Output = buf.String()
+	if prefixed != nil {
+		prefixed.Flush()
+	}
+
+	if err == nil {
+		result.Status = ExecStatusSucceeded
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		result.Status = ExecStatusCancelled
+		result.ExitCode = -1
+		return context.Cause(ctx)
+	}
+
+	result.Status = ExecStatusFailed
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+		return fmt.Errorf("exit status %d", result.ExitCode)
+	}
+	result.ExitCode = -1
+	return err
+}
+
+// lockedWriter lets the stdout and stderr of a command write to one writer
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// prefixWriter writes complete lines to dst, each prefixed with the repo name
+type prefixWriter struct {
+	prefix  string
+	dst     io.Writer
+	mu      *sync.Mutex
+	pending []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.pending = append(p.pending, data...)
+	for {
+		idx := bytes.IndexByte(p.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		p.writeLine(p.pending[:idx+1])
+		p.pending = p.pending[idx+1:]
+	}
+	return len(data), nil
+}
+
+// Flush writes any trailing partial line
+func (p *prefixWriter) Flush() {
+	if len(p.pending) > 0 {
+		p.writeLine(append(p.pending, '\n'))
+		p.pending = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.dst, "%s%s", p.prefix, line)
+}
+
+// CalculateExecSummary aggregates exec results into a summary
+func CalculateExecSummary(results []ExecResult) ExecSummary {
+	summary := ExecSummary{
+		Total: len(results),
+	}
+
+	for _, r := range results {
+		switch r.Status {
+		case ExecStatusSucceeded:
+			summary.Succeeded++
+		case ExecStatusFailed:
+			summary.Failed++
+		case ExecStatusSkipped:
+			summary.Skipped++
+		case ExecStatusCancelled:
+			summary.Cancelled++
+		}
+	}
+
+	return summary
+}
+
+// FormatExecResults formats exec results as a per-repo summary table
+func FormatExecResults(results []ExecResult) string {
+	var output strings.Builder
+
+	for _, r := range results {
+		status := StatusSymbolSuccess
+		switch r.Status {
+		case ExecStatusFailed:
+			status = StatusSymbolFailed
+		case ExecStatusSkipped, ExecStatusCancelled:
+			status = StatusSymbolSkipped
+		}
+
+		output.WriteString(fmt.Sprintf("%s %s: %s", status, r.RepoName, r.Status))
+
+		if (r.Status == ExecStatusSucceeded || r.Status == ExecStatusFailed) && r.ExitCode >= 0 {
+			output.WriteString(fmt.Sprintf(" (exit %d, %s)", r.ExitCode, r.Duration.Round(time.Millisecond)))
+		} else if r.ErrorMessage != "" {
+			output.WriteString(fmt.Sprintf(" (%s)", r.ErrorMessage))
+		}
+
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
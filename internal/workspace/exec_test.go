@@ -0,0 +1,146 @@
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupExecWorkspace(t *testing.T, branch string, repos ...string) *Workspace {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	state := &State{Repositories: map[string]*Repository{}}
+	for _, name := range repos {
+		state.Repositories[name] = &Repository{Name: name, Worktrees: []string{branch}}
+		require.NoError(t, os.MkdirAll(ws.WorktreePath(name, branch), 0755))
+	}
+	require.NoError(t, ws.SaveState(state))
+	return ws
+}
+
+func TestExecAll(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api", "web")
+
+	results, err := ws.ExecAll(t.Context(), ExecOptions{
+		Branch:  "main",
+		Command: []string{"sh", "-c", "echo $FA_REPO@$FA_BRANCH"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "api", results[0].RepoName)
+	assert.Equal(t, ExecStatusSucceeded, results[0].Status)
+	assert.Equal(t, 0, results[0].ExitCode)
+	assert.Equal(t, "api@main\n", results[0].Output)
+	assert.Equal(t, "web@main\n", results[1].Output)
+}
+
+func TestExecAll_Failure(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api", "web")
+
+	results, err := ws.ExecAll(t.Context(), ExecOptions{
+		Branch:  "main",
+		Command: []string{"sh", "-c", `[ "$FA_REPO" = api ] && exit 3; exit 0`},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, ExecStatusFailed, results[0].Status)
+	assert.Equal(t, 3, results[0].ExitCode)
+	assert.Equal(t, ExecStatusSucceeded, results[1].Status)
+
+	summary := CalculateExecSummary(results)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 1, summary.Succeeded)
+}
+
+func TestExecAll_MissingWorktreeSkipped(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api")
+
+	results, err := ws.ExecAll(t.Context(), ExecOptions{Branch: "feature", Command: []string{"true"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, ExecStatusSkipped, results[0].Status)
+	assert.Contains(t, results[0].ErrorMessage, "no worktree")
+}
+
+func TestExecAll_Selection(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api", "web")
+
+	results, err := ws.ExecAll(t.Context(), ExecOptions{
+		Branch:    "main",
+		Command:   []string{"true"},
+		Selection: config.Selector{Repos: []string{"web"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "web", results[0].RepoName)
+}
+
+func TestExecAll_Stream(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api")
+
+	var out bytes.Buffer
+	_, err := ws.ExecAll(t.Context(), ExecOptions{
+		Branch:  "main",
+		Command: []string{"sh", "-c", "echo one; printf two"},
+		Stream:  &out,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "[api] one\n[api] two\n", out.String())
+}
+
+func TestExecAll_TraceStderr(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api")
+	var records bytes.Buffer
+	trace.AddWriter(&records)
+	defer trace.Close()
+
+	results, err := ws.ExecAll(t.Context(), ExecOptions{
+		Branch:  "main",
+		Command: []string{"sh", "-c", "echo out; echo err >&2; exit 1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Output, "out\n")
+	assert.Contains(t, results[0].Output, "err\n")
+
+	// The trace holds stderr alone, not the output combined with it
+	var rec trace.Record
+	require.NoError(t, json.Unmarshal(records.Bytes(), &rec))
+	assert.Equal(t, []string{"sh", "-c", "echo out; echo err >&2; exit 1"}, rec.Argv)
+	assert.Equal(t, 1, rec.ExitCode)
+	assert.Equal(t, "err", rec.Stderr)
+}
+
+func TestExecAll_NoCommand(t *testing.T) {
+	ws := setupExecWorkspace(t, "main", "api")
+
+	_, err := ws.ExecAll(t.Context(), ExecOptions{Branch: "main"})
+	assert.Error(t, err)
+}
+
+func TestFormatExecResults(t *testing.T) {
+	results := []ExecResult{
+		{RepoName: "api", Status: ExecStatusSucceeded},
+		{RepoName: "web", Status: ExecStatusFailed, ExitCode: 2},
+		{RepoName: "lib", Status: ExecStatusSkipped, ExitCode: -1, ErrorMessage: "no worktree for branch main"},
+	}
+
+	output := FormatExecResults(results)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "api: succeeded (exit 0")
+	assert.Contains(t, lines[1], "web: failed (exit 2")
+	assert.Contains(t, lines[2], "lib: skipped (no worktree for branch main)")
+}
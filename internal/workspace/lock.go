@@ -0,0 +1,330 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
+)
+
+const (
+	// LockFileName is the default name of the workspace lock file
+	LockFileName = ".foundagent.lock"
+
+	// LockFileVersion is the current lock file format version
+	LockFileVersion = 1
+)
+
+// Restore status constants
+const (
+	RestoreStatusCreated = "created"
+	RestoreStatusFailed  = "failed"
+)
+
+// Lock pins every repository in the workspace to an exact commit
+type Lock struct {
+	Version   int          `json:"version"`
+	Workspace string       `json:"workspace"`
+	Branch    string       `json:"branch"`
+	CreatedAt time.Time    `json:"created_at"`
+	Repos     []LockedRepo `json:"repos"`
+}
+
+// LockedRepo records a single repository's pinned commit
+type LockedRepo struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+	SHA    string `json:"sha"`
+	Dirty  bool   `json:"dirty,omitempty"`
+}
+
+// LockPath returns the default path of the workspace lock file
+func (w *Workspace) LockPath() string {
+	return filepath.Join(w.Path, LockFileName)
+}
+
+// CreateLock records the HEAD commit of every selected repo's worktree for a
+// branch. Repos without a worktree for the branch are returned as skipped.
+func (w *Workspace) CreateLock(branch string, sel config.Selector) (*Lock, []string, error) {
+	state, err := w.LoadState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if branch == "" {
+		branch = getCurrentBranch(state)
+	}
+
+	repoNames, err := w.SelectRepoNames(state, sel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lock := &Lock{
+		Version:   LockFileVersion,
+		Workspace: w.Name,
+		Branch:    branch,
+		CreatedAt: time.Now().UTC(),
+		Repos:     []LockedRepo{},
+	}
+
+	var skipped []string
+	for _, name := range repoNames {
+		worktreePath := w.WorktreePath(name, branch)
+		if _, err := os.Stat(worktreePath); err != nil {
+			skipped = append(skipped, name)
+			continue
+		}
+
+		sha, err := git.GetFullHeadSHA(worktreePath)
+		if err != nil {
+			return nil, nil, errors.Wrap(
+				errors.ErrCodeGitOperationFailed,
+				fmt.Sprintf("Failed to read HEAD of %s", name),
+				"Verify the worktree has at least one commit",
+				err,
+			)
+		}
+
+		dirty, _ := git.HasUncommittedChanges(worktreePath)
+
+		lock.Repos = append(lock.Repos, LockedRepo{
+			Name:   name,
+			URL:    state.Repositories[name].URL,
+			Branch: branch,
+			SHA:    sha,
+			Dirty:  dirty,
+		})
+	}
+
+	return lock, skipped, nil
+}
+
+// SaveLock writes a lock file to path
+func SaveLock(path string, lock *Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return errors.Wrap(
+			errors.ErrCodeUnknown,
+			"Failed to marshal lock file",
+			"This is an internal error, please report it",
+			err,
+		)
+	}
+
+	if err := atomicfile.Write(path, append(data, '\n'), 0644); err != nil {
+		return errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to write lock file",
+			"Check that you have write permissions",
+			err,
+		)
+	}
+
+	return nil
+}
+
+// LoadLock reads a lock file from path
+func LoadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrap(
+				errors.ErrCodeFileNotFound,
+				fmt.Sprintf("Lock file not found: %s", path),
+				"Create one with 'fa lock'",
+				err,
+			)
+		}
+		return nil, errors.Wrap(
+			errors.ErrCodePermissionDenied,
+			"Failed to read lock file",
+			"Check file permissions",
+			err,
+		)
+	}
+
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, errors.Wrap(
+			errors.ErrCodeInvalidConfig,
+			"Failed to parse lock file",
+			"Regenerate the lock file with 'fa lock'",
+			err,
+		)
+	}
+
+	if lock.Version > LockFileVersion {
+		return nil, errors.New(
+			errors.ErrCodeInvalidConfig,
+			fmt.Sprintf("Lock file version %d is newer than supported version %d", lock.Version, LockFileVersion),
+			"Upgrade foundagent to restore this lock file",
+		)
+	}
+
+	for i, repo := range lock.Repos {
+		if repo.Name == "" || repo.SHA == "" {
+			return nil, errors.New(
+				errors.ErrCodeInvalidConfig,
+				fmt.Sprintf("Lock file entry %d is missing a name or sha", i),
+				"Regenerate the lock file with 'fa lock'",
+			)
+		}
+	}
+
+	return &lock, nil
+}
+
+// RestoreOptions represents options for restoring worktrees from a lock
+type RestoreOptions struct {
+	Lock   *Lock
+	Name   string // Worktree directory name, and branch name unless Detach is set
+	Detach bool   // Create detached worktrees instead of named branches
+}
+
+// RestoreResult represents the result of restoring a single repo
+type RestoreResult struct {
+	RepoName     string `json:"name"`
+	SHA          string `json:"sha"`
+	WorktreePath string `json:"worktree_path,omitempty"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+	Warning      string `json:"warning,omitempty"`
+}
+
+// RestoreLock creates a worktree at each locked commit. Every locked repo must
+// exist in the workspace and must not already have a worktree named opts.Name.
+func (w *Workspace) RestoreLock(ctx context.Context, opts RestoreOptions) ([]RestoreResult, error) {
+	state, err := w.LoadState()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.validateRestore(state, opts); err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]LockedRepo, len(opts.Lock.Repos))
+	repoNames := make([]string, 0, len(opts.Lock.Repos))
+	for _, repo := range opts.Lock.Repos {
+		locked[repo.Name] = repo
+		repoNames = append(repoNames, repo.Name)
+	}
+	sort.Strings(repoNames)
+
+	sparse := w.SparsePaths()
+	repoConfigs := w.repoConfigs()
+	var mu sync.Mutex
+	warnings := make(map[string]string)
+	parallelResults := ExecuteParallel(ctx, w.Jobs(), repoNames, func(ctx context.Context, repoName string) error {
+		if err := w.restoreRepo(locked[repoName], opts, sparse[repoName]); err != nil {
+			return err
+		}
+		if err := setupWorktree(repoConfigs[repoName], w.WorktreePath(repoName, opts.Name)); err != nil {
+			mu.Lock()
+			warnings[repoName] = err.Error()
+			mu.Unlock()
+		}
+		return nil
+	})
+
+	results := make([]RestoreResult, len(parallelResults))
+	for i, pr := range parallelResults {
+		result := RestoreResult{
+			RepoName: pr.RepoName,
+			SHA:      locked[pr.RepoName].SHA,
+			Status:   RestoreStatusCreated,
+		}
+		if pr.Error != nil {
+			result.Status = RestoreStatusFailed
+			result.Error = pr.Error.Error()
+		} else {
+			result.WorktreePath = w.WorktreePath(pr.RepoName, opts.Name)
+			result.Warning = warnings[pr.RepoName]
+		}
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+func (w *Workspace) validateRestore(state *State, opts RestoreOptions) error {
+	for _, repo := range opts.Lock.Repos {
+		if _, ok := state.Repositories[repo.Name]; !ok {
+			return errors.New(
+				errors.ErrCodeRepoNotFound,
+				fmt.Sprintf("Repository '%s' from lock file not found in workspace", repo.Name),
+				fmt.Sprintf("Add it with 'fa add %s'", repo.URL),
+			)
+		}
+
+		exists, err := w.WorktreeExists(repo.Name, opts.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New(
+				errors.ErrCodeWorktreeExists,
+				fmt.Sprintf("Worktree '%s' already exists in %s", opts.Name, repo.Name),
+				"Choose a different name with --branch, or remove it with 'fa wt remove'",
+			)
+		}
+
+		if !opts.Detach {
+			branchExists, err := git.BranchExists(w.BareRepoPath(repo.Name), opts.Name)
+			if err != nil {
+				return err
+			}
+			if branchExists {
+				return errors.New(
+					errors.ErrCodeBranchExists,
+					fmt.Sprintf("Branch '%s' already exists in %s", opts.Name, repo.Name),
+					"Choose a different name with --branch, or omit --branch to restore detached worktrees",
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Workspace) restoreRepo(repo LockedRepo, opts RestoreOptions, sparse []string) error {
+	bareRepoPath := w.BareRepoPath(repo.Name)
+
+	// The pinned commit may postdate our last fetch
+	if !git.CommitExists(bareRepoPath, repo.SHA) {
+		if err := git.Fetch(bareRepoPath); err != nil {
+			return err
+		}
+		// A dry run only plans the fetch, so it cannot tell
+		if !git.CommitExists(bareRepoPath, repo.SHA) && !dryrun.Enabled() {
+			return fmt.Errorf("commit %s not found in %s", repo.SHA, repo.Name)
+		}
+	}
+
+	worktreePath := w.WorktreePath(repo.Name, opts.Name)
+	if opts.Detach {
+		if err := git.WorktreeAddDetached(bareRepoPath, worktreePath, repo.SHA, sparse...); err != nil {
+			return err
+		}
+		journal.WorktreeAdded(repo.Name, bareRepoPath, worktreePath, "")
+		return nil
+	}
+	if err := git.WorktreeAddNew(bareRepoPath, worktreePath, opts.Name, repo.SHA, sparse...); err != nil {
+		return err
+	}
+	journal.WorktreeCreated(repo.Name, bareRepoPath, worktreePath, opts.Name)
+	return nil
+}
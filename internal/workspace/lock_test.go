@@ -0,0 +1,169 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLockWorkspace creates a workspace whose repos have a real bare clone and
+// a main worktree, returning the HEAD SHA of each repo
+func setupLockWorkspace(t *testing.T, repos ...string) (*Workspace, map[string]string) {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	state := &State{Repositories: map[string]*Repository{}}
+	shas := make(map[string]string)
+	for _, name := range repos {
+		src := filepath.Join(t.TempDir(), name)
+		setupRealGitRepoForPull(t, src)
+		runGit(t, src, "branch", "-M", "main")
+
+		runGit(t, "", "clone", "--bare", src, ws.BareRepoPath(name))
+		runGit(t, "", "--git-dir="+ws.BareRepoPath(name), "worktree", "add", ws.WorktreePath(name, "main"), "main")
+
+		shas[name] = runGit(t, ws.WorktreePath(name, "main"), "rev-parse", "HEAD")
+		state.Repositories[name] = &Repository{Name: name, URL: "https://github.com/org/" + name + ".git"}
+	}
+	require.NoError(t, ws.SaveState(state))
+	return ws, shas
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestCreateLock(t *testing.T) {
+	ws, shas := setupLockWorkspace(t, "api", "web")
+
+	lock, skipped, err := ws.CreateLock("main", config.Selector{})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, LockFileVersion, lock.Version)
+	assert.Equal(t, "main", lock.Branch)
+	require.Len(t, lock.Repos, 2)
+	assert.Equal(t, "api", lock.Repos[0].Name)
+	assert.Equal(t, shas["api"], lock.Repos[0].SHA)
+	assert.Equal(t, "https://github.com/org/api.git", lock.Repos[0].URL)
+	assert.False(t, lock.Repos[0].Dirty)
+}
+
+func TestCreateLock_DirtyAndSkipped(t *testing.T) {
+	ws, _ := setupLockWorkspace(t, "api")
+	require.NoError(t, os.WriteFile(filepath.Join(ws.WorktreePath("api", "main"), "test.txt"), []byte("changed"), 0644))
+
+	lock, _, err := ws.CreateLock("main", config.Selector{})
+	require.NoError(t, err)
+	require.Len(t, lock.Repos, 1)
+	assert.True(t, lock.Repos[0].Dirty)
+
+	lock, skipped, err := ws.CreateLock("feature", config.Selector{})
+	require.NoError(t, err)
+	assert.Empty(t, lock.Repos)
+	assert.Equal(t, []string{"api"}, skipped)
+}
+
+func TestSaveAndLoadLock(t *testing.T) {
+	ws, _ := setupLockWorkspace(t, "api")
+
+	lock, _, err := ws.CreateLock("main", config.Selector{})
+	require.NoError(t, err)
+	require.NoError(t, SaveLock(ws.LockPath(), lock))
+
+	loaded, err := LoadLock(ws.LockPath())
+	require.NoError(t, err)
+	assert.Equal(t, lock.Repos, loaded.Repos)
+	assert.True(t, lock.CreatedAt.Equal(loaded.CreatedAt))
+}
+
+func TestLoadLock_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadLock(filepath.Join(dir, "missing.lock"))
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "bad.lock")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+	_, err = LoadLock(path)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "repos": []}`), 0644))
+	_, err = LoadLock(path)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "repos": [{"name": "api"}]}`), 0644))
+	_, err = LoadLock(path)
+	assert.Error(t, err)
+}
+
+func TestRestoreLock_Detached(t *testing.T) {
+	ws, shas := setupLockWorkspace(t, "api", "web")
+
+	lock, _, err := ws.CreateLock("main", config.Selector{})
+	require.NoError(t, err)
+
+	results, err := ws.RestoreLock(t.Context(), RestoreOptions{Lock: lock, Name: "repro", Detach: true})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for _, r := range results {
+		assert.Equal(t, RestoreStatusCreated, r.Status, r.Error)
+		assert.Equal(t, shas[r.RepoName], runGit(t, r.WorktreePath, "rev-parse", "HEAD"))
+	}
+}
+
+func TestRestoreLock_NamedBranch(t *testing.T) {
+	ws, shas := setupLockWorkspace(t, "api")
+
+	lock, _, err := ws.CreateLock("main", config.Selector{})
+	require.NoError(t, err)
+
+	results, err := ws.RestoreLock(t.Context(), RestoreOptions{Lock: lock, Name: "repro-1"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, RestoreStatusCreated, results[0].Status, results[0].Error)
+
+	path := ws.WorktreePath("api", "repro-1")
+	assert.Equal(t, "repro-1", runGit(t, path, "rev-parse", "--abbrev-ref", "HEAD"))
+	assert.Equal(t, shas["api"], runGit(t, path, "rev-parse", "HEAD"))
+
+	// Restoring onto the same name again is rejected up front
+	_, err = ws.RestoreLock(t.Context(), RestoreOptions{Lock: lock, Name: "repro-1"})
+	assert.Error(t, err)
+}
+
+func TestRestoreLock_UnknownRepo(t *testing.T) {
+	ws, _ := setupLockWorkspace(t, "api")
+
+	lock := &Lock{Version: LockFileVersion, Repos: []LockedRepo{{Name: "other", URL: "https://github.com/org/other.git", SHA: "abc"}}}
+	_, err := ws.RestoreLock(t.Context(), RestoreOptions{Lock: lock, Name: "repro", Detach: true})
+	require.Error(t, err)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeRepoNotFound, faErr.Code)
+	assert.Contains(t, faErr.Remediation, "fa add https://github.com/org/other.git")
+}
+
+func TestRestoreLock_MissingCommit(t *testing.T) {
+	ws, _ := setupLockWorkspace(t, "api")
+
+	lock := &Lock{Version: LockFileVersion, Repos: []LockedRepo{{Name: "api", SHA: strings.Repeat("0", 40)}}}
+	results, err := ws.RestoreLock(t.Context(), RestoreOptions{Lock: lock, Name: "repro", Detach: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, RestoreStatusFailed, results[0].Status)
+}
@@ -0,0 +1,350 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+// StateVersion is the state schema and on-disk layout version written by this release
+const StateVersion = 1
+
+// legacyWorktreesDir is where worktrees lived before state version 1:
+// repos/worktrees/<repo>/<branch>
+const legacyWorktreesDir = WorktreesDir
+
+// StateMigration upgrades workspace state from one version to the next.
+// Upgrade changes the state in memory and runs on every load; Plan returns
+// any on-disk changes, which are only applied by 'fa migrate'.
+type StateMigration struct {
+	From        int
+	Description string
+	Upgrade     func(state *State)
+	Plan        func(w *Workspace) ([]MigrationAction, error)
+}
+
+// MigrationAction is a single change made while migrating a workspace
+type MigrationAction struct {
+	Description string `json:"description"`
+	apply       func() error
+}
+
+// MigrationPlan lists everything 'fa migrate' would change
+type MigrationPlan struct {
+	ConfigFrom int               `json:"config_from"`
+	ConfigTo   int               `json:"config_to"`
+	StateFrom  int               `json:"state_from"`
+	StateTo    int               `json:"state_to"`
+	Actions    []MigrationAction `json:"actions"`
+}
+
+// stateMigrations is the ordered registry of state upgrades. Each entry
+// upgrades state at version From to From+1.
+var stateMigrations = []StateMigration{
+	{
+		From:        0,
+		Description: "Move worktrees from repos/worktrees/<repo>/<branch> to repos/<repo>/worktrees/<branch>",
+		Plan:        planWorktreeLayoutMigration,
+	},
+}
+
+// pendingStateMigrations returns the migrations needed to bring state at the
+// given version up to StateVersion
+func pendingStateMigrations(version int) []StateMigration {
+	var pending []StateMigration
+	for _, m := range stateMigrations {
+		if m.From >= version && m.From < StateVersion {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// migrateState upgrades state in memory. It stops at the first migration that
+// still has on-disk work to do, leaving the version for 'fa migrate' to bump.
+func (w *Workspace) migrateState(state *State) error {
+	if state.Version > StateVersion {
+		return errors.New(
+			errors.ErrCodeInvalidConfig,
+			fmt.Sprintf("State version %d is newer than supported version %d", state.Version, StateVersion),
+			"Upgrade foundagent to use this workspace",
+		)
+	}
+
+	for _, m := range pendingStateMigrations(state.Version) {
+		if m.Plan != nil {
+			actions, err := m.Plan(w)
+			if err != nil {
+				return err
+			}
+			if len(actions) > 0 {
+				return nil
+			}
+		}
+		if m.Upgrade != nil {
+			m.Upgrade(state)
+		}
+		state.Version = m.From + 1
+	}
+
+	return nil
+}
+
+// PlanMigration determines what is needed to bring the workspace config,
+// state and on-disk layout up to the current versions
+func (w *Workspace) PlanMigration() (*MigrationPlan, error) {
+	cfg, err := config.LoadRaw(w.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := w.loadStateFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Version > config.CurrentVersion || state.Version > StateVersion {
+		return nil, errors.New(
+			errors.ErrCodeInvalidConfig,
+			"Workspace was written by a newer version of foundagent",
+			"Upgrade foundagent to use this workspace",
+		)
+	}
+
+	plan := &MigrationPlan{
+		ConfigFrom: cfg.Version,
+		ConfigTo:   config.CurrentVersion,
+		StateFrom:  state.Version,
+		StateTo:    StateVersion,
+		Actions:    []MigrationAction{},
+	}
+
+	for _, m := range config.PendingMigrations(cfg.Version) {
+		plan.Actions = append(plan.Actions, MigrationAction{
+			Description: fmt.Sprintf("Config v%d → v%d: %s", m.From, m.From+1, m.Description),
+		})
+	}
+	if cfg.Version < config.CurrentVersion {
+		plan.Actions = append(plan.Actions, MigrationAction{
+			Description: fmt.Sprintf("Rewrite config at version %d", config.CurrentVersion),
+			apply: func() error {
+				if _, err := config.Migrate(cfg); err != nil {
+					return err
+				}
+				return config.Save(w.Path, cfg)
+			},
+		})
+	}
+
+	pending := pendingStateMigrations(state.Version)
+	for _, m := range pending {
+		plan.Actions = append(plan.Actions, MigrationAction{
+			Description: fmt.Sprintf("State v%d → v%d: %s", m.From, m.From+1, m.Description),
+		})
+		if m.Plan != nil {
+			actions, err := m.Plan(w)
+			if err != nil {
+				return nil, err
+			}
+			plan.Actions = append(plan.Actions, actions...)
+		}
+	}
+	if len(pending) > 0 {
+		plan.Actions = append(plan.Actions, MigrationAction{
+			Description: fmt.Sprintf("Rewrite state at version %d", StateVersion),
+			apply: func() error {
+				for _, m := range pending {
+					if m.Upgrade != nil {
+						m.Upgrade(state)
+					}
+				}
+				state.Version = StateVersion
+				return w.SaveState(state)
+			},
+		})
+	}
+
+	return plan, nil
+}
+
+// IsEmpty returns true if the workspace is already up to date
+func (p *MigrationPlan) IsEmpty() bool {
+	return len(p.Actions) == 0
+}
+
+// ApplyMigration performs every action in the plan, in order
+func (w *Workspace) ApplyMigration(plan *MigrationPlan) error {
+	for _, action := range plan.Actions {
+		if action.apply == nil {
+			continue
+		}
+		if err := action.apply(); err != nil {
+			return errors.Wrap(
+				errors.ErrCodeUnknown,
+				fmt.Sprintf("Migration step failed: %s", action.Description),
+				"Fix the reported problem and run 'fa migrate' again",
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// planWorktreeLayoutMigration plans moving worktrees out of the legacy
+// repos/worktrees/<repo>/<branch> layout
+func planWorktreeLayoutMigration(w *Workspace) ([]MigrationAction, error) {
+	legacyBase := filepath.Join(w.Path, ReposDir, legacyWorktreesDir)
+
+	// A repo that happens to be named "worktrees" is not the legacy layout
+	if _, err := os.Stat(filepath.Join(legacyBase, BareDir)); err == nil {
+		return nil, nil
+	}
+
+	repoEntries, err := os.ReadDir(legacyBase)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var actions []MigrationAction
+	moved := make(map[string]string)
+
+	for _, repoEntry := range repoEntries {
+		if !repoEntry.IsDir() {
+			continue
+		}
+		repoName := repoEntry.Name()
+
+		branchEntries, err := os.ReadDir(filepath.Join(legacyBase, repoName))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, branchEntry := range branchEntries {
+			if !branchEntry.IsDir() {
+				continue
+			}
+			branch := branchEntry.Name()
+			oldPath := filepath.Join(legacyBase, repoName, branch)
+			newPath := w.WorktreePath(repoName, branch)
+			moved[workspaceRelPath(w.Path, oldPath)] = workspaceRelPath(w.Path, newPath)
+
+			actions = append(actions, MigrationAction{
+				Description: fmt.Sprintf("Move %s → %s", workspaceRelPath(w.Path, oldPath), workspaceRelPath(w.Path, newPath)),
+				apply: func() error {
+					return w.moveWorktree(repoName, oldPath, newPath)
+				},
+			})
+		}
+	}
+
+	if len(actions) == 0 {
+		return nil, nil
+	}
+
+	actions = append(actions,
+		MigrationAction{
+			Description: "Rewrite VS Code workspace folder paths",
+			apply: func() error {
+				return w.rewriteWorkspaceFolders(moved)
+			},
+		},
+		MigrationAction{
+			Description: fmt.Sprintf("Remove empty %s", workspaceRelPath(w.Path, legacyBase)),
+			apply: func() error {
+				return removeEmptyDirs(legacyBase)
+			},
+		},
+	)
+
+	return actions, nil
+}
+
+// moveWorktree moves a worktree directory and repairs git's links to it
+func (w *Workspace) moveWorktree(repoName, oldPath, newPath string) error {
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("destination %s already exists", newPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return err
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+
+	bareRepoPath := w.BareRepoPath(repoName)
+	if _, err := os.Stat(bareRepoPath); err != nil {
+		return nil
+	}
+	return git.WorktreeRepair(bareRepoPath, newPath)
+}
+
+// rewriteWorkspaceFolders replaces moved folder paths in the VS Code workspace file
+func (w *Workspace) rewriteWorkspaceFolders(moved map[string]string) error {
+	if _, err := os.Stat(w.VSCodeWorkspacePath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	vscodeWorkspace, err := w.LoadVSCodeWorkspace()
+	if err != nil {
+		return err
+	}
+
+	for i, folder := range vscodeWorkspace.Folders {
+		path := folder.Path
+		if filepath.IsAbs(path) {
+			path = workspaceRelPath(w.Path, path)
+		}
+		if newPath, ok := moved[filepath.Clean(path)]; ok {
+			vscodeWorkspace.Folders[i].Path = newPath
+		}
+	}
+
+	return w.SaveVSCodeWorkspace(vscodeWorkspace)
+}
+
+// removeEmptyDirs removes dir and its subdirectories if they contain no files
+func removeEmptyDirs(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := removeEmptyDirs(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	entries, err = os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return os.Remove(dir)
+	}
+	return nil
+}
+
+// workspaceRelPath returns path relative to base, or path itself if that fails
+func workspaceRelPath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLegacyWorkspace creates an unversioned workspace with a worktree in the
+// old repos/worktrees/<repo>/<branch> layout
+func setupLegacyWorkspace(t *testing.T) (*Workspace, string) {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	src := filepath.Join(t.TempDir(), "api")
+	setupRealGitRepoForPull(t, src)
+	runGit(t, src, "branch", "-M", "main")
+	runGit(t, "", "clone", "--bare", src, ws.BareRepoPath("api"))
+
+	legacyPath := filepath.Join(ws.Path, ReposDir, WorktreesDir, "api", "main")
+	runGit(t, "", "--git-dir="+ws.BareRepoPath("api"), "worktree", "add", legacyPath, "main")
+
+	require.NoError(t, os.WriteFile(ws.StatePath(), []byte(`{"repositories": {"api": {"name": "api", "url": "https://github.com/org/api.git"}}}`), 0644))
+	require.NoError(t, os.WriteFile(ws.ConfigPath(), []byte("workspace:\n  name: test-ws\nrepos:\n  - url: https://github.com/org/api.git\n"), 0644))
+
+	vscode, err := ws.LoadVSCodeWorkspace()
+	require.NoError(t, err)
+	vscode.Folders = append(vscode.Folders, VSCodeFolder{Path: filepath.Join(ReposDir, WorktreesDir, "api", "main")})
+	require.NoError(t, ws.SaveVSCodeWorkspace(vscode))
+
+	return ws, legacyPath
+}
+
+func TestNewWorkspaceIsCurrent(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	plan, err := ws.PlanMigration()
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty())
+}
+
+func TestLoadState_UpgradesInMemory(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	require.NoError(t, os.WriteFile(ws.StatePath(), []byte(`{}`), 0644))
+
+	state, err := ws.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, StateVersion, state.Version)
+
+	raw, err := ws.loadStateFile()
+	require.NoError(t, err)
+	assert.Equal(t, 0, raw.Version)
+}
+
+func TestLoadState_NewerVersion(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	require.NoError(t, os.WriteFile(ws.StatePath(), []byte(`{"version": 99}`), 0644))
+
+	_, err = ws.LoadState()
+	assert.Error(t, err)
+}
+
+func TestMigration_LegacyLayout(t *testing.T) {
+	ws, legacyPath := setupLegacyWorkspace(t)
+
+	// Pending on-disk work keeps the in-memory version at 0
+	state, err := ws.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.Version)
+
+	plan, err := ws.PlanMigration()
+	require.NoError(t, err)
+	assert.Equal(t, 0, plan.ConfigFrom)
+	assert.Equal(t, 0, plan.StateFrom)
+
+	var descriptions []string
+	for _, a := range plan.Actions {
+		descriptions = append(descriptions, a.Description)
+	}
+	assert.Contains(t, descriptions, "Move repos/worktrees/api/main → repos/api/worktrees/main")
+
+	// Planning alone changes nothing
+	assert.DirExists(t, legacyPath)
+
+	require.NoError(t, ws.ApplyMigration(plan))
+
+	newPath := ws.WorktreePath("api", "main")
+	assert.NoDirExists(t, filepath.Join(ws.Path, ReposDir, WorktreesDir))
+	assert.Equal(t, "main", runGit(t, newPath, "rev-parse", "--abbrev-ref", "HEAD"))
+
+	data, err := os.ReadFile(ws.VSCodeWorkspacePath())
+	require.NoError(t, err)
+	var vscode VSCodeWorkspace
+	require.NoError(t, json.Unmarshal(data, &vscode))
+	assert.Contains(t, vscode.Folders, VSCodeFolder{Path: filepath.Join(ReposDir, "api", WorktreesDir, "main")})
+
+	raw, err := ws.loadStateFile()
+	require.NoError(t, err)
+	assert.Equal(t, StateVersion, raw.Version)
+	assert.Contains(t, raw.Repositories, "api")
+
+	cfg, err := config.LoadRaw(ws.Path)
+	require.NoError(t, err)
+	assert.Equal(t, config.CurrentVersion, cfg.Version)
+
+	plan, err = ws.PlanMigration()
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty())
+}
+
+func TestMigration_DestinationExists(t *testing.T) {
+	ws, _ := setupLegacyWorkspace(t)
+	require.NoError(t, os.MkdirAll(ws.WorktreePath("api", "main"), 0755))
+
+	plan, err := ws.PlanMigration()
+	require.NoError(t, err)
+	assert.Error(t, ws.ApplyMigration(plan))
+}
@@ -0,0 +1,285 @@
+package workspace
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+// OverlayResult describes the overlays applied to one worktree
+type OverlayResult struct {
+	Repo         string   `json:"repo"`
+	Branch       string   `json:"branch"`
+	WorktreePath string   `json:"worktree_path"`
+	Copied       []string `json:"copied,omitempty"`
+	Linked       []string `json:"linked,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// overlay is one file or directory placed into a worktree
+type overlay struct {
+	path   string // relative to the worktree
+	source string
+	link   bool
+}
+
+// OverlaysPath returns the directory holding overlay sources. Workspace-wide
+// overlays live at its top; a repo's own overlays live in a subdirectory
+// named after the repo.
+func (w *Workspace) OverlaysPath() string {
+	return filepath.Join(w.Path, FoundagentDir, OverlaysDir)
+}
+
+// RefreshOverlays re-applies overlays to the existing worktrees of the
+// selected repos, so changes to the overlay sources or config reach them.
+// An empty branch means every branch. A worktree that fails is reported in
+// its result and does not stop the others.
+func (w *Workspace) RefreshOverlays(branch string, sel config.Selector) ([]OverlayResult, error) {
+	cfg, err := config.Load(w.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := w.LoadState()
+	if err != nil {
+		return nil, err
+	}
+
+	repoNames, err := w.SelectRepoNames(state, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	allWorktrees, err := w.GetAllWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]OverlayResult, 0)
+	for _, repoName := range repoNames {
+		branches := slices.Sorted(slices.Values(allWorktrees[repoName]))
+		for _, b := range branches {
+			if branch != "" && b != branch {
+				continue
+			}
+
+			worktreePath := w.WorktreePath(repoName, b)
+			result, err := w.applyOverlays(cfg, repoName, worktreePath)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Branch = b
+			results = append(results, *result)
+		}
+	}
+
+	if branch != "" && len(results) == 0 {
+		return nil, errors.New(
+			errors.ErrCodeWorktreeNotFound,
+			fmt.Sprintf("No worktrees found for branch '%s'", branch),
+			"Run 'fa wt list' to see available worktrees",
+		)
+	}
+
+	return results, nil
+}
+
+// applyOverlays copies and symlinks the overlays of a repo into one of its
+// worktrees, replacing what an earlier run placed there. All of them are
+// attempted; the first error is returned along with what was applied.
+func (w *Workspace) applyOverlays(cfg *config.Config, repoName, worktreePath string) (*OverlayResult, error) {
+	result := &OverlayResult{
+		Repo:         repoName,
+		WorktreePath: worktreePath,
+	}
+
+	var firstErr error
+	for _, o := range w.overlaysFor(cfg, repoName) {
+		var err error
+		if o.link {
+			err = linkOverlay(o, worktreePath)
+		} else {
+			err = copyOverlay(o, worktreePath)
+		}
+
+		switch {
+		case err != nil:
+			if firstErr == nil {
+				firstErr = err
+			}
+		case o.link:
+			result.Linked = append(result.Linked, o.path)
+		default:
+			result.Copied = append(result.Copied, o.path)
+		}
+	}
+
+	return result, firstErr
+}
+
+// overlaysFor returns the overlays of a repo, sorted by path. A repo's own
+// overlay replaces a workspace-wide one at the same path.
+func (w *Workspace) overlaysFor(cfg *config.Config, repoName string) []overlay {
+	byPath := make(map[string]overlay)
+	add := func(overlays config.Overlays, sourceDir string) {
+		for _, path := range overlays.Copy {
+			byPath[filepath.Clean(path)] = overlay{path: filepath.Clean(path), source: filepath.Join(sourceDir, path)}
+		}
+		for _, path := range overlays.Symlink {
+			byPath[filepath.Clean(path)] = overlay{path: filepath.Clean(path), source: filepath.Join(sourceDir, path), link: true}
+		}
+	}
+
+	add(cfg.Workspace.Overlays, w.OverlaysPath())
+	for _, repo := range cfg.Repos {
+		if repo.Name == repoName {
+			add(repo.Overlays, filepath.Join(w.OverlaysPath(), repoName))
+		}
+	}
+
+	overlays := make([]overlay, 0, len(byPath))
+	for _, path := range slices.Sorted(maps.Keys(byPath)) {
+		overlays = append(overlays, byPath[path])
+	}
+	return overlays
+}
+
+// linkOverlay symlinks the overlay source into the worktree. An existing
+// symlink is replaced; anything else at the path is left alone.
+func linkOverlay(o overlay, worktreePath string) error {
+	if _, err := os.Stat(o.source); err != nil {
+		return overlaySourceMissing(o)
+	}
+
+	target := filepath.Join(worktreePath, o.path)
+	info, err := os.Lstat(target)
+	exists := err == nil
+	if exists && info.Mode()&fs.ModeSymlink == 0 {
+		return errors.New(
+			errors.ErrCodeInvalidOperation,
+			fmt.Sprintf("Cannot symlink overlay %s: %s already exists", o.path, target),
+			"Remove the file from the worktree, or list it under copy instead of symlink",
+		)
+	}
+	if dryrun.Enabled() {
+		dryrun.Symlink(o.source, target)
+		return nil
+	}
+	if exists {
+		if err := os.Remove(target); err != nil {
+			return overlayFailed(o, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return overlayFailed(o, err)
+	}
+
+	// A relative link keeps working if the workspace is moved
+	source, err := filepath.Rel(filepath.Dir(target), o.source)
+	if err != nil {
+		source = o.source
+	}
+	if err := os.Symlink(source, target); err != nil {
+		return overlayFailed(o, err)
+	}
+	return nil
+}
+
+// copyOverlay copies the overlay source into the worktree, overwriting files
+// at the same paths. A symlink left by an earlier symlink overlay is replaced.
+func copyOverlay(o overlay, worktreePath string) error {
+	info, err := os.Stat(o.source)
+	if err != nil {
+		return overlaySourceMissing(o)
+	}
+
+	target := filepath.Join(worktreePath, o.path)
+	if dryrun.Enabled() {
+		dryrun.Copy(o.source, target)
+		return nil
+	}
+	if existing, err := os.Lstat(target); err == nil && existing.Mode()&fs.ModeSymlink != 0 {
+		if err := os.Remove(target); err != nil {
+			return overlayFailed(o, err)
+		}
+	}
+
+	if !info.IsDir() {
+		if err := copyFile(o.source, target, info.Mode()); err != nil {
+			return overlayFailed(o, err)
+		}
+		return nil
+	}
+
+	err = filepath.WalkDir(o.source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(o.source, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(target, rel), 0755)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(target, rel), info.Mode())
+	})
+	if err != nil {
+		return overlayFailed(o, err)
+	}
+	return nil
+}
+
+// copyFile copies src to dst with the given permissions, creating parent
+// directories as needed
+func copyFile(src, dst string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func overlaySourceMissing(o overlay) error {
+	return errors.New(
+		errors.ErrCodeFileNotFound,
+		fmt.Sprintf("Overlay source not found: %s", o.source),
+		fmt.Sprintf("Create it, or remove %s from the overlays in the config", o.path),
+	)
+}
+
+func overlayFailed(o overlay, err error) error {
+	return errors.Wrap(
+		errors.ErrCodePermissionDenied,
+		fmt.Sprintf("Failed to apply overlay %s", o.path),
+		"Check permissions in the worktree and the overlay directory",
+		err,
+	)
+}
@@ -0,0 +1,143 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOverlayWorkspace returns a workspace with api and web repos and main
+// worktree directories for both. AGENTS.md is symlinked into every worktree,
+// and api also gets a copy of .env and the certs directory.
+func setupOverlayWorkspace(t *testing.T) *Workspace {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	cfg.Workspace.Overlays = config.Overlays{Symlink: []string{"AGENTS.md"}}
+	for _, name := range []string{"api", "web"} {
+		require.NoError(t, ws.AddRepository(&Repository{
+			Name:         name,
+			URL:          "git@github.com:org/" + name + ".git",
+			BareRepoPath: ws.BareRepoPath(name),
+		}))
+		require.NoError(t, os.MkdirAll(ws.WorktreePath(name, "main"), 0755))
+		cfg.Repos = append(cfg.Repos, config.RepoConfig{Name: name, URL: "git@github.com:org/" + name + ".git"})
+	}
+	cfg.Repos[0].Overlays = config.Overlays{Copy: []string{".env", "certs"}}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	writeOverlay(t, ws, "AGENTS.md", "Be nice")
+	writeOverlay(t, ws, "api/.env", "SECRET=1")
+	writeOverlay(t, ws, "api/certs/dev.pem", "cert")
+	return ws
+}
+
+func writeOverlay(t *testing.T, ws *Workspace, path, content string) {
+	t.Helper()
+	source := filepath.Join(ws.OverlaysPath(), path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(source), 0755))
+	require.NoError(t, os.WriteFile(source, []byte(content), 0644))
+}
+
+func TestSetupWorktree_Overlays(t *testing.T) {
+	ws := setupOverlayWorkspace(t)
+	worktreePath := ws.WorktreePath("api", "main")
+
+	require.NoError(t, ws.SetupWorktree("api", worktreePath))
+
+	content, err := os.ReadFile(filepath.Join(worktreePath, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET=1", string(content))
+	content, err = os.ReadFile(filepath.Join(worktreePath, "certs", "dev.pem"))
+	require.NoError(t, err)
+	assert.Equal(t, "cert", string(content))
+
+	// The symlink is relative, and points at the shared source
+	link, err := os.Readlink(filepath.Join(worktreePath, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.False(t, filepath.IsAbs(link))
+	content, err = os.ReadFile(filepath.Join(worktreePath, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "Be nice", string(content))
+
+	// web only gets the workspace-wide overlay
+	webPath := ws.WorktreePath("web", "main")
+	require.NoError(t, ws.SetupWorktree("web", webPath))
+	assert.FileExists(t, filepath.Join(webPath, "AGENTS.md"))
+	assert.NoFileExists(t, filepath.Join(webPath, ".env"))
+}
+
+func TestSetupWorktree_OverlaySourceMissing(t *testing.T) {
+	ws := setupOverlayWorkspace(t)
+	require.NoError(t, os.Remove(filepath.Join(ws.OverlaysPath(), "api", ".env")))
+	worktreePath := ws.WorktreePath("api", "main")
+
+	err := ws.SetupWorktree("api", worktreePath)
+
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeFileNotFound, faErr.Code)
+
+	// The other overlays are still applied
+	assert.FileExists(t, filepath.Join(worktreePath, "AGENTS.md"))
+	assert.FileExists(t, filepath.Join(worktreePath, "certs", "dev.pem"))
+}
+
+func TestRefreshOverlays(t *testing.T) {
+	ws := setupOverlayWorkspace(t)
+	for _, name := range []string{"api", "web"} {
+		require.NoError(t, ws.SetupWorktree(name, ws.WorktreePath(name, "main")))
+	}
+
+	// A copied file changed in the worktree is overwritten by the new source
+	apiPath := ws.WorktreePath("api", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(apiPath, ".env"), []byte("local"), 0644))
+	writeOverlay(t, ws, "api/.env", "SECRET=2")
+
+	// A file in the way of a symlink is reported, not overwritten
+	webPath := ws.WorktreePath("web", "main")
+	require.NoError(t, os.Remove(filepath.Join(webPath, "AGENTS.md")))
+	require.NoError(t, os.WriteFile(filepath.Join(webPath, "AGENTS.md"), []byte("mine"), 0644))
+
+	results, err := ws.RefreshOverlays("", config.Selector{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "api", results[0].Repo)
+	assert.Equal(t, "main", results[0].Branch)
+	assert.Equal(t, []string{".env", "certs"}, results[0].Copied)
+	assert.Equal(t, []string{"AGENTS.md"}, results[0].Linked)
+	assert.Empty(t, results[0].Error)
+	content, err := os.ReadFile(filepath.Join(apiPath, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET=2", string(content))
+
+	assert.Equal(t, "web", results[1].Repo)
+	assert.Contains(t, results[1].Error, "already exists")
+	content, err = os.ReadFile(filepath.Join(webPath, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine", string(content))
+}
+
+func TestRefreshOverlays_Selection(t *testing.T) {
+	ws := setupOverlayWorkspace(t)
+
+	results, err := ws.RefreshOverlays("main", config.Selector{Repos: []string{"web"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "web", results[0].Repo)
+
+	_, err = ws.RefreshOverlays("feature", config.Selector{})
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeWorktreeNotFound, faErr.Code)
+}
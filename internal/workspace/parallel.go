@@ -1,32 +1,125 @@
 package workspace
 
 import (
+	"context"
 	"sync"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
 )
 
+// DefaultJobs is how many repos are worked on at once unless --jobs or
+// settings.jobs says otherwise
+const DefaultJobs = 8
+
+// jobsOverride is set from --jobs and takes precedence over settings.jobs
+var jobsOverride int
+
+// SetJobs overrides settings.jobs for every workspace in this process. Zero
+// or less clears the override.
+func SetJobs(jobs int) {
+	jobsOverride = jobs
+}
+
+// Jobs returns how many repos to work on at once: the --jobs override, else
+// settings.jobs, else DefaultJobs
+func (w *Workspace) Jobs() int {
+	if jobsOverride > 0 {
+		return jobsOverride
+	}
+	if cfg, err := config.Load(w.Path); err == nil && cfg.Settings.Jobs > 0 {
+		return cfg.Settings.Jobs
+	}
+	return DefaultJobs
+}
+
 // ParallelResult represents the result of a parallel operation
 type ParallelResult struct {
 	RepoName string
 	Error    error
 }
 
-// ExecuteParallel runs a function for each repo in parallel
-func ExecuteParallel(repos []string, fn func(repo string) error) []ParallelResult {
-	var wg sync.WaitGroup
+// ExecuteParallel runs a function for each repo, at most jobs at a time, and
+// returns the results in the order of repos. Once ctx is cancelled no more
+// repos are started; those get the cancellation cause as their error.
+func ExecuteParallel(ctx context.Context, jobs int, repos []string, fn func(ctx context.Context, repo string) error) []ParallelResult {
 	results := make([]ParallelResult, len(repos))
+	started := ForEachParallel(ctx, jobs, len(repos), func(ctx context.Context, i int) {
+		results[i] = ParallelResult{
+			RepoName: repos[i],
+			Error:    fn(ctx, repos[i]),
+		}
+	})
+
+	for i, ok := range started {
+		if !ok {
+			results[i] = ParallelResult{
+				RepoName: repos[i],
+				Error:    context.Cause(ctx),
+			}
+		}
+	}
+	return results
+}
 
-	for i, repo := range repos {
+// ForEachParallel calls fn for each index in [0, n), running at most jobs
+// calls at once; jobs of zero or less means DefaultJobs. Indexes are started
+// in order, and none are started once ctx is cancelled. It waits for the
+// calls in flight and reports which indexes were started.
+func ForEachParallel(ctx context.Context, jobs, n int, fn func(ctx context.Context, i int)) []bool {
+	if jobs <= 0 {
+		jobs = DefaultJobs
+	}
+
+	started := make([]bool, n)
+	slots := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		// A free slot and a cancellation can be ready at the same time
+		if ctx.Err() != nil {
+			break
+		}
+
+		started[i] = true
 		wg.Add(1)
-		go func(index int, repoName string) {
+		go func(index int) {
 			defer wg.Done()
-			err := fn(repoName)
-			results[index] = ParallelResult{
-				RepoName: repoName,
-				Error:    err,
-			}
-		}(i, repo)
+			defer func() { <-slots }()
+			fn(ctx, index)
+		}(i)
 	}
 
 	wg.Wait()
-	return results
+	return started
+}
+
+// retryLog collects the retries of git commands run for repos in parallel
+type retryLog struct {
+	mu      sync.Mutex
+	retries map[string][]git.Retry
+}
+
+func newRetryLog() *retryLog {
+	return &retryLog{retries: make(map[string][]git.Retry)}
+}
+
+// recorder returns an OnRetry callback that records retries for repo
+func (l *retryLog) recorder(repo string) func(git.Retry) {
+	return func(r git.Retry) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.retries[repo] = append(l.retries[repo], r)
+	}
+}
+
+// get returns the retries recorded for repo
+func (l *retryLog) get(repo string) []git.Retry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.retries[repo]
 }
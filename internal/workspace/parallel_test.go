@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteParallel_Bounded(t *testing.T) {
+	repos := make([]string, 20)
+	for i := range repos {
+		repos[i] = fmt.Sprintf("repo%d", i)
+	}
+
+	var running, peak atomic.Int32
+	results := ExecuteParallel(t.Context(), 3, repos, func(ctx context.Context, repo string) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	require.Len(t, results, len(repos))
+	for i, r := range results {
+		assert.Equal(t, repos[i], r.RepoName)
+		assert.NoError(t, r.Error)
+	}
+}
+
+func TestExecuteParallel_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(t.Context())
+	repos := []string{"a", "b", "c", "d"}
+
+	var mu sync.Mutex
+	ran := make(map[string]bool)
+	results := ExecuteParallel(ctx, 1, repos, func(ctx context.Context, repo string) error {
+		mu.Lock()
+		ran[repo] = true
+		mu.Unlock()
+		if repo == "b" {
+			cancel(fmt.Errorf("interrupted"))
+		}
+		return nil
+	})
+
+	require.Len(t, results, len(repos))
+	assert.True(t, ran["a"])
+	assert.True(t, ran["b"])
+	assert.False(t, ran["c"])
+	assert.False(t, ran["d"])
+
+	assert.NoError(t, results[1].Error)
+	for _, r := range results[2:] {
+		assert.EqualError(t, r.Error, "interrupted")
+	}
+	assert.Equal(t, "d", results[3].RepoName)
+}
+
+func TestJobs(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	defer SetJobs(0)
+
+	assert.Equal(t, DefaultJobs, ws.Jobs())
+
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	cfg.Settings.Jobs = 2
+	require.NoError(t, config.Save(ws.Path, cfg))
+	assert.Equal(t, 2, ws.Jobs())
+
+	SetJobs(5)
+	assert.Equal(t, 5, ws.Jobs())
+}
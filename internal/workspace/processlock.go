@@ -0,0 +1,191 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/errors"
+)
+
+const (
+	// ProcessLockFileName is the name of the lock file, in FoundagentDir, held
+	// by the fa process that is changing the workspace
+	ProcessLockFileName = "process.lock"
+
+	// DefaultLockTimeout is how long to wait for another fa process to
+	// release the workspace
+	DefaultLockTimeout = 30 * time.Second
+
+	// lockPollInterval is how often a held lock is checked while waiting
+	lockPollInterval = 100 * time.Millisecond
+
+	// lockWriteGrace is how long an unreadable lock file is taken to be in
+	// the middle of being written, rather than left behind by a crash
+	lockWriteGrace = 5 * time.Second
+)
+
+// ProcessLockInfo identifies the fa process holding the workspace lock
+type ProcessLockInfo struct {
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	Command    string    `json:"command"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// ProcessLock is an advisory lock on the workspace, held by one fa process
+// at a time so concurrent processes do not lose each other's updates to the
+// state file and config
+type ProcessLock struct {
+	path string
+	info ProcessLockInfo
+}
+
+// ProcessLockPath returns the path to the workspace's process lock file
+func (w *Workspace) ProcessLockPath() string {
+	return filepath.Join(w.Path, FoundagentDir, ProcessLockFileName)
+}
+
+// AcquireLock takes the workspace lock for this process, waiting up to
+// timeout for another process to release it. A lock left behind by a process
+// that is no longer running on this host is taken over.
+func (w *Workspace) AcquireLock(timeout time.Duration) (*ProcessLock, error) {
+	path := w.ProcessLockPath()
+	host, _ := os.Hostname()
+	info := ProcessLockInfo{
+		PID:        os.Getpid(),
+		Host:       host,
+		Command:    strings.Join(append([]string{"fa"}, os.Args[1:]...), " "),
+		AcquiredAt: time.Now().UTC(),
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(
+			errors.ErrCodeUnknown,
+			"Failed to marshal lock",
+			"This is an internal error, please report it",
+			err,
+		)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, writeErr := file.Write(data)
+			closeErr := file.Close()
+			if writeErr == nil {
+				writeErr = closeErr
+			}
+			if writeErr != nil {
+				_ = os.Remove(path)
+				return nil, lockWriteFailed(writeErr)
+			}
+			return &ProcessLock{path: path, info: info}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, lockWriteFailed(err)
+		}
+
+		holder, stale := readProcessLock(path, host)
+		if stale {
+			// Check again right before removing, in case another process
+			// took over the stale lock in the meantime
+			if again, _ := readProcessLock(path, host); again == holder {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, lockHeld(path, holder)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Release gives up the lock. A lock that another process has since taken
+// over is left alone.
+func (l *ProcessLock) Release() error {
+	if l == nil {
+		return nil
+	}
+
+	host, _ := os.Hostname()
+	if holder, _ := readProcessLock(l.path, host); holder.PID != l.info.PID || !holder.AcquiredAt.Equal(l.info.AcquiredAt) {
+		return nil
+	}
+
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return lockWriteFailed(err)
+	}
+	return nil
+}
+
+// readProcessLock reads the lock file and reports whether it is stale: held
+// by a process on this host that is no longer running, or unreadable for
+// longer than it takes to write it. Locks from other hosts are never stale,
+// since their processes cannot be checked.
+func readProcessLock(path, host string) (ProcessLockInfo, bool) {
+	var holder ProcessLockInfo
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Released between the create attempt and now; just retry
+		return holder, os.IsNotExist(err)
+	}
+
+	if err := json.Unmarshal(data, &holder); err != nil || holder.PID == 0 {
+		info, statErr := os.Stat(path)
+		return ProcessLockInfo{}, statErr == nil && time.Since(info.ModTime()) > lockWriteGrace
+	}
+
+	if holder.Host == host && !processRunning(holder.PID) {
+		return holder, true
+	}
+	return holder, false
+}
+
+// processRunning reports whether a process with the given PID exists on
+// this host
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess only succeeds for running processes on Windows. Elsewhere
+	// it always succeeds, and signal 0 checks for the process; a process
+	// owned by another user refuses the signal but is still running.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return process.Signal(syscall.Signal(0)) != os.ErrProcessDone
+}
+
+func lockHeld(path string, holder ProcessLockInfo) error {
+	holderDesc := "another fa process"
+	if holder.PID != 0 {
+		holderDesc = fmt.Sprintf("'%s' (PID %d on %s, since %s)",
+			holder.Command, holder.PID, holder.Host, holder.AcquiredAt.Local().Format(time.Kitchen))
+	}
+	return errors.New(
+		errors.ErrCodeWorkspaceLocked,
+		fmt.Sprintf("Workspace is locked by %s", holderDesc),
+		fmt.Sprintf("Wait for it to finish, or raise --lock-timeout. If no fa process is running, delete %s", path),
+	)
+}
+
+func lockWriteFailed(err error) error {
+	return errors.Wrap(
+		errors.ErrCodePermissionDenied,
+		"Failed to write workspace lock",
+		"Check that you have write permissions in the .foundagent directory",
+		err,
+	)
+}
@@ -0,0 +1,145 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProcessLock(t *testing.T, ws *Workspace, info ProcessLockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ws.ProcessLockPath(), data, 0644))
+}
+
+func TestAcquireLock(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	lock, err := ws.AcquireLock(0)
+	require.NoError(t, err)
+	assert.FileExists(t, ws.ProcessLockPath())
+
+	// A second acquirer waits for the timeout and reports the holder
+	start := time.Now()
+	_, err = ws.AcquireLock(200 * time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeWorkspaceLocked, faErr.Code)
+	assert.Contains(t, faErr.Message, fmt.Sprintf("PID %d", os.Getpid()))
+
+	require.NoError(t, lock.Release())
+	assert.NoFileExists(t, ws.ProcessLockPath())
+
+	lock, err = ws.AcquireLock(0)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLock_WaitsForRelease(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	lock, err := ws.AcquireLock(0)
+	require.NoError(t, err)
+	time.AfterFunc(200*time.Millisecond, func() { _ = lock.Release() })
+
+	next, err := ws.AcquireLock(5 * time.Second)
+	require.NoError(t, err)
+	require.NoError(t, next.Release())
+}
+
+func TestAcquireLock_Stale(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	host, _ := os.Hostname()
+
+	// A process that has exited leaves a stale lock
+	cmd := exec.Command("git", "--version")
+	require.NoError(t, cmd.Run())
+	writeProcessLock(t, ws, ProcessLockInfo{PID: cmd.Process.Pid, Host: host, Command: "fa sync", AcquiredAt: time.Now()})
+
+	lock, err := ws.AcquireLock(0)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+
+	// So does a crash in the middle of writing the lock
+	require.NoError(t, os.WriteFile(ws.ProcessLockPath(), nil, 0644))
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(ws.ProcessLockPath(), old, old))
+
+	lock, err = ws.AcquireLock(0)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLock_OtherHost(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	// Processes on other hosts cannot be checked, so their locks are kept
+	writeProcessLock(t, ws, ProcessLockInfo{PID: 1 << 30, Host: "elsewhere", Command: "fa add", AcquiredAt: time.Now()})
+
+	_, err = ws.AcquireLock(0)
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeWorkspaceLocked, faErr.Code)
+	assert.Contains(t, faErr.Message, "'fa add'")
+	assert.Contains(t, faErr.Message, "on elsewhere")
+	assert.Contains(t, faErr.Remediation, ws.ProcessLockPath())
+}
+
+func TestProcessLock_ReleaseTakenOver(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	lock, err := ws.AcquireLock(0)
+	require.NoError(t, err)
+
+	// Another process took the lock over; releasing must not remove theirs
+	writeProcessLock(t, ws, ProcessLockInfo{PID: 1 << 30, Host: "elsewhere", AcquiredAt: time.Now()})
+	require.NoError(t, lock.Release())
+	assert.FileExists(t, ws.ProcessLockPath())
+}
+
+func TestAcquireLock_SerializesStateUpdates(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lock, err := ws.AcquireLock(10 * time.Second)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer lock.Release()
+
+			name := fmt.Sprintf("repo%d", i)
+			assert.NoError(t, ws.AddRepository(&Repository{Name: name, URL: "git@github.com:org/" + name + ".git"}))
+		}(i)
+	}
+	wg.Wait()
+
+	state, err := ws.LoadState()
+	require.NoError(t, err)
+	assert.Len(t, state.Repositories, 10)
+}
@@ -1,11 +1,13 @@
 package workspace
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
 
+	"github.com/foundagent/foundagent/gitexec/gittest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -445,3 +447,46 @@ func TestPullAllWorktrees_UncommittedChangesWithoutStash(t *testing.T) {
 		assert.NotNil(t, results[0].Error)
 	}
 }
+
+// TestPullWorktrees_FetchFailedNotPulled tests that a repo whose fetch failed
+// reports the fetch error and is not pulled
+func TestPullWorktrees_FetchFailedNotPulled(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	require.NoError(t, ws.AddRepository(&Repository{Name: "api", DefaultBranch: "main", Worktrees: []string{"main"}}))
+
+	fake := gittest.New()
+	fake.On("fetch").AuthFailure()
+	fake.On()
+	t.Cleanup(fake.Install())
+
+	results, err := ws.PullWorktrees(context.Background(), "main", SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusFailed, results[0].Status)
+	require.Error(t, results[0].Error)
+	assert.Contains(t, results[0].Error.Error(), "Failed to fetch")
+	assert.Zero(t, fake.Count("pull"))
+}
+
+// TestPullWorktrees_FetchRetries tests that the fetch retries of a repo are
+// reported in its pull result
+func TestPullWorktrees_FetchRetries(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+	require.NoError(t, ws.AddRepository(&Repository{Name: "api", DefaultBranch: "main", Worktrees: []string{"main"}}))
+
+	fake := gittest.New()
+	fake.On("fetch").Times(1).TransientFailure()
+	fake.On()
+	t.Cleanup(fake.Install())
+
+	results, err := ws.PullWorktrees(context.Background(), "main", SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusUpdated, results[0].Status)
+	require.Len(t, results[0].Retries, 1)
+	assert.Equal(t, 1, fake.Count("pull"))
+}
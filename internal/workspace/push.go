@@ -1,9 +1,11 @@
 package workspace
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/git"
 )
 
@@ -17,12 +19,13 @@ const (
 
 // PushResult represents the result of pushing a single repo
 type PushResult struct {
-	RepoName      string   `json:"name"`
-	Status        string   `json:"status"` // "pushed", "skipped", "failed"
-	RefsPushed    []string `json:"refs_pushed,omitempty"`
-	CommitsPushed int      `json:"commits_pushed"`
-	Error         error    `json:"-"`
-	ErrorMessage  string   `json:"error,omitempty"`
+	RepoName      string      `json:"name"`
+	Status        string      `json:"status"` // "pushed", "skipped", "failed"
+	RefsPushed    []string    `json:"refs_pushed,omitempty"`
+	CommitsPushed int         `json:"commits_pushed"`
+	Retries       []git.Retry `json:"retries,omitempty"` // Retries after transient network failures
+	Error         error       `json:"-"`
+	ErrorMessage  string      `json:"error,omitempty"`
 }
 
 // PushSummary aggregates push results across all repos
@@ -35,14 +38,14 @@ type PushSummary struct {
 
 // PushOptions represents options for cross-repo push
 type PushOptions struct {
-	DryRun  bool     // Preview without executing
-	Repos   []string // Limit to specific repos (nil = all)
-	Verbose bool     // Show detailed output
-	Force   bool     // Force push (dangerous)
+	DryRun    bool            // Preview without executing
+	Selection config.Selector // Limit to selected repos (empty = all)
+	Verbose   bool            // Show detailed output
+	Force     bool            // Force push (dangerous)
 }
 
 // PushAllReposNew pushes all repos with unpushed commits
-func (w *Workspace) PushAllReposNew(opts PushOptions) ([]PushResult, error) {
+func (w *Workspace) PushAllReposNew(ctx context.Context, opts PushOptions) ([]PushResult, error) {
 	state, err := w.LoadState()
 	if err != nil {
 		return nil, err
@@ -53,7 +56,7 @@ func (w *Workspace) PushAllReposNew(opts PushOptions) ([]PushResult, error) {
 	}
 
 	currentBranch := getPushCurrentBranch(state)
-	repoNames, err := w.filterPushRepoNames(state, opts.Repos)
+	repoNames, err := w.SelectRepoNames(state, opts.Selection)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +65,7 @@ func (w *Workspace) PushAllReposNew(opts PushOptions) ([]PushResult, error) {
 	repoStates := w.preparePushStates(repoNames, currentBranch)
 
 	// Execute pushes in parallel
-	parallelResults := ExecuteParallel(repoNames, func(repoName string) error {
+	parallelResults := ExecuteParallel(ctx, w.Jobs(), repoNames, func(ctx context.Context, repoName string) error {
 		return w.executePush(repoStates[repoName], opts)
 	})
 
@@ -77,53 +80,40 @@ func getPushCurrentBranch(state *State) string {
 	return "main"
 }
 
-func (w *Workspace) filterPushRepoNames(state *State, requestedRepos []string) ([]string, error) {
-	var repoNames []string
-	for name := range state.Repositories {
-		if len(requestedRepos) > 0 && !containsPushString(requestedRepos, name) {
-			continue
-		}
-		repoNames = append(repoNames, name)
-	}
-
-	// Validate requested repos exist
-	for _, r := range requestedRepos {
-		if _, exists := state.Repositories[r]; !exists {
-			return nil, fmt.Errorf("repository '%s' not found in workspace", r)
-		}
-	}
-	return repoNames, nil
-}
-
-func containsPushString(slice []string, s string) bool {
-	for _, item := range slice {
-		if item == s {
-			return true
-		}
-	}
-	return false
-}
-
 type pushRepoState struct {
 	worktreePath  string
+	remote        string // Configured push remote; empty pushes to the upstream
 	hasUnpushed   bool
 	unpushedCount int
 	refspec       string
+	retries       []git.Retry
 }
 
 func (w *Workspace) preparePushStates(repoNames []string, currentBranch string) map[string]*pushRepoState {
 	repoStates := make(map[string]*pushRepoState)
+	repoConfigs := w.repoConfigs()
 
 	for _, repoName := range repoNames {
 		worktreePath := w.WorktreePath(repoName, currentBranch)
-		rs := &pushRepoState{worktreePath: worktreePath}
+		rs := &pushRepoState{
+			worktreePath: worktreePath,
+			remote:       repoConfigs[repoName].PushTarget(),
+		}
 
-		hasUnpushed, _ := git.HasUnpushedCommits(worktreePath)
-		rs.hasUnpushed = hasUnpushed
+		if rs.remote != "" {
+			rs.unpushedCount, _ = git.GetUnpushedCountTo(worktreePath, rs.remote)
+			rs.hasUnpushed = rs.unpushedCount > 0
+			if rs.hasUnpushed {
+				rs.refspec = fmt.Sprintf("%s -> %s/%s", currentBranch, rs.remote, currentBranch)
+			}
+		} else {
+			hasUnpushed, _ := git.HasUnpushedCommits(worktreePath)
+			rs.hasUnpushed = hasUnpushed
 
-		if hasUnpushed {
-			rs.unpushedCount, _ = git.GetUnpushedCount(worktreePath)
-			rs.refspec, _ = git.GetPushRefspec(worktreePath)
+			if hasUnpushed {
+				rs.unpushedCount, _ = git.GetUnpushedCount(worktreePath)
+				rs.refspec, _ = git.GetPushRefspec(worktreePath)
+			}
 		}
 
 		repoStates[repoName] = rs
@@ -138,7 +128,11 @@ func (w *Workspace) executePush(rs *pushRepoState, opts PushOptions) error {
 	if opts.DryRun {
 		return nil
 	}
-	return git.PushWithOptions(rs.worktreePath, opts.Force)
+	return git.PushBranch(rs.worktreePath, git.PushOptions{
+		Remote:  rs.remote,
+		Force:   opts.Force,
+		OnRetry: func(r git.Retry) { rs.retries = append(rs.retries, r) },
+	})
 }
 
 func (w *Workspace) buildPushResults(parallelResults []ParallelResult, repoStates map[string]*pushRepoState, opts PushOptions) []PushResult {
@@ -151,7 +145,7 @@ func (w *Workspace) buildPushResults(parallelResults []ParallelResult, repoState
 }
 
 func buildSinglePushResult(pr ParallelResult, rs *pushRepoState, opts PushOptions) PushResult {
-	result := PushResult{RepoName: pr.RepoName}
+	result := PushResult{RepoName: pr.RepoName, Retries: rs.retries}
 
 	switch {
 	case pr.Error != nil:
@@ -239,5 +233,5 @@ func FormatPushResults(results []PushResult) string {
 // GetPushDryRunPreview returns preview info for dry-run mode
 func (w *Workspace) GetPushDryRunPreview(opts PushOptions) ([]PushResult, error) {
 	opts.DryRun = true
-	return w.PushAllReposNew(opts)
+	return w.PushAllReposNew(context.Background(), opts)
 }
@@ -15,12 +15,22 @@ type ReconcileResult struct {
 
 // Reconcile compares config with state and determines what actions to take
 func Reconcile(ws *Workspace) (*ReconcileResult, error) {
+	return ReconcileSelected(ws, config.Selector{})
+}
+
+// ReconcileSelected reconciles only the config repos matched by the selector
+func ReconcileSelected(ws *Workspace, sel config.Selector) (*ReconcileResult, error) {
 	// Load config
 	cfg, err := config.Load(ws.Path)
 	if err != nil {
 		return nil, err
 	}
 
+	selected, err := sel.Filter(cfg.Repos)
+	if err != nil {
+		return nil, err
+	}
+
 	// Load state
 	state, err := ws.LoadState()
 	if err != nil {
@@ -33,8 +43,8 @@ func Reconcile(ws *Workspace) (*ReconcileResult, error) {
 		ReposStale:    []string{},
 	}
 
-	// Check which repos from config need to be cloned
-	for _, repo := range cfg.Repos {
+	// Check which selected repos from config need to be cloned
+	for _, repo := range selected {
 		name := repo.Name
 		if name == "" {
 			// Name should have been inferred during validation
@@ -86,6 +96,6 @@ func PrintReconcileResult(result *ReconcileResult) {
 			fmt.Printf("  - %s\n", name)
 		}
 		fmt.Printf("\nTo clean up: fa remove %s\n", result.ReposStale[0])
-		fmt.Printf("To keep: Add them back to .foundagent.yaml\n")
+		fmt.Printf("To keep: Add them back to the workspace config\n")
 	}
 }
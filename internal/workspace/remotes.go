@@ -0,0 +1,81 @@
+package workspace
+
+import (
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+// SetupRemotes adds the repo's configured remotes to its bare clone and
+// fetches them, so branches can be created from upstream/<branch> right away.
+// Repos without remotes in the config are left alone.
+func (w *Workspace) SetupRemotes(repoName string) error {
+	repoConfig := w.repoConfigs()[repoName]
+	if len(repoConfig.Remotes) == 0 {
+		return nil
+	}
+
+	bareRepoPath := w.BareRepoPath(repoName)
+	if err := configureRemotes(bareRepoPath, repoConfig); err != nil {
+		return err
+	}
+	return fetchRemotes(bareRepoPath, repoConfig, nil, nil, nil)
+}
+
+// configureRemotes adds or updates every configured remote other than origin,
+// which is the URL the repo was cloned from. Origin's branches are then
+// tracked as origin/<branch> alongside the other remotes' branches.
+func configureRemotes(bareRepoPath string, repoConfig config.RepoConfig) error {
+	if len(repoConfig.Remotes) == 0 {
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(repoConfig.Remotes)) {
+		if name == config.DefaultRemote {
+			continue
+		}
+		if err := git.SetRemote(bareRepoPath, name, repoConfig.Remotes[name]); err != nil {
+			return err
+		}
+	}
+	return git.TrackRemoteBranches(bareRepoPath, config.DefaultRemote)
+}
+
+// fetchRemotes fetches origin and then every other remote of the bare clone,
+// including remotes added outside foundagent. Depth and filter carry over to
+// every remote; single-branch fetching only applies to origin. onRetry, if
+// set, is called before each retry of a fetch, and progress, if set, receives
+// git's progress output.
+func fetchRemotes(bareRepoPath string, repoConfig config.RepoConfig, repo *Repository, onRetry func(git.Retry), progress io.Writer) error {
+	opts := fetchOptions(bareRepoPath, repoConfig, repo)
+	opts.OnRetry = onRetry
+	opts.Progress = progress
+	if err := git.FetchWithOptions(bareRepoPath, opts); err != nil {
+		return err
+	}
+
+	remotes, err := git.ListRemotes(bareRepoPath)
+	if err != nil {
+		return err
+	}
+
+	for _, remote := range remotes {
+		if remote == config.DefaultRemote {
+			continue
+		}
+		err := git.FetchWithOptions(bareRepoPath, git.FetchOptions{
+			Remote:   remote,
+			Depth:    opts.Depth,
+			Filter:   opts.Filter,
+			OnRetry:  onRetry,
+			Progress: progress,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
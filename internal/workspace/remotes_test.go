@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupForkWorkspace returns a workspace with an "app" repo cloned from a
+// fork, configured with the canonical repo as upstream. The canonical repo
+// has one commit the fork lacks. It also returns the fork and default branch.
+func setupForkWorkspace(t *testing.T) (*Workspace, string, string) {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	canonical := filepath.Join(t.TempDir(), "canonical")
+	setupRealGitRepoForPull(t, canonical)
+	branch := runGit(t, canonical, "rev-parse", "--abbrev-ref", "HEAD")
+	fork := filepath.Join(t.TempDir(), "fork.git")
+	runGit(t, "", "clone", "--bare", "-q", canonical, fork)
+	runGit(t, canonical, "commit", "--allow-empty", "-m", "upstream only")
+
+	bareRepoPath := ws.BareRepoPath("app")
+	require.NoError(t, git.CloneBare(fork, bareRepoPath, false))
+	require.NoError(t, ws.AddRepository(&Repository{
+		Name:          "app",
+		URL:           "file://" + fork,
+		DefaultBranch: branch,
+		BareRepoPath:  bareRepoPath,
+	}))
+
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = append(cfg.Repos, config.RepoConfig{
+		Name:    "app",
+		URL:     "file://" + fork,
+		Remotes: map[string]string{"upstream": "file://" + canonical},
+	})
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	return ws, fork, branch
+}
+
+func TestFetchRepos_AllRemotes(t *testing.T) {
+	ws, _, branch := setupForkWorkspace(t)
+	bareRepoPath := ws.BareRepoPath("app")
+
+	results, err := ws.FetchRepos(t.Context(), SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+
+	remotes, err := git.ListRemotes(bareRepoPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"origin", "upstream"}, remotes)
+	assert.True(t, git.RemoteBranchExists(bareRepoPath, "origin/"+branch))
+	assert.True(t, git.RemoteBranchExists(bareRepoPath, "upstream/"+branch))
+
+	ahead := runGit(t, "", "--git-dir="+bareRepoPath, "rev-list", "--count", "origin/"+branch+"..upstream/"+branch)
+	assert.Equal(t, "1", ahead)
+}
+
+func TestSetupRemotes_NoRemotesConfigured(t *testing.T) {
+	ws, _, _ := setupForkWorkspace(t)
+	require.NoError(t, config.UnsetValue(ws.Path, "repos.app.remotes"))
+
+	require.NoError(t, ws.SetupRemotes("app"))
+
+	remotes, err := git.ListRemotes(ws.BareRepoPath("app"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"origin"}, remotes)
+}
+
+func TestPushAllReposNew_PushRemote(t *testing.T) {
+	ws, fork, branch := setupForkWorkspace(t)
+	require.NoError(t, ws.SetupRemotes("app"))
+
+	// Branch off upstream and commit, as in a fork workflow
+	worktreePath := ws.WorktreePath("app", "feature")
+	require.NoError(t, git.WorktreeAddNew(ws.BareRepoPath("app"), worktreePath, "feature", "upstream/"+branch))
+	runGit(t, worktreePath, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", "feature")
+
+	state, err := ws.LoadState()
+	require.NoError(t, err)
+	state.CurrentBranch = "feature"
+	require.NoError(t, ws.SaveState(state))
+
+	results, err := ws.PushAllReposNew(t.Context(), PushOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, PushStatusPushed, results[0].Status, results[0].ErrorMessage)
+	assert.Equal(t, 1, results[0].CommitsPushed)
+	assert.Equal(t, []string{"feature -> origin/feature"}, results[0].RefsPushed)
+
+	// The branch landed on the fork, and nothing is left to push
+	assert.True(t, git.CommitExists(fork, runGit(t, worktreePath, "rev-parse", "HEAD")))
+	results, err = ws.PushAllReposNew(t.Context(), PushOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, PushStatusSkipped, results[0].Status)
+}
+
+func TestPushRepos_PushRemote(t *testing.T) {
+	ws, fork, branch := setupForkWorkspace(t)
+	require.NoError(t, config.SetValue(ws.Path, "repos.app.push_remote", "origin"))
+	require.NoError(t, ws.SetupRemotes("app"))
+
+	worktreePath := ws.WorktreePath("app", "feature")
+	require.NoError(t, git.WorktreeAddNew(ws.BareRepoPath("app"), worktreePath, "feature", "upstream/"+branch))
+	runGit(t, worktreePath, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", "feature")
+
+	results, err := ws.PushRepos(t.Context(), SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusPushed, results[0].Status, results[0].Error)
+	assert.True(t, results[0].Pushed)
+
+	// The fork's branch moved to the pushed commit
+	head := runGit(t, worktreePath, "rev-parse", "HEAD")
+	assert.Equal(t, head, runGit(t, "", "--git-dir="+fork, "rev-parse", "refs/heads/feature"))
+
+	results, err = ws.PushRepos(t.Context(), SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusNothingPush, results[0].Status)
+}
@@ -3,12 +3,14 @@ package workspace
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/journal"
 )
 
 // RemovalResult represents the result of a repo removal operation
@@ -96,9 +98,11 @@ func (w *Workspace) RemoveRepo(repoName string, force bool, configOnly bool) Rem
 	}
 	result.WorktreesDeleted = worktreesDeleted
 
-	// Remove bare clone
+	// Remove bare clone, into the journal if it is being kept
 	bareRepoPath := w.BareRepoPath(repoName)
-	err = os.RemoveAll(bareRepoPath)
+	if !journal.TrashRepo(repoName, bareRepoPath) {
+		err = dryrun.RemoveAll(bareRepoPath)
+	}
 	if err != nil {
 		result.Error = fmt.Sprintf("failed to delete bare clone: %v", err)
 		return result
@@ -185,21 +189,22 @@ func (w *Workspace) removeAllWorktrees(repoName string) (int, error) {
 
 		worktreePath := filepath.Join(worktreeBase, entry.Name())
 
-		// Use git worktree remove
-		cmd := exec.Command("git", "--git-dir="+bareRepoPath, "worktree", "remove", "--force", worktreePath)
-		err := cmd.Run()
+		// Use git worktree remove, keeping the worktree's changes for 'fa undo'
+		saved := journal.SaveWorktree(repoName, bareRepoPath, worktreePath)
+		err := git.WorktreeRemove(bareRepoPath, worktreePath, true)
 		if err != nil {
 			// If git worktree remove fails, try manual removal
-			err = os.RemoveAll(worktreePath)
+			err = dryrun.RemoveAll(worktreePath)
 			if err != nil {
 				return count, fmt.Errorf("failed to remove worktree %s: %v", entry.Name(), err)
 			}
 		}
+		journal.Record(saved)
 		count++
 	}
 
 	// Remove the worktree base directory
-	err = os.RemoveAll(worktreeBase)
+	err = dryrun.RemoveAll(worktreeBase)
 	if err != nil {
 		return count, err
 	}
@@ -207,10 +212,10 @@ func (w *Workspace) removeAllWorktrees(repoName string) (int, error) {
 	return count, nil
 }
 
-// removeRepoFromConfig removes a repo from .foundagent.yaml
+// removeRepoFromConfig removes a repo from the workspace config file
 func (w *Workspace) removeRepoFromConfig(repoName string) error {
-	// Load using the config package
-	cfg, err := w.loadFoundagentConfig()
+	// Edit only the local file so included entries are not copied into it
+	cfg, err := config.LoadLocal(w.Path)
 	if err != nil {
 		return err
 	}
@@ -224,6 +229,16 @@ func (w *Workspace) removeRepoFromConfig(repoName string) error {
 	}
 
 	cfg.Repos = newRepos
+
+	// A repo that comes from an included file has to be excluded instead
+	included, err := config.IsIncluded(w.Path, repoName)
+	if err != nil {
+		return err
+	}
+	if included && !slices.Contains(cfg.Exclude, repoName) {
+		cfg.Exclude = append(cfg.Exclude, repoName)
+	}
+
 	return w.saveFoundagentConfig(cfg)
 }
 
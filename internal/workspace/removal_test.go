@@ -68,6 +68,60 @@ func TestRemoveRepoFromConfig(t *testing.T) {
 	assert.Len(t, loadedCfg.Repos, 0)
 }
 
+func TestRemoveRepoFromConfig_Included(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws, err := New("test-workspace", tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	// Repo comes from a shared team file
+	teamPath := filepath.Join(ws.Path, "team.foundagent.yaml")
+	require.NoError(t, os.WriteFile(teamPath, []byte("repos:\n  - url: https://github.com/test/repo.git\n    name: test-repo\n"), 0644))
+
+	cfg := config.DefaultConfig(ws.Name)
+	cfg.Include = []string{"team.foundagent.yaml"}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	loadedCfg, err := ws.loadFoundagentConfig()
+	require.NoError(t, err)
+	assert.Len(t, loadedCfg.Repos, 1)
+
+	// Removing it excludes it locally instead of editing the team file
+	require.NoError(t, ws.removeRepoFromConfig("test-repo"))
+
+	loadedCfg, err = ws.loadFoundagentConfig()
+	require.NoError(t, err)
+	assert.Len(t, loadedCfg.Repos, 0)
+
+	localCfg, err := config.LoadLocal(ws.Path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-repo"}, localCfg.Exclude)
+	assert.Empty(t, localCfg.Repos)
+}
+
+func TestRemoveRepoFromConfig_KeepsIncludedSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws, err := New("test-workspace", tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	// The local file leaves settings to the team file
+	configPath := filepath.Join(ws.Path, ".foundagent.yaml")
+	require.NoError(t, os.WriteFile(filepath.Join(ws.Path, "team.yaml"), []byte("settings:\n  auto_create_worktree: true\n"), 0644))
+	require.NoError(t, os.WriteFile(configPath, []byte("workspace:\n  name: test-workspace\ninclude:\n  - team.yaml\nrepos:\n  - url: https://github.com/test/repo.git\n    name: test-repo\n"), 0644))
+
+	require.NoError(t, ws.removeRepoFromConfig("test-repo"))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "test-repo")
+	assert.NotContains(t, string(data), "auto_create_worktree")
+
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Settings.AutoCreateWorktree)
+}
+
 func TestRemoveRepoFromWorkspaceFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	ws, err := New("test-workspace", tmpDir)
@@ -0,0 +1,63 @@
+package workspace
+
+import (
+	"sort"
+
+	"github.com/foundagent/foundagent/internal/config"
+)
+
+// SelectRepoNames returns the sorted names of repositories in state that match
+// the selector. Group and tag membership is read from the workspace config.
+func (w *Workspace) SelectRepoNames(state *State, sel config.Selector) ([]string, error) {
+	repos := make([]config.RepoConfig, 0, len(state.Repositories))
+	for name, repo := range state.Repositories {
+		repos = append(repos, config.RepoConfig{Name: name, URL: repo.URL})
+	}
+
+	// Group and tag labels only live in config
+	if len(sel.Groups) > 0 || len(sel.Tags) > 0 {
+		cfg, err := config.Load(w.Path)
+		if err != nil {
+			return nil, err
+		}
+		labels := make(map[string]config.RepoConfig)
+		for _, r := range cfg.Repos {
+			labels[r.Name] = r
+		}
+		for i := range repos {
+			if r, ok := labels[repos[i].Name]; ok {
+				repos[i].Groups = r.Groups
+				repos[i].Tags = r.Tags
+			}
+		}
+	}
+
+	selected, err := sel.Filter(repos)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(selected))
+	for i, r := range selected {
+		names[i] = r.Name
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// selectedSet returns the selected repo names as a set, or nil when the
+// selector is empty and everything is selected
+func (w *Workspace) selectedSet(state *State, sel config.Selector) (map[string]bool, error) {
+	if sel.IsEmpty() {
+		return nil, nil
+	}
+	names, err := w.SelectRepoNames(state, sel)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,68 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSelectionWorkspace(t *testing.T) (*Workspace, *State) {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	cfg, err := config.Load(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = []config.RepoConfig{
+		{URL: "https://github.com/org/api.git", Name: "api", Groups: []string{"backend"}},
+		{URL: "https://github.com/org/web.git", Name: "web", Groups: []string{"frontend"}, Tags: []string{"js"}},
+		{URL: "https://github.com/org/lib.git", Name: "lib", Groups: []string{"backend"}, Tags: []string{"js"}},
+	}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	state := &State{Repositories: map[string]*Repository{}}
+	for _, r := range cfg.Repos {
+		state.Repositories[r.Name] = &Repository{Name: r.Name, URL: r.URL}
+	}
+	require.NoError(t, ws.SaveState(state))
+	return ws, state
+}
+
+func TestSelectRepoNames(t *testing.T) {
+	ws, state := setupSelectionWorkspace(t)
+
+	names, err := ws.SelectRepoNames(state, config.Selector{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "lib", "web"}, names)
+
+	names, err = ws.SelectRepoNames(state, config.Selector{Groups: []string{"backend"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "lib"}, names)
+
+	names, err = ws.SelectRepoNames(state, config.Selector{Tags: []string{"js"}, Repos: []string{"api"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "lib", "web"}, names)
+}
+
+func TestSelectRepoNames_Unknown(t *testing.T) {
+	ws, state := setupSelectionWorkspace(t)
+
+	_, err := ws.SelectRepoNames(state, config.Selector{Repos: []string{"nope"}})
+	assert.Error(t, err)
+
+	_, err = ws.SelectRepoNames(state, config.Selector{Groups: []string{"mobile"}})
+	assert.Error(t, err)
+}
+
+func TestReconcileSelected(t *testing.T) {
+	ws, _ := setupSelectionWorkspace(t)
+	require.NoError(t, ws.SaveState(&State{}))
+
+	result, err := ReconcileSelected(ws, config.Selector{Groups: []string{"frontend"}})
+	require.NoError(t, err)
+	require.Len(t, result.ReposToClone, 1)
+	assert.Equal(t, "web", result.ReposToClone[0].Name)
+}
@@ -0,0 +1,55 @@
+package workspace
+
+import (
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+// SetupWorktree prepares a newly created worktree of a repo: submodules are
+// initialised when the repo sets submodules: recursive, Git LFS files are
+// downloaded when the repo uses LFS, and the configured overlays are copied
+// or symlinked in. The worktree is usable even if this fails, so callers
+// report the error as a warning.
+func (w *Workspace) SetupWorktree(repoName, worktreePath string) error {
+	// Like repoConfigs, a config that fails to load means no extra setup
+	cfg, err := config.Load(w.Path)
+	if err != nil {
+		return setupWorktree(config.RepoConfig{}, worktreePath)
+	}
+
+	var repo config.RepoConfig
+	for _, r := range cfg.Repos {
+		if r.Name == repoName {
+			repo = r
+		}
+	}
+
+	setupErr := setupWorktree(repo, worktreePath)
+	_, overlayErr := w.applyOverlays(cfg, repoName, worktreePath)
+
+	if setupErr != nil {
+		return setupErr
+	}
+	return overlayErr
+}
+
+// setupWorktree brings a worktree's submodules and LFS files in line with its
+// checkout. Both steps run even if the first fails; the first error is returned.
+func setupWorktree(repo config.RepoConfig, worktreePath string) error {
+	submoduleErr := updateSubmodules(repo, worktreePath)
+	lfsErr := pullLFS(worktreePath)
+
+	if submoduleErr != nil {
+		return submoduleErr
+	}
+	return lfsErr
+}
+
+// pullLFS replaces LFS pointer files in a worktree with their contents, if the
+// repo uses Git LFS
+func pullLFS(worktreePath string) error {
+	if !git.UsesLFS(worktreePath) {
+		return nil
+	}
+	return git.LFSPull(worktreePath)
+}
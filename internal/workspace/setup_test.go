@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLFSWorktree returns a workspace and a worktree whose .gitattributes
+// uses LFS. git lfs is replaced by a stub that logs its arguments when
+// installed is true; the log file is returned.
+func setupLFSWorktree(t *testing.T, installed bool) (*Workspace, string, string) {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	worktree := t.TempDir()
+	runGit(t, worktree, "init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644))
+
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+	bin := t.TempDir()
+	require.NoError(t, os.Symlink(gitPath, filepath.Join(bin, "git")))
+
+	logFile := filepath.Join(t.TempDir(), "lfs.log")
+	if installed {
+		script := "#!/bin/sh\necho \"$@\" >> " + logFile + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "git-lfs"), []byte(script), 0755))
+	}
+	t.Setenv("PATH", bin)
+
+	return ws, worktree, logFile
+}
+
+func TestSetupWorktree_LFS(t *testing.T) {
+	ws, worktree, logFile := setupLFSWorktree(t, true)
+
+	require.NoError(t, ws.SetupWorktree("app", worktree))
+
+	log, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, strings.Fields(string(log)), "fetch")
+	assert.Contains(t, strings.Fields(string(log)), "checkout")
+}
+
+func TestSetupWorktree_LFSNotInstalled(t *testing.T) {
+	ws, worktree, _ := setupLFSWorktree(t, false)
+
+	err := ws.SetupWorktree("app", worktree)
+
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeGitLFSNotInstalled, faErr.Code)
+}
+
+func TestSetupWorktree_NoLFS(t *testing.T) {
+	ws, worktree, logFile := setupLFSWorktree(t, true)
+	require.NoError(t, os.Remove(filepath.Join(worktree, ".gitattributes")))
+
+	require.NoError(t, ws.SetupWorktree("app", worktree))
+
+	assert.NoFileExists(t, logFile)
+}
+
+func TestPullWorktrees_SetupErrorIsWarning(t *testing.T) {
+	ws, app, _ := setupSubmoduleWorkspace(t, config.SubmodulesNone)
+
+	// The pulled commit starts using LFS, which is not installed
+	require.NoError(t, os.WriteFile(filepath.Join(app, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644))
+	runGit(t, app, "add", ".gitattributes")
+	runGit(t, app, "commit", "-m", "Use LFS")
+
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+	bin := t.TempDir()
+	require.NoError(t, os.Symlink(gitPath, filepath.Join(bin, "git")))
+	t.Setenv("PATH", bin)
+
+	results, err := ws.PullWorktrees(context.Background(), "main", SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusUpdated, results[0].Status)
+	assert.NoError(t, results[0].Error)
+	assert.Contains(t, results[0].Warning, "LFS")
+	assert.FileExists(t, filepath.Join(ws.WorktreePath("app", "main"), ".gitattributes"))
+}
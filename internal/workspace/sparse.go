@@ -0,0 +1,181 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+// SparseResult describes a worktree's sparse checkout after a change
+type SparseResult struct {
+	Repo         string   `json:"repo"`
+	Branch       string   `json:"branch"`
+	WorktreePath string   `json:"worktree_path"`
+	Paths        []string `json:"paths"`
+}
+
+// SparsePaths returns the configured sparse checkout directories of every repo
+// that declares them. A config that fails to load yields an empty map, so
+// worktrees are created in full rather than not at all.
+func (w *Workspace) SparsePaths() map[string][]string {
+	paths := make(map[string][]string)
+	for name, repo := range w.repoConfigs() {
+		if len(repo.Sparse) > 0 {
+			paths[name] = repo.Sparse
+		}
+	}
+	return paths
+}
+
+// SparseAdd adds directories to the sparse checkout of an existing worktree.
+// An empty branch means the current branch.
+func (w *Workspace) SparseAdd(repoName, branch string, dirs []string) (*SparseResult, error) {
+	worktreePath, branch, err := w.sparseWorktree(repoName, branch, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := git.SparseCheckoutAdd(worktreePath, dirs); err != nil {
+		return nil, err
+	}
+
+	return w.sparseResult(repoName, branch, worktreePath)
+}
+
+// SparseRemove removes directories from the sparse checkout of an existing
+// worktree. An empty branch means the current branch.
+func (w *Workspace) SparseRemove(repoName, branch string, dirs []string) (*SparseResult, error) {
+	worktreePath, branch, err := w.sparseWorktree(repoName, branch, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := git.SparseCheckoutList(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if !slices.Contains(current, dir) {
+			return nil, errors.New(
+				errors.ErrCodeInvalidInput,
+				fmt.Sprintf("'%s' is not in the sparse checkout of %s", dir, repoName),
+				fmt.Sprintf("Checked out directories: %s", strings.Join(current, ", ")),
+			)
+		}
+	}
+
+	remaining := make([]string, 0, len(current))
+	for _, path := range current {
+		if !slices.Contains(dirs, path) {
+			remaining = append(remaining, path)
+		}
+	}
+
+	if err := git.SparseCheckoutSet(worktreePath, remaining); err != nil {
+		return nil, err
+	}
+
+	return w.sparseResult(repoName, branch, worktreePath)
+}
+
+// sparseWorktree validates a sparse change and returns the worktree it applies to
+func (w *Workspace) sparseWorktree(repoName, branch string, dirs []string) (string, string, error) {
+	state, err := w.LoadState()
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, ok := state.Repositories[repoName]; !ok {
+		return "", "", errors.New(
+			errors.ErrCodeRepoNotFound,
+			fmt.Sprintf("Repository '%s' not found in workspace", repoName),
+			"Run 'fa status' to see available repositories",
+		)
+	}
+
+	if branch == "" {
+		branch = w.branchAtCwd(repoName)
+	}
+	if branch == "" {
+		branch = getCurrentBranch(state)
+	}
+
+	worktreePath := w.WorktreePath(repoName, branch)
+	if _, err := os.Stat(worktreePath); err != nil {
+		return "", "", errors.New(
+			errors.ErrCodeWorktreeNotFound,
+			fmt.Sprintf("No worktree for branch '%s' in %s", branch, repoName),
+			"Choose another branch with --branch, or create it with 'fa wt create'",
+		)
+	}
+
+	for _, dir := range dirs {
+		if !config.ValidSparseDir(dir) {
+			return "", "", errors.New(
+				errors.ErrCodeInvalidInput,
+				fmt.Sprintf("Invalid sparse directory: %q", dir),
+				"Use directory paths relative to the repository root, without wildcards",
+			)
+		}
+	}
+
+	if !git.IsSparse(worktreePath) {
+		return "", "", errors.New(
+			errors.ErrCodeInvalidOperation,
+			fmt.Sprintf("Worktree %s/%s does not use sparse checkout", repoName, branch),
+			fmt.Sprintf("Set repos.%s.sparse in the config to create sparse worktrees", repoName),
+		)
+	}
+
+	return worktreePath, branch, nil
+}
+
+func (w *Workspace) sparseResult(repoName, branch, worktreePath string) (*SparseResult, error) {
+	paths, err := git.SparseCheckoutList(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SparseResult{
+		Repo:         repoName,
+		Branch:       branch,
+		WorktreePath: worktreePath,
+		Paths:        paths,
+	}, nil
+}
+
+// branchAtCwd returns the branch of the repo worktree containing the current
+// directory, or "" when the current directory is not inside one
+func (w *Workspace) branchAtCwd(repoName string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	base := filepath.Join(w.Path, ReposDir, repoName, WorktreesDir)
+	if resolved, err := filepath.EvalSymlinks(base); err == nil {
+		base = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+
+	// Walk up to the worktrees directory; a worktree root holds a .git file
+	for dir := cwd; strings.HasPrefix(dir, base+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			branch, err := filepath.Rel(base, dir)
+			if err != nil {
+				return ""
+			}
+			return filepath.ToSlash(branch)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/errors"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSparseWorkspace creates a workspace with one repo holding api, web and
+// docs directories, with a main worktree that checks out only sparse
+func setupSparseWorkspace(t *testing.T, sparse ...string) *Workspace {
+	t.Helper()
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	src := filepath.Join(t.TempDir(), "mono")
+	setupRealGitRepoForPull(t, src)
+	for _, dir := range []string{"api", "web", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(src, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, dir, "file.txt"), []byte(dir), 0644))
+	}
+	runGit(t, src, "add", ".")
+	runGit(t, src, "commit", "-m", "add dirs")
+	runGit(t, src, "branch", "-M", "main")
+	runGit(t, "", "clone", "--bare", src, ws.BareRepoPath("mono"))
+
+	require.NoError(t, git.WorktreeAdd(git.WorktreeAddOptions{
+		BareRepoPath: ws.BareRepoPath("mono"),
+		WorktreePath: ws.WorktreePath("mono", "main"),
+		Branch:       "main",
+		Sparse:       sparse,
+	}))
+
+	state := &State{Repositories: map[string]*Repository{
+		"mono": {Name: "mono", URL: "https://github.com/org/mono.git"},
+	}}
+	require.NoError(t, ws.SaveState(state))
+	return ws
+}
+
+func TestSparsePaths(t *testing.T) {
+	ws := setupSparseWorkspace(t, "api")
+
+	cfg, err := config.LoadLocal(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = []config.RepoConfig{
+		{URL: "https://github.com/org/mono.git", Name: "mono", Sparse: []string{"api", "libs/common"}},
+		{URL: "https://github.com/org/web.git", Name: "web"},
+	}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	assert.Equal(t, map[string][]string{"mono": {"api", "libs/common"}}, ws.SparsePaths())
+}
+
+func TestSparseAddAndRemove(t *testing.T) {
+	ws := setupSparseWorkspace(t, "api")
+	worktree := ws.WorktreePath("mono", "main")
+
+	result, err := ws.SparseAdd("mono", "", []string{"web"})
+	require.NoError(t, err)
+	assert.Equal(t, "main", result.Branch)
+	assert.Equal(t, []string{"api", "web"}, result.Paths)
+	assert.DirExists(t, filepath.Join(worktree, "web"))
+	assert.NoDirExists(t, filepath.Join(worktree, "docs"))
+
+	result, err = ws.SparseRemove("mono", "main", []string{"api"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web"}, result.Paths)
+	assert.NoDirExists(t, filepath.Join(worktree, "api"))
+	assert.FileExists(t, filepath.Join(worktree, "test.txt"))
+}
+
+func TestSparseRemove_NotCheckedOut(t *testing.T) {
+	ws := setupSparseWorkspace(t, "api")
+
+	_, err := ws.SparseRemove("mono", "main", []string{"docs"})
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Equal(t, errors.ErrCodeInvalidInput, faErr.Code)
+}
+
+func TestSparseAdd_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		sparse []string
+		repo   string
+		branch string
+		dirs   []string
+		code   string
+	}{
+		{"unknown repo", []string{"api"}, "other", "main", []string{"web"}, errors.ErrCodeRepoNotFound},
+		{"missing worktree", []string{"api"}, "mono", "feature", []string{"web"}, errors.ErrCodeWorktreeNotFound},
+		{"invalid dir", []string{"api"}, "mono", "main", []string{"../web"}, errors.ErrCodeInvalidInput},
+		{"wildcard", []string{"api"}, "mono", "main", []string{"web/*"}, errors.ErrCodeInvalidInput},
+		{"not sparse", nil, "mono", "main", []string{"web"}, errors.ErrCodeInvalidOperation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := setupSparseWorkspace(t, tt.sparse...)
+
+			_, err := ws.SparseAdd(tt.repo, tt.branch, tt.dirs)
+			var faErr *errors.Error
+			require.ErrorAs(t, err, &faErr)
+			assert.Equal(t, tt.code, faErr.Code)
+		})
+	}
+}
+
+func TestSparse_BranchFromCwd(t *testing.T) {
+	ws := setupSparseWorkspace(t, "api")
+	feature := ws.WorktreePath("mono", "feature/x")
+	require.NoError(t, git.WorktreeAddNew(ws.BareRepoPath("mono"), feature, "feature/x", "main", "api"))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+	require.NoError(t, os.Chdir(filepath.Join(feature, "api")))
+
+	result, err := ws.SparseAdd("mono", "", []string{"docs"})
+	require.NoError(t, err)
+	assert.Equal(t, "feature/x", result.Branch)
+	assert.DirExists(t, filepath.Join(feature, "docs"))
+	assert.NoDirExists(t, filepath.Join(ws.WorktreePath("mono", "main"), "docs"))
+}
+
+func TestDetectWorktreeStatus_Sparse(t *testing.T) {
+	ws := setupSparseWorkspace(t, "api")
+
+	detail := ws.detectWorktreeStatus(ws.WorktreePath("mono", "main"), false)
+	assert.Equal(t, "clean", detail.Status)
+	assert.True(t, detail.Sparse)
+	assert.Equal(t, []string{"api"}, detail.SparsePaths)
+}
@@ -2,21 +2,26 @@ package workspace
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/errors"
 )
 
 // State represents the workspace runtime state
 type State struct {
+	Version       int                    `json:"version"`
 	CurrentBranch string                 `json:"current_branch,omitempty"`
 	Repositories  map[string]*Repository `json:"repositories,omitempty"`
 }
 
 // createState creates the state.json file with initial empty state
 func (w *Workspace) createState() error {
-	// Initialize with empty JSON object
-	state := State{}
+	// Initialize with empty state at the current schema version
+	state := State{Version: StateVersion}
 
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
@@ -29,7 +34,7 @@ func (w *Workspace) createState() error {
 	}
 
 	statePath := w.StatePath()
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(statePath, data, 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write state file",
@@ -41,11 +46,60 @@ func (w *Workspace) createState() error {
 	return nil
 }
 
-// LoadState loads the workspace state
+// LoadState loads the workspace state, upgrading older schema versions in memory
 func (w *Workspace) LoadState() (*State, error) {
+	state, err := w.loadStateFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.migrateState(state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// loadStateFile loads the state file exactly as written. A missing or
+// corrupted state file is restored from the backup kept by SaveState, with a
+// warning, so a crash mid-write does not need 'fa init --force'.
+func (w *Workspace) loadStateFile() (*State, error) {
 	statePath := w.StatePath()
 
-	data, err := os.ReadFile(statePath)
+	state, err := readStateFile(statePath)
+	if err == nil {
+		return state, nil
+	}
+	if err.Code == errors.ErrCodePermissionDenied {
+		return nil, err
+	}
+
+	backupPath := atomicfile.BackupPath(statePath)
+	backup, backupErr := readStateFile(backupPath)
+	if backupErr != nil {
+		if backupErr.Code != errors.ErrCodeFileNotFound && err.Code != errors.ErrCodeFileNotFound {
+			err.Remediation = "The state file and its backup are both corrupted. Try 'fa init --force' to reinitialize"
+		}
+		return nil, err
+	}
+
+	if restoreErr := atomicfile.Restore(statePath); restoreErr != nil {
+		return nil, err
+	}
+
+	relPath, relErr := filepath.Rel(w.Path, backupPath)
+	if relErr != nil {
+		relPath = backupPath
+	}
+	err.Remediation = fmt.Sprintf("Restored the last good state from %s; changes from the last command may need to be redone", relPath)
+	fmt.Fprintf(os.Stderr, "Warning: %v\n  %s\n", err, err.Remediation)
+
+	return backup, nil
+}
+
+// readStateFile reads and parses a state file
+func readStateFile(path string) (*State, *errors.Error) {
+	data, err := dryrun.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, errors.Wrap(
@@ -89,7 +143,7 @@ func (w *Workspace) SaveState(state *State) error {
 	}
 
 	statePath := w.StatePath()
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(statePath, data, 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write state file",
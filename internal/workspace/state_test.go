@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/foundagent/foundagent/internal/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,6 +59,46 @@ func TestLoadState_CorruptedJSON(t *testing.T) {
 	assert.Contains(t, err.Error(), "Failed to parse state file")
 }
 
+func TestLoadState_RestoresBackup(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	// Two saves leave the first one as the backup
+	require.NoError(t, ws.SaveState(&State{CurrentBranch: "main"}))
+	require.NoError(t, ws.SaveState(&State{CurrentBranch: "feature"}))
+
+	// Simulate a write cut short by a crash
+	require.NoError(t, os.WriteFile(ws.StatePath(), []byte(`{"current_bra`), 0644))
+
+	state, err := ws.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, "main", state.CurrentBranch)
+
+	// The state file itself is repaired
+	data, err := os.ReadFile(ws.StatePath())
+	require.NoError(t, err)
+	var restored State
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, "main", restored.CurrentBranch)
+}
+
+func TestLoadState_CorruptedBackup(t *testing.T) {
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	require.NoError(t, os.WriteFile(ws.StatePath(), []byte("{ invalid json"), 0644))
+	require.NoError(t, os.WriteFile(ws.StatePath()+".bak", []byte("{ invalid json"), 0644))
+
+	_, err = ws.LoadState()
+
+	var faErr *errors.Error
+	require.ErrorAs(t, err, &faErr)
+	assert.Contains(t, faErr.Message, "Failed to parse state file")
+	assert.Contains(t, faErr.Remediation, "backup")
+}
+
 func TestSaveState(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/git"
 )
 
@@ -36,6 +37,9 @@ type WorktreeStatus struct {
 	IsCurrent      bool
 	ModifiedFiles  []string
 	UntrackedFiles []string
+	Sparse         bool
+	SparsePaths    []string              `json:",omitempty"`
+	SubmoduleDrift []git.SubmoduleStatus `json:",omitempty"` // Submodules not at the recorded commit
 }
 
 // StatusSummary provides aggregate counts and flags
@@ -52,17 +56,27 @@ type StatusSummary struct {
 
 // GetWorkspaceStatus collects complete workspace status
 func (w *Workspace) GetWorkspaceStatus(verbose bool) (*WorkspaceStatus, error) {
+	return w.GetWorkspaceStatusFor(config.Selector{}, verbose)
+}
+
+// GetWorkspaceStatusFor collects workspace status for the selected repos
+func (w *Workspace) GetWorkspaceStatusFor(sel config.Selector, verbose bool) (*WorkspaceStatus, error) {
 	// Load config and state
 	state, err := w.LoadState()
 	if err != nil {
 		return nil, err
 	}
 
+	selected, err := w.selectedSet(state, sel)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get repo statuses
-	repoStatuses := w.getRepoStatuses(state)
+	repoStatuses := w.getRepoStatuses(state, selected)
 
 	// Get worktree statuses
-	worktreeStatuses, err := w.getWorktreeStatuses(state, verbose)
+	worktreeStatuses, err := w.getWorktreeStatuses(state, selected, verbose)
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +93,8 @@ func (w *Workspace) GetWorkspaceStatus(verbose bool) (*WorkspaceStatus, error) {
 	}, nil
 }
 
-// getRepoStatuses checks clone status for all repos
-func (w *Workspace) getRepoStatuses(state *State) []RepoStatus {
+// getRepoStatuses checks clone status for all selected repos (nil selects all)
+func (w *Workspace) getRepoStatuses(state *State, selected map[string]bool) []RepoStatus {
 	statuses := make([]RepoStatus, 0)
 
 	if state.Repositories == nil {
@@ -88,6 +102,10 @@ func (w *Workspace) getRepoStatuses(state *State) []RepoStatus {
 	}
 
 	for repoName, repo := range state.Repositories {
+		if selected != nil && !selected[repoName] {
+			continue
+		}
+
 		// Check if bare clone exists
 		bareRepoPath := w.BareRepoPath(repoName)
 		isCloned := w.isBareCloneExists(bareRepoPath)
@@ -116,8 +134,8 @@ func (w *Workspace) isBareCloneExists(bareRepoPath string) bool {
 	return headErr == nil && configErr == nil
 }
 
-// getWorktreeStatuses collects status for all worktrees
-func (w *Workspace) getWorktreeStatuses(state *State, verbose bool) ([]WorktreeStatus, error) {
+// getWorktreeStatuses collects status for all worktrees of the selected repos (nil selects all)
+func (w *Workspace) getWorktreeStatuses(state *State, selected map[string]bool, verbose bool) ([]WorktreeStatus, error) {
 	allWorktrees, err := w.GetAllWorktrees()
 	if err != nil {
 		return nil, err
@@ -139,6 +157,9 @@ func (w *Workspace) getWorktreeStatuses(state *State, verbose bool) ([]WorktreeS
 	worktreesToProcess := make([]worktreeInfo, 0)
 
 	for repoName, branches := range allWorktrees {
+		if selected != nil && !selected[repoName] {
+			continue
+		}
 		for _, branch := range branches {
 			worktreePath := w.WorktreePath(repoName, branch)
 			worktreesToProcess = append(worktreesToProcess, worktreeInfo{
@@ -149,6 +170,9 @@ func (w *Workspace) getWorktreeStatuses(state *State, verbose bool) ([]WorktreeS
 		}
 	}
 
+	// Submodule drift is only reported for repos that keep submodules updated
+	repoConfigs := w.repoConfigs()
+
 	// Process worktrees in parallel
 	for _, wt := range worktreesToProcess {
 		wg.Add(1)
@@ -158,6 +182,11 @@ func (w *Workspace) getWorktreeStatuses(state *State, verbose bool) ([]WorktreeS
 			status := w.detectWorktreeStatus(path, verbose)
 			isCurrent := cwd != "" && strings.HasPrefix(cwd, path)
 
+			var drift []git.SubmoduleStatus
+			if repoConfigs[repo].UpdatesSubmodules() {
+				drift = submoduleDrift(path)
+			}
+
 			mu.Lock()
 			statuses = append(statuses, WorktreeStatus{
 				Branch:         branch,
@@ -167,6 +196,9 @@ func (w *Workspace) getWorktreeStatuses(state *State, verbose bool) ([]WorktreeS
 				IsCurrent:      isCurrent,
 				ModifiedFiles:  status.ModifiedFiles,
 				UntrackedFiles: status.UntrackedFiles,
+				Sparse:         status.Sparse,
+				SparsePaths:    status.SparsePaths,
+				SubmoduleDrift: drift,
 			})
 			mu.Unlock()
 		}(wt.repo, wt.branch, wt.path)
@@ -182,6 +214,8 @@ type worktreeStatusDetail struct {
 	Status         string
 	ModifiedFiles  []string
 	UntrackedFiles []string
+	Sparse         bool
+	SparsePaths    []string
 }
 
 // detectWorktreeStatus checks git status for a single worktree
@@ -213,11 +247,18 @@ func (w *Workspace) detectWorktreeStatus(worktreePath string, verbose bool) work
 		}
 	}
 
-	return worktreeStatusDetail{
+	detail := worktreeStatusDetail{
 		Status:         status,
 		ModifiedFiles:  modifiedFiles,
 		UntrackedFiles: untrackedFiles,
 	}
+
+	if git.IsSparse(worktreePath) {
+		detail.Sparse = true
+		detail.SparsePaths, _ = git.SparseCheckoutList(worktreePath)
+	}
+
+	return detail
 }
 
 // getModifiedFiles returns list of modified files (for verbose mode)
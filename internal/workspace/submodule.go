@@ -0,0 +1,36 @@
+package workspace
+
+import (
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+)
+
+// updateSubmodules checks out the submodules of a worktree at the commits it
+// records, if the repo's config asks for it
+func updateSubmodules(repo config.RepoConfig, worktreePath string) error {
+	if !repo.UpdatesSubmodules() || !git.HasSubmodules(worktreePath) {
+		return nil
+	}
+	return git.SubmoduleUpdate(worktreePath)
+}
+
+// submoduleDrift returns the submodules of a worktree that are not checked out
+// at the commit the worktree records
+func submoduleDrift(worktreePath string) []git.SubmoduleStatus {
+	if !git.HasSubmodules(worktreePath) {
+		return nil
+	}
+
+	statuses, err := git.SubmoduleStatuses(worktreePath)
+	if err != nil {
+		return nil
+	}
+
+	var drift []git.SubmoduleStatus
+	for _, status := range statuses {
+		if !status.InSync() {
+			drift = append(drift, status)
+		}
+	}
+	return drift
+}
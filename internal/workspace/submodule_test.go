@@ -0,0 +1,111 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSubmoduleWorkspace creates a workspace with an app repo whose main
+// worktree has an uninitialised submodule at lib. mode is written to the
+// repo's submodules setting. Returns the app and lib source repos.
+func setupSubmoduleWorkspace(t *testing.T, mode string) (*Workspace, string, string) {
+	t.Helper()
+
+	// Local submodule URLs need the file protocol
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	ws, err := New("test-ws", t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	lib := filepath.Join(t.TempDir(), "lib")
+	setupRealGitRepoForPull(t, lib)
+
+	app := filepath.Join(t.TempDir(), "app")
+	setupRealGitRepoForPull(t, app)
+	runGit(t, app, "branch", "-M", "main")
+	runGit(t, app, "submodule", "add", lib, "lib")
+	runGit(t, app, "commit", "-m", "Add lib")
+
+	runGit(t, "", "clone", "--bare", app, ws.BareRepoPath("app"))
+	runGit(t, "", "--git-dir="+ws.BareRepoPath("app"), "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	runGit(t, "", "--git-dir="+ws.BareRepoPath("app"), "fetch", "-q", "origin")
+	runGit(t, "", "--git-dir="+ws.BareRepoPath("app"), "worktree", "add", ws.WorktreePath("app", "main"), "main")
+	runGit(t, ws.WorktreePath("app", "main"), "branch", "--set-upstream-to=origin/main")
+
+	require.NoError(t, ws.SaveState(&State{Repositories: map[string]*Repository{
+		"app": {Name: "app", URL: "https://github.com/org/app.git", DefaultBranch: "main"},
+	}}))
+
+	cfg, err := config.LoadLocal(ws.Path)
+	require.NoError(t, err)
+	cfg.Repos = []config.RepoConfig{{URL: "https://github.com/org/app.git", Name: "app", Submodules: mode}}
+	require.NoError(t, config.Save(ws.Path, cfg))
+
+	return ws, app, lib
+}
+
+func TestSetupWorktree_Submodules(t *testing.T) {
+	ws, _, _ := setupSubmoduleWorkspace(t, config.SubmodulesRecursive)
+	worktree := ws.WorktreePath("app", "main")
+
+	require.NoError(t, ws.SetupWorktree("app", worktree))
+
+	assert.FileExists(t, filepath.Join(worktree, "lib", "test.txt"))
+	assert.Empty(t, submoduleDrift(worktree))
+}
+
+func TestSetupWorktree_SubmodulesNone(t *testing.T) {
+	ws, _, _ := setupSubmoduleWorkspace(t, config.SubmodulesNone)
+	worktree := ws.WorktreePath("app", "main")
+
+	require.NoError(t, ws.SetupWorktree("app", worktree))
+
+	assert.NoFileExists(t, filepath.Join(worktree, "lib", "test.txt"))
+}
+
+func TestStatus_SubmoduleDrift(t *testing.T) {
+	ws, _, _ := setupSubmoduleWorkspace(t, config.SubmodulesRecursive)
+
+	status, err := ws.GetWorkspaceStatus(false)
+	require.NoError(t, err)
+	require.Len(t, status.Worktrees, 1)
+	require.Len(t, status.Worktrees[0].SubmoduleDrift, 1)
+	assert.Equal(t, "lib", status.Worktrees[0].SubmoduleDrift[0].Path)
+	assert.Equal(t, git.SubmoduleUninitialized, status.Worktrees[0].SubmoduleDrift[0].State)
+
+	require.NoError(t, ws.SetupWorktree("app", ws.WorktreePath("app", "main")))
+
+	status, err = ws.GetWorkspaceStatus(false)
+	require.NoError(t, err)
+	assert.Empty(t, status.Worktrees[0].SubmoduleDrift)
+}
+
+func TestPullWorktrees_UpdatesSubmodules(t *testing.T) {
+	ws, app, lib := setupSubmoduleWorkspace(t, config.SubmodulesRecursive)
+	worktree := ws.WorktreePath("app", "main")
+	require.NoError(t, ws.SetupWorktree("app", worktree))
+
+	// Advance lib and record the new commit in app
+	require.NoError(t, os.WriteFile(filepath.Join(lib, "new.txt"), []byte("new"), 0644))
+	runGit(t, lib, "add", ".")
+	runGit(t, lib, "commit", "-m", "new")
+	runGit(t, filepath.Join(app, "lib"), "pull", "-q", "origin", "HEAD")
+	runGit(t, app, "commit", "-am", "Bump lib")
+
+	results, err := ws.PullWorktrees(t.Context(), "main", SyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusUpdated, results[0].Status, results[0].Error)
+
+	assert.FileExists(t, filepath.Join(worktree, "lib", "new.txt"))
+	assert.Empty(t, submoduleDrift(worktree))
+}
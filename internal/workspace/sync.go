@@ -1,11 +1,14 @@
 package workspace
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/git"
+	"github.com/foundagent/foundagent/internal/progress"
 )
 
 // Sync status constants
@@ -35,6 +38,8 @@ type SyncResult struct {
 	CommitsBehind int
 	CommitsAhead  int
 	Pushed        bool
+	Retries       []git.Retry // Retries after transient network failures
+	Warning       string      // A problem that did not stop the repo from syncing
 }
 
 // SyncSummary aggregates results across all repos
@@ -47,8 +52,21 @@ type SyncSummary struct {
 	Pushed  int
 }
 
+// SyncOptions represents options for syncing repos
+type SyncOptions struct {
+	Selection config.Selector    // Limit to selected repos (empty = all)
+	Stash     bool               // Stash uncommitted changes before pull
+	Verbose   bool               // Show detailed output
+	Progress  *progress.Renderer // Shows each repo's progress (nil = none)
+}
+
 // SyncAllRepos fetches from all repos in parallel
 func (w *Workspace) SyncAllRepos(verbose bool) ([]SyncResult, error) {
+	return w.FetchRepos(context.Background(), SyncOptions{Verbose: verbose})
+}
+
+// FetchRepos fetches from the selected repos in parallel
+func (w *Workspace) FetchRepos(ctx context.Context, opts SyncOptions) ([]SyncResult, error) {
 	// Load state to get repo list
 	state, err := w.LoadState()
 	if err != nil {
@@ -59,16 +77,30 @@ func (w *Workspace) SyncAllRepos(verbose bool) ([]SyncResult, error) {
 		return []SyncResult{}, nil
 	}
 
-	// Collect repo names
-	var repoNames []string
-	for name := range state.Repositories {
-		repoNames = append(repoNames, name)
+	// Collect selected repo names
+	repoNames, err := w.SelectRepoNames(state, opts.Selection)
+	if err != nil {
+		return nil, err
 	}
 
 	// Execute fetch in parallel
-	parallelResults := ExecuteParallel(repoNames, func(repoName string) error {
+	repoConfigs := w.repoConfigs()
+	retries := newRetryLog()
+	parallelResults := ExecuteParallel(ctx, w.Jobs(), repoNames, func(ctx context.Context, repoName string) error {
+		task := opts.Progress.Start(repoName, "fetching")
+		record := retries.recorder(repoName)
+		onRetry := func(r git.Retry) {
+			record(r)
+			task.Log("%s", r)
+		}
+
 		bareRepoPath := filepath.Join(w.Path, ReposDir, repoName, BareDir)
-		return git.Fetch(bareRepoPath)
+		err := configureRemotes(bareRepoPath, repoConfigs[repoName])
+		if err == nil {
+			err = fetchRemotes(bareRepoPath, repoConfigs[repoName], state.Repositories[repoName], onRetry, task.Writer())
+		}
+		finishTask(task, err)
+		return err
 	})
 
 	// Convert to SyncResult
@@ -88,18 +120,54 @@ func (w *Workspace) SyncAllRepos(verbose bool) ([]SyncResult, error) {
 				Status:   SyncStatusSynced,
 			}
 		}
+		results[i].Retries = retries.get(pr.RepoName)
 	}
 
 	return results, nil
 }
 
+// fetchOptions returns the fetch options that keep a clone in the shape its
+// config asks for. Depth and filter only apply to clones that are already
+// shallow or partial, so a config change never truncates a full clone; doctor
+// reports such mismatches instead.
+func fetchOptions(bareRepoPath string, repoConfig config.RepoConfig, repo *Repository) git.FetchOptions {
+	var opts git.FetchOptions
+
+	if repoConfig.CloneDepth > 0 && git.IsShallow(bareRepoPath) {
+		opts.Depth = repoConfig.CloneDepth
+	}
+	if repoConfig.Filter != "" && git.PartialCloneFilter(bareRepoPath) != "" {
+		opts.Filter = repoConfig.Filter
+	}
+	if repoConfig.SingleBranch {
+		opts.Branch = repoConfig.DefaultBranch
+		if repo != nil && repo.DefaultBranch != "" {
+			opts.Branch = repo.DefaultBranch
+		}
+	}
+
+	return opts
+}
+
 // PullAllWorktrees pulls all worktrees for a branch
 func (w *Workspace) PullAllWorktrees(branch string, stash bool, verbose bool) ([]SyncResult, error) {
-	// First fetch
-	_, err := w.SyncAllRepos(verbose)
+	return w.PullWorktrees(context.Background(), branch, SyncOptions{Stash: stash, Verbose: verbose})
+}
+
+// PullWorktrees pulls the selected repos' worktrees for a branch
+func (w *Workspace) PullWorktrees(ctx context.Context, branch string, opts SyncOptions) ([]SyncResult, error) {
+	stash := opts.Stash
+
+	// First fetch. A repo's fetch retries go into its result, and a repo that
+	// cannot be fetched is not pulled.
+	fetched, err := w.FetchRepos(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
+	fetches := make(map[string]SyncResult, len(fetched))
+	for _, f := range fetched {
+		fetches[f.RepoName] = f
+	}
 
 	// Load state to get repo list
 	state, err := w.LoadState()
@@ -107,15 +175,30 @@ func (w *Workspace) PullAllWorktrees(branch string, stash bool, verbose bool) ([
 		return nil, err
 	}
 
+	repoNames, err := w.SelectRepoNames(state, opts.Selection)
+	if err != nil {
+		return nil, err
+	}
+
 	// Now pull each worktree for the branch
 	results := make([]SyncResult, 0)
+	repoConfigs := w.repoConfigs()
 
-	for repoName := range state.Repositories {
+	for _, repoName := range repoNames {
 		worktreePath := filepath.Join(w.Path, ReposDir, repoName, WorktreesDir, branch)
 
 		// Check if worktree exists
 		result := SyncResult{
 			RepoName: repoName,
+			Retries:  fetches[repoName].Retries,
+		}
+
+		// Leave the remaining worktrees alone once interrupted
+		if ctx.Err() != nil {
+			result.Status = SyncStatusSkipped
+			result.Error = context.Cause(ctx)
+			results = append(results, result)
+			continue
 		}
 
 		// Check for detached HEAD
@@ -128,6 +211,14 @@ func (w *Workspace) PullAllWorktrees(branch string, stash bool, verbose bool) ([
 			continue
 		}
 
+		// Pulling after a failed fetch would merge stale remote refs
+		if fetchErr := fetches[repoName].Error; fetchErr != nil {
+			result.Status = SyncStatusFailed
+			result.Error = fetchErr
+			results = append(results, result)
+			continue
+		}
+
 		if isDetached {
 			result.Status = SyncStatusSkipped
 			result.Error = fmt.Errorf("detached HEAD - cannot pull")
@@ -164,7 +255,9 @@ func (w *Workspace) PullAllWorktrees(branch string, stash bool, verbose bool) ([
 		}
 
 		// Pull
+		task := opts.Progress.Start(repoName, "pulling")
 		pullErr := git.Pull(worktreePath)
+		finishTask(task, pullErr)
 
 		// Pop stash if we stashed
 		if stash && hasChanges {
@@ -181,50 +274,83 @@ func (w *Workspace) PullAllWorktrees(branch string, stash bool, verbose bool) ([
 			result.Error = pullErr
 		} else {
 			result.Status = SyncStatusUpdated
+			// The pulled worktree is usable without its submodules or LFS files
+			if err := setupWorktree(repoConfigs[repoName], worktreePath); err != nil {
+				result.Warning = fmt.Sprintf("failed to update submodules or LFS files: %v", err)
+			}
 		}
 
 		results = append(results, result)
 	}
 
-	// Merge fetch and pull results
 	return results, nil
 }
 
 // PushAllRepos pushes all repos with unpushed commits
 func (w *Workspace) PushAllRepos(verbose bool) ([]SyncResult, error) {
+	return w.PushRepos(context.Background(), SyncOptions{Verbose: verbose})
+}
+
+// PushRepos pushes the selected repos with unpushed commits
+func (w *Workspace) PushRepos(ctx context.Context, opts SyncOptions) ([]SyncResult, error) {
 	// Load state
 	state, err := w.LoadState()
 	if err != nil {
 		return nil, err
 	}
 
+	repoNames, err := w.SelectRepoNames(state, opts.Selection)
+	if err != nil {
+		return nil, err
+	}
+
+	allWorktrees, err := w.GetAllWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]SyncResult, 0)
+	repoConfigs := w.repoConfigs()
 
 	// For each repo, check all worktrees for unpushed commits
-	for repoName := range state.Repositories {
-		worktreesDir := filepath.Join(w.Path, ReposDir, WorktreesDir, repoName)
-
-		// List all branches (subdirectories)
-		entries, err := filepath.Glob(filepath.Join(worktreesDir, "*"))
-		if err != nil {
+	for _, repoName := range repoNames {
+		if ctx.Err() != nil {
+			results = append(results, SyncResult{
+				RepoName: repoName,
+				Status:   SyncStatusSkipped,
+				Error:    context.Cause(ctx),
+			})
 			continue
 		}
 
+		pushRemote := repoConfigs[repoName].PushTarget()
+
 		pushed := false
 		var pushErr error
+		var retries []git.Retry
+		onRetry := func(r git.Retry) { retries = append(retries, r) }
 
-		for _, entry := range entries {
-			branch := filepath.Base(entry)
-			worktreePath := entry
+		for _, branch := range allWorktrees[repoName] {
+			worktreePath := w.WorktreePath(repoName, branch)
 
 			// Check ahead/behind
 			ahead, _, err := git.GetAheadBehindCount(worktreePath, branch)
+			if pushRemote != "" {
+				ahead, err = git.GetUnpushedCountTo(worktreePath, pushRemote)
+			}
 			if err != nil || ahead == 0 {
 				continue // No unpushed commits
 			}
 
 			// Push this worktree
-			if err := git.Push(worktreePath); err != nil {
+			task := opts.Progress.Start(repoName, "pushing "+branch)
+			err = git.PushBranch(worktreePath, git.PushOptions{
+				Remote:   pushRemote,
+				OnRetry:  func(r git.Retry) { onRetry(r); task.Log("%s", r) },
+				Progress: task.Writer(),
+			})
+			finishTask(task, err)
+			if err != nil {
 				pushErr = err
 				break
 			}
@@ -234,6 +360,7 @@ func (w *Workspace) PushAllRepos(verbose bool) ([]SyncResult, error) {
 		result := SyncResult{
 			RepoName: repoName,
 			Pushed:   pushed,
+			Retries:  retries,
 		}
 
 		if pushErr != nil {
@@ -251,6 +378,15 @@ func (w *Workspace) PushAllRepos(verbose bool) ([]SyncResult, error) {
 	return results, nil
 }
 
+// finishTask marks a progress task as done or failed
+func finishTask(task *progress.Task, err error) {
+	if err != nil {
+		task.Fail()
+	} else {
+		task.Done()
+	}
+}
+
 // CalculateSummary aggregates sync results into a summary
 func CalculateSummary(results []SyncResult) SyncSummary {
 	summary := SyncSummary{
@@ -292,6 +428,9 @@ func FormatSyncResults(results []SyncResult, operation string) string {
 		if r.Error != nil {
 			output.WriteString(fmt.Sprintf(" (%s)", r.Error.Error()))
 		}
+		if r.Warning != "" {
+			output.WriteString(fmt.Sprintf(" (warning: %s)", r.Warning))
+		}
 
 		output.WriteString("\n")
 	}
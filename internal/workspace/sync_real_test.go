@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/foundagent/foundagent/internal/config"
+	"github.com/foundagent/foundagent/internal/git"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,14 +42,20 @@ func setupRealGitRepoForPull(t *testing.T, path string) {
 	require.NoError(t, cmd.Run())
 }
 
-// setupBareRepoForPull creates a bare git repository
+// setupBareRepoForPull creates a bare git repository whose origin is itself,
+// so the fetch before a pull succeeds
 func setupBareRepoForPull(t *testing.T, path string) {
 	t.Helper()
 	require.NoError(t, os.MkdirAll(path, 0755))
 
-	cmd := exec.Command("git", "init", "--bare")
-	cmd.Dir = path
-	require.NoError(t, cmd.Run())
+	for _, args := range [][]string{
+		{"git", "init", "--bare"},
+		{"git", "remote", "add", "origin", path},
+	} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = path
+		require.NoError(t, cmd.Run())
+	}
 }
 
 // TestPullAllWorktrees_RealDetachedHead tests detached HEAD with real git
@@ -298,3 +306,24 @@ func TestDetectWorktreeStatus_RealClean(t *testing.T) {
 	assert.Empty(t, status.ModifiedFiles)
 	assert.Empty(t, status.UntrackedFiles)
 }
+
+func TestFetchOptions(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	setupRealGitRepoForPull(t, src)
+
+	full := filepath.Join(t.TempDir(), "full.git")
+	runGit(t, "", "clone", "--bare", src, full)
+	shallow := filepath.Join(t.TempDir(), "shallow.git")
+	runGit(t, "", "clone", "--bare", "--depth=1", "--filter=blob:none", "file://"+src, shallow)
+
+	repoConfig := config.RepoConfig{CloneDepth: 1, Filter: "blob:none", SingleBranch: true, DefaultBranch: "main"}
+
+	opts := fetchOptions(shallow, repoConfig, &Repository{DefaultBranch: "trunk"})
+	assert.Equal(t, git.FetchOptions{Depth: 1, Filter: "blob:none", Branch: "trunk"}, opts)
+
+	// A full clone is never truncated by a later config change
+	opts = fetchOptions(full, repoConfig, nil)
+	assert.Equal(t, git.FetchOptions{Branch: "main"}, opts)
+
+	assert.Equal(t, git.FetchOptions{}, fetchOptions(shallow, config.RepoConfig{}, nil))
+}
@@ -124,6 +124,14 @@ func TestFormatSyncResults(t *testing.T) {
 			operation: "sync",
 			contains:  []string{"⊘ repo1: skipped"},
 		},
+		{
+			name: "updated with warning",
+			results: []SyncResult{
+				{RepoName: "repo1", Status: "updated", Warning: "failed to update submodules or LFS files"},
+			},
+			operation: "pull",
+			contains:  []string{"✓ repo1: updated (warning: failed to update submodules or LFS files)"},
+		},
 		{
 			name: "mixed results",
 			results: []SyncResult{
@@ -1,6 +1,7 @@
 package workspace
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -172,14 +173,14 @@ func TestExecuteParallel(t *testing.T) {
 	callCount := 0
 	var mu sync.Mutex
 
-	fn := func(repo string) error {
+	fn := func(ctx context.Context, repo string) error {
 		mu.Lock()
 		callCount++
 		mu.Unlock()
 		return nil
 	}
 
-	results := ExecuteParallel(repos, fn)
+	results := ExecuteParallel(t.Context(), 0, repos, fn)
 
 	if len(results) != 3 {
 		t.Errorf("ExecuteParallel() returned %d results, want 3", len(results))
@@ -202,14 +203,14 @@ func TestExecuteParallel(t *testing.T) {
 func TestExecuteParallel_WithErrors(t *testing.T) {
 	repos := []string{"repo1", "repo2", "repo3"}
 
-	fn := func(repo string) error {
+	fn := func(ctx context.Context, repo string) error {
 		if repo == "repo2" {
 			return errors.New("test error")
 		}
 		return nil
 	}
 
-	results := ExecuteParallel(repos, fn)
+	results := ExecuteParallel(t.Context(), 0, repos, fn)
 
 	if len(results) != 3 {
 		t.Errorf("ExecuteParallel() returned %d results, want 3", len(results))
@@ -230,11 +231,11 @@ func TestExecuteParallel_WithErrors(t *testing.T) {
 }
 
 func TestExecuteParallel_Empty(t *testing.T) {
-	fn := func(repo string) error {
+	fn := func(ctx context.Context, repo string) error {
 		return nil
 	}
 
-	results := ExecuteParallel([]string{}, fn)
+	results := ExecuteParallel(t.Context(), 0, []string{}, fn)
 
 	if len(results) != 0 {
 		t.Errorf("ExecuteParallel([]) returned %d results, want 0", len(results))
@@ -276,6 +277,28 @@ repos: []
 	}
 }
 
+func TestDiscover_OtherFormats(t *testing.T) {
+	for _, name := range []string{".foundagent.yml", ".foundagent.toml", ".foundagent.json"} {
+		t.Run(name, func(t *testing.T) {
+			wsPath := filepath.Join(t.TempDir(), "test-workspace")
+			subdir := filepath.Join(wsPath, "repos")
+			os.MkdirAll(subdir, 0755)
+			os.WriteFile(filepath.Join(wsPath, name), []byte("{}"), 0644)
+
+			ws, err := Discover(subdir)
+			if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+			if ws.Path != wsPath {
+				t.Errorf("Workspace path = %q, want %q", ws.Path, wsPath)
+			}
+			if ws.ConfigPath() != filepath.Join(wsPath, name) {
+				t.Errorf("ConfigPath() = %q, want %s", ws.ConfigPath(), name)
+			}
+		})
+	}
+}
+
 func TestDiscover_NotFound(t *testing.T) {
 	tmpDir := t.TempDir()
 	notWorkspace := filepath.Join(tmpDir, "not-a-workspace")
@@ -332,13 +355,13 @@ func TestParallelResult_Fields(t *testing.T) {
 func TestExecuteParallel_OrderPreserved(t *testing.T) {
 	repos := []string{"a", "b", "c", "d", "e"}
 
-	fn := func(repo string) error {
+	fn := func(ctx context.Context, repo string) error {
 		// Sleep varying amounts to test that results are in correct order
 		// despite async execution
 		return nil
 	}
 
-	results := ExecuteParallel(repos, fn)
+	results := ExecuteParallel(t.Context(), 0, repos, fn)
 
 	for i, result := range results {
 		if result.RepoName != repos[i] {
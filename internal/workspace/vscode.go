@@ -2,10 +2,14 @@ package workspace
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/foundagent/foundagent/internal/atomicfile"
+	"github.com/foundagent/foundagent/internal/dryrun"
 	"github.com/foundagent/foundagent/internal/errors"
 )
 
@@ -38,7 +42,7 @@ func (w *Workspace) createVSCodeWorkspace() error {
 	}
 
 	workspacePath := w.VSCodeWorkspacePath()
-	if err := os.WriteFile(workspacePath, data, 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(workspacePath, data, 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write VS Code workspace file",
@@ -54,7 +58,7 @@ func (w *Workspace) createVSCodeWorkspace() error {
 func (w *Workspace) LoadVSCodeWorkspace() (*VSCodeWorkspace, error) {
 	workspacePath := w.VSCodeWorkspacePath()
 
-	data, err := os.ReadFile(workspacePath)
+	data, err := dryrun.ReadFile(workspacePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, errors.Wrap(
@@ -98,7 +102,7 @@ func (w *Workspace) SaveVSCodeWorkspace(workspace *VSCodeWorkspace) error {
 	}
 
 	workspacePath := w.VSCodeWorkspacePath()
-	if err := os.WriteFile(workspacePath, data, 0644); err != nil {
+	if err := atomicfile.WriteWithBackup(workspacePath, data, 0644); err != nil {
 		return errors.Wrap(
 			errors.ErrCodePermissionDenied,
 			"Failed to write VS Code workspace file",
@@ -313,13 +317,28 @@ func (w *Workspace) ReplaceWorktreeFolders(targetBranch string) error {
 		}
 	}
 
-	// Add new worktree folders for target branch
-	for repoName := range state.Repositories {
+	// Add new worktree folders for target branch. A branch may only exist in
+	// some repos; the others keep showing their default branch worktree,
+	// listed after the target branch's so it stays the current branch.
+	var fallbacks []VSCodeFolder
+	for _, repoName := range slices.Sorted(maps.Keys(state.Repositories)) {
 		worktreePath := w.WorktreePath(repoName, targetBranch)
-
-		// Check if worktree exists
-		if _, err := os.Stat(worktreePath); err != nil {
-			continue // Skip if worktree doesn't exist
+		folders := &newFolders
+
+		// Check if worktree exists. A dry run has only planned the
+		// worktrees it creates, and recorded them in the state.
+		_, err := os.Stat(worktreePath)
+		planned := dryrun.Enabled() && slices.Contains(state.Repositories[repoName].Worktrees, targetBranch)
+		if err != nil && !planned {
+			defaultBranch := state.Repositories[repoName].DefaultBranch
+			if defaultBranch == "" || defaultBranch == targetBranch {
+				continue
+			}
+			worktreePath = w.WorktreePath(repoName, defaultBranch)
+			if _, err := os.Stat(worktreePath); err != nil {
+				continue // Skip if neither worktree exists
+			}
+			folders = &fallbacks
 		}
 
 		// Make path relative
@@ -328,9 +347,11 @@ func (w *Workspace) ReplaceWorktreeFolders(targetBranch string) error {
 			relPath = worktreePath
 		}
 
-		newFolders = append(newFolders, VSCodeFolder{Path: relPath})
+		*folders = append(*folders, VSCodeFolder{Path: relPath})
 	}
 
+	newFolders = append(newFolders, fallbacks...)
+
 	workspace.Folders = newFolders
 	return w.SaveVSCodeWorkspace(workspace)
 }
@@ -204,6 +204,40 @@ func TestReplaceWorktreeFolders_MultipleRepos(t *testing.T) {
 	assert.True(t, foundRepo2, "Should have repo2 worktree")
 }
 
+func TestReplaceWorktreeFolders_PartialBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws, err := New("test-ws", tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, ws.Create(false))
+
+	// The feature branch only exists in web; api falls back to main
+	for _, name := range []string{"api", "web"} {
+		require.NoError(t, ws.AddRepository(&Repository{
+			Name:          name,
+			URL:           "https://github.com/test/" + name + ".git",
+			DefaultBranch: "main",
+			BareRepoPath:  ws.BareRepoPath(name),
+		}))
+		require.NoError(t, os.MkdirAll(ws.WorktreePath(name, "main"), 0755))
+	}
+	require.NoError(t, os.MkdirAll(ws.WorktreePath("web", "feature"), 0755))
+
+	require.NoError(t, ws.ReplaceWorktreeFolders("feature"))
+
+	reloaded, err := ws.LoadVSCodeWorkspace()
+	require.NoError(t, err)
+
+	var paths []string
+	for _, folder := range reloaded.Folders {
+		paths = append(paths, filepath.ToSlash(folder.Path))
+	}
+	assert.Equal(t, []string{".", "repos/web/worktrees/feature", "repos/api/worktrees/main"}, paths)
+
+	branch, err := ws.GetCurrentBranchFromWorkspace()
+	require.NoError(t, err)
+	assert.Equal(t, "feature", branch)
+}
+
 func TestLoadVSCodeWorkspace_InvalidJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	ws, err := New("test-ws", tmpDir)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/foundagent/foundagent/internal/config"
 	"github.com/foundagent/foundagent/internal/errors"
 )
 
@@ -12,12 +13,25 @@ const (
 	// FoundagentDir is the directory for machine-managed state
 	FoundagentDir = ".foundagent"
 
-	// ConfigFileName is the name of the config file
+	// ConfigFileName is the name of the config file created for new
+	// workspaces. Existing workspaces may use any name in config.ConfigFileNames.
 	ConfigFileName = ".foundagent.yaml"
 
 	// StateFileName is the name of the state file
 	StateFileName = "state.json"
 
+	// OverlaysDir is the subdirectory of FoundagentDir holding the files
+	// overlaid onto new worktrees
+	OverlaysDir = "overlays"
+
+	// LogsDir is the subdirectory of FoundagentDir holding the traces of
+	// recent runs
+	LogsDir = "logs"
+
+	// JournalDir is the subdirectory of FoundagentDir holding the records
+	// 'fa undo' uses to reverse recent commands
+	JournalDir = "journal"
+
 	// ReposDir is the directory for repository storage
 	ReposDir = "repos"
 
@@ -147,8 +161,15 @@ func (w *Workspace) createReposStructure(force bool) error {
 	return nil
 }
 
-// ConfigPath returns the path to the config file
+// ConfigPath returns the path to the config file, in whichever format the
+// workspace uses
 func (w *Workspace) ConfigPath() string {
+	for _, name := range config.ConfigFileNames {
+		path := filepath.Join(w.Path, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
 	return filepath.Join(w.Path, ConfigFileName)
 }
 
@@ -157,6 +178,17 @@ func (w *Workspace) StatePath() string {
 	return filepath.Join(w.Path, FoundagentDir, StateFileName)
 }
 
+// LogsPath returns the path to the directory of recent runs' traces
+func (w *Workspace) LogsPath() string {
+	return filepath.Join(w.Path, FoundagentDir, LogsDir)
+}
+
+// JournalPath returns the path to the directory of recent commands' records
+// for 'fa undo'
+func (w *Workspace) JournalPath() string {
+	return filepath.Join(w.Path, FoundagentDir, JournalDir)
+}
+
 // VSCodeWorkspacePath returns the path to the VS Code workspace file
 func (w *Workspace) VSCodeWorkspacePath() string {
 	return filepath.Join(w.Path, w.Name+".code-workspace")